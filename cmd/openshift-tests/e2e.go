@@ -1,6 +1,7 @@
 package main
 
 import (
+	"fmt"
 	"strings"
 	"time"
 
@@ -51,7 +52,7 @@ var staticSuites = testSuites{
 				return strings.Contains(name, "[Suite:openshift/conformance/")
 			},
 			Parallelism:         30,
-			SyntheticEventTests: ginkgo.JUnitForEventsFunc(synthetictests.StableSystemEventInvariants),
+			SyntheticEventTests: synthetictests.StableSystemEventInvariants,
 		},
 		PreSuite: suiteWithProviderPreSuite,
 	},
@@ -69,7 +70,7 @@ var staticSuites = testSuites{
 			},
 			Parallelism:          30,
 			MaximumAllowedFlakes: 15,
-			SyntheticEventTests:  ginkgo.JUnitForEventsFunc(synthetictests.StableSystemEventInvariants),
+			SyntheticEventTests:  synthetictests.StableSystemEventInvariants,
 		},
 		PreSuite: suiteWithProviderPreSuite,
 	},
@@ -85,7 +86,7 @@ var staticSuites = testSuites{
 				}
 				return strings.Contains(name, "[Suite:openshift/conformance/serial") || isStandardEarlyOrLateTest(name)
 			},
-			SyntheticEventTests: ginkgo.JUnitForEventsFunc(synthetictests.StableSystemEventInvariants),
+			SyntheticEventTests: synthetictests.StableSystemEventInvariants,
 		},
 		PreSuite: suiteWithProviderPreSuite,
 	},
@@ -108,7 +109,7 @@ var staticSuites = testSuites{
 			},
 			// Duration of the quorum restore test exceeds 60 minutes.
 			TestTimeout:         90 * time.Minute,
-			SyntheticEventTests: ginkgo.JUnitForEventsFunc(synthetictests.SystemEventInvariants),
+			SyntheticEventTests: synthetictests.SystemEventInvariants,
 		},
 		PreSuite: suiteWithProviderPreSuite,
 	},
@@ -125,7 +126,7 @@ var staticSuites = testSuites{
 				return strings.Contains(name, "[Suite:k8s]") && strings.Contains(name, "[Conformance]")
 			},
 			Parallelism:         30,
-			SyntheticEventTests: ginkgo.JUnitForEventsFunc(synthetictests.StableSystemEventInvariants),
+			SyntheticEventTests: synthetictests.StableSystemEventInvariants,
 		},
 		PreSuite: suiteWithProviderPreSuite,
 	},
@@ -146,7 +147,7 @@ var staticSuites = testSuites{
 			MaximumAllowedFlakes: 3,
 			// Jenkins tests can take a really long time
 			TestTimeout:         60 * time.Minute,
-			SyntheticEventTests: ginkgo.JUnitForEventsFunc(synthetictests.StableSystemEventInvariants),
+			SyntheticEventTests: synthetictests.StableSystemEventInvariants,
 		},
 		PreSuite: suiteWithProviderPreSuite,
 	},
@@ -163,7 +164,7 @@ var staticSuites = testSuites{
 				return strings.Contains(name, "[Feature:Templates]") || isStandardEarlyOrLateTest(name)
 			},
 			Parallelism:         1,
-			SyntheticEventTests: ginkgo.JUnitForEventsFunc(synthetictests.StableSystemEventInvariants),
+			SyntheticEventTests: synthetictests.StableSystemEventInvariants,
 		},
 		PreSuite: suiteWithProviderPreSuite,
 	},
@@ -179,7 +180,7 @@ var staticSuites = testSuites{
 				}
 				return strings.Contains(name, "[sig-imageregistry]") || isStandardEarlyOrLateTest(name)
 			},
-			SyntheticEventTests: ginkgo.JUnitForEventsFunc(synthetictests.StableSystemEventInvariants),
+			SyntheticEventTests: synthetictests.StableSystemEventInvariants,
 		},
 		PreSuite: suiteWithProviderPreSuite,
 	},
@@ -197,7 +198,7 @@ var staticSuites = testSuites{
 			},
 			Parallelism:         7,
 			TestTimeout:         20 * time.Minute,
-			SyntheticEventTests: ginkgo.JUnitForEventsFunc(synthetictests.StableSystemEventInvariants),
+			SyntheticEventTests: synthetictests.StableSystemEventInvariants,
 		},
 		PreSuite: suiteWithProviderPreSuite,
 	},
@@ -215,7 +216,7 @@ var staticSuites = testSuites{
 			},
 			Parallelism:         4,
 			TestTimeout:         20 * time.Minute,
-			SyntheticEventTests: ginkgo.JUnitForEventsFunc(synthetictests.StableSystemEventInvariants),
+			SyntheticEventTests: synthetictests.StableSystemEventInvariants,
 		},
 		PreSuite: suiteWithProviderPreSuite,
 	},
@@ -233,7 +234,7 @@ var staticSuites = testSuites{
 			},
 			Parallelism:         4,
 			TestTimeout:         20 * time.Minute,
-			SyntheticEventTests: ginkgo.JUnitForEventsFunc(synthetictests.StableSystemEventInvariants),
+			SyntheticEventTests: synthetictests.StableSystemEventInvariants,
 		},
 		PreSuite: suiteWithProviderPreSuite,
 	},
@@ -266,7 +267,7 @@ var staticSuites = testSuites{
 				}
 				return !strings.Contains(name, "[Suite:openshift/conformance/")
 			},
-			SyntheticEventTests: ginkgo.JUnitForEventsFunc(synthetictests.StableSystemEventInvariants),
+			SyntheticEventTests: synthetictests.StableSystemEventInvariants,
 		},
 		PreSuite: suiteWithProviderPreSuite,
 	},
@@ -282,7 +283,7 @@ var staticSuites = testSuites{
 				}
 				return strings.Contains(name, "[Feature:LegacyCommandTests]") || isStandardEarlyOrLateTest(name)
 			},
-			SyntheticEventTests: ginkgo.JUnitForEventsFunc(synthetictests.StableSystemEventInvariants),
+			SyntheticEventTests: synthetictests.StableSystemEventInvariants,
 		},
 		PreSuite: suiteWithNoProviderPreSuite,
 	},
@@ -300,7 +301,7 @@ var staticSuites = testSuites{
 				}
 				return strings.Contains(name, "External Storage [Driver:") && !strings.Contains(name, "[Disruptive]")
 			},
-			SyntheticEventTests: ginkgo.JUnitForEventsFunc(synthetictests.StableSystemEventInvariants),
+			SyntheticEventTests: synthetictests.StableSystemEventInvariants,
 		},
 		PreSuite: suiteWithKubeTestInitializationPreSuite,
 		PostSuite: func(opt *runOptions) {
@@ -330,7 +331,7 @@ var staticSuites = testSuites{
 			Parallelism:         60,
 			Count:               12,
 			TestTimeout:         20 * time.Minute,
-			SyntheticEventTests: ginkgo.JUnitForEventsFunc(synthetictests.StableSystemEventInvariants),
+			SyntheticEventTests: synthetictests.StableSystemEventInvariants,
 		},
 		PreSuite: suiteWithProviderPreSuite,
 	},
@@ -365,7 +366,7 @@ var staticSuites = testSuites{
 			},
 			Parallelism:          20,
 			MaximumAllowedFlakes: 15,
-			SyntheticEventTests:  ginkgo.JUnitForEventsFunc(synthetictests.StableSystemEventInvariants),
+			SyntheticEventTests:  synthetictests.StableSystemEventInvariants,
 		},
 		PreSuite: suiteWithKubeTestInitializationPreSuite,
 	},
@@ -383,6 +384,37 @@ var staticSuites = testSuites{
 	},
 }
 
+// suiteFileInvariants are the sets of synthetic tests a suite declared in a --suite-file may
+// apply to the events of its run.
+var suiteFileInvariants = map[string]ginkgo.JUnitsForEvents{
+	"stable":  synthetictests.StableSystemEventInvariants,
+	"upgrade": synthetictests.SystemUpgradeEventInvariants,
+	"system":  synthetictests.SystemEventInvariants,
+}
+
+// withSuiteFile returns the suites followed by those declared in the suite file at path,
+// which may not reuse the name of an existing suite.
+func withSuiteFile(suites testSuites, path string) (testSuites, error) {
+	declared, err := ginkgo.LoadSuiteFile(path, suiteFileInvariants)
+	if err != nil {
+		return nil, fmt.Errorf("could not load --suite-file: %v", err)
+	}
+	combined := make(testSuites, len(suites), len(suites)+len(declared))
+	copy(combined, suites)
+	for _, suite := range declared {
+		for i := range suites {
+			if suites[i].Name == suite.Name {
+				return nil, fmt.Errorf("could not load --suite-file: suite %q is already defined", suite.Name)
+			}
+		}
+		combined = append(combined, testSuite{
+			TestSuite: *suite,
+			PreSuite:  suiteWithProviderPreSuite,
+		})
+	}
+	return combined, nil
+}
+
 // isStandardEarlyTest returns true if a test is considered part of the normal
 // pre or post condition tests.
 func isStandardEarlyTest(name string) bool {
@@ -22,10 +22,12 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/kubectl/pkg/util/templates"
 
+	configv1 "github.com/openshift/api/config/v1"
 	"github.com/openshift/library-go/pkg/image/reference"
 	"github.com/openshift/library-go/pkg/serviceability"
 	"github.com/openshift/origin/pkg/monitor"
 	"github.com/openshift/origin/pkg/monitor/resourcewatch/cmd"
+	"github.com/openshift/origin/pkg/synthetictests"
 	testginkgo "github.com/openshift/origin/pkg/test/ginkgo"
 	"github.com/openshift/origin/pkg/version"
 	exutil "github.com/openshift/origin/test/extended/util"
@@ -70,6 +72,9 @@ func main() {
 		newImagesCommand(),
 		newRunTestCommand(),
 		newRunMonitorCommand(),
+		newMergeResultsCommand(),
+		newAnalyzeIntervalsCommand(),
+		newVerifyQuarantineCommand(),
 		cmd.NewRunResourceWatchCommand(),
 	)
 
@@ -110,9 +115,158 @@ func newRunMonitorCommand() *cobra.Command {
 			return monitorOpt.Run()
 		},
 	}
+	cmd.Flags().StringVar(&monitorOpt.StoreDir, "store-dir", monitorOpt.StoreDir, "Write recorded events to segment files in this directory instead of keeping them in memory.")
+	cmd.Flags().StringVar(&monitorOpt.ListenAddress, "listen", monitorOpt.ListenAddress, "Serve the recorded intervals, an event stream and a live timeline over HTTP on this address (e.g. localhost:8080).")
+	cmd.Flags().StringVar(&monitorOpt.MetricsListenAddress, "metrics-listen", monitorOpt.MetricsListenAddress, "Serve Prometheus metrics for disruption, restarts, operator and node conditions and test results at /metrics on this address (e.g. :9090).")
+	cmd.Flags().StringVar(&monitorOpt.DisruptionConfig, "disruption-config", monitorOpt.DisruptionConfig, "A YAML file of additional routes, services or URLs to poll for disruption.")
 	return cmd
 }
 
+func newMergeResultsCommand() *cobra.Command {
+	opt := &testginkgo.MergeResultsOptions{
+		Out:    os.Stdout,
+		ErrOut: os.Stderr,
+	}
+	cmd := &cobra.Command{
+		Use:   "merge-results REPORT|DIR...",
+		Short: "Merge the JUnit reports of a sharded suite",
+		Long: templates.LongDesc(`
+		Merge the JUnit reports written by each shard of a suite into a single report
+
+		Each argument is a JUnit report or a directory containing the junit_e2e_*.xml reports
+		written by 'run --shard=N/M'. Tests that failed and passed in the same report are
+		reported as flakes. A test that failed without passing in any report, such as an
+		invariant evaluated by every shard, fails the command.
+		`),
+
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return opt.Run(args)
+		},
+	}
+	cmd.Flags().StringVar(&opt.JUnitDir, "junit-dir", opt.JUnitDir, "The directory to write the merged report to.")
+	return cmd
+}
+
+// invariantSets are the synthetic invariants that can be evaluated offline by analyze-intervals.
+var invariantSets = map[string]synthetictests.EventInvariants{
+	"stable":  synthetictests.StableSystemEventInvariants,
+	"upgrade": synthetictests.SystemUpgradeEventInvariants,
+	"system":  synthetictests.SystemEventInvariants,
+}
+
+func newAnalyzeIntervalsCommand() *cobra.Command {
+	opt := &testginkgo.AnalyzeIntervalsOptions{
+		Out:    os.Stdout,
+		ErrOut: os.Stderr,
+	}
+	invariants := "stable"
+	var disruptionConfig, budgetsFile, latencyFile, platform, topology string
+	cmd := &cobra.Command{
+		Use:   "analyze-intervals FILE|DIR...",
+		Short: "Evaluate invariants against the intervals saved by previous runs",
+		Long: templates.LongDesc(`
+		Evaluate the synthetic invariants against saved intervals without a cluster
+
+		Each argument is an e2e-events_*.json file written by a previous run, or a directory
+		that is searched for them. The selected invariants are evaluated against each file
+		separately and a summary is printed. If --junit-dir is set a JUnit report is written
+		for each file. The command fails if any invariant failed without also passing.
+		`),
+
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			set, ok := invariantSets[invariants]
+			if !ok {
+				return fmt.Errorf("--invariants must be one of stable, upgrade or system")
+			}
+			backends, err := loadDisruptionBackends(disruptionConfig)
+			if err != nil {
+				return err
+			}
+			budgets, err := loadDisruptionBudgets(budgetsFile)
+			if err != nil {
+				return err
+			}
+			thresholds, err := loadPodLatencyThresholds(latencyFile)
+			if err != nil {
+				return err
+			}
+			opt.Invariants = set.WithContext(synthetictests.InvariantContext{
+				DisruptionBackends:   backends,
+				DisruptionBudgets:    budgets,
+				Platform:             platform,
+				Topology:             configv1.TopologyMode(topology),
+				PodLatencyThresholds: thresholds,
+			})
+			return opt.Run(args)
+		},
+	}
+	cmd.Flags().StringVar(&invariants, "invariants", invariants, "The set of invariants to evaluate: stable, upgrade or system.")
+	cmd.Flags().StringVar(&opt.JUnitDir, "junit-dir", opt.JUnitDir, "The directory to write JUnit reports to.")
+	cmd.Flags().StringVar(&disruptionConfig, "disruption-config", disruptionConfig, "The YAML file of additional backends the run polled for disruption, so their availability is evaluated.")
+	cmd.Flags().StringVar(&budgetsFile, "disruption-budgets", budgetsFile, "A YAML file of the disruption tolerated for each backend. Backends it does not select are allowed 1% of the run.")
+	cmd.Flags().StringVar(&latencyFile, "pod-latency-thresholds", latencyFile, "A YAML file of the pod lifecycle latencies tolerated in platform namespaces. Latencies it does not select keep the default thresholds.")
+	cmd.Flags().StringVar(&platform, "platform", platform, "The platform of the cluster the intervals were recorded on (e.g. aws), used to select disruption budgets.")
+	cmd.Flags().StringVar(&topology, "topology", topology, "The control plane topology of the cluster the intervals were recorded on (HighlyAvailable or SingleReplica), used to select disruption budgets.")
+	return cmd
+}
+
+func newVerifyQuarantineCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "verify-quarantine FILE",
+		Short: "Check that a quarantine list is valid and none of its entries have expired",
+		Long: templates.LongDesc(`
+		Check a list of quarantined tests
+
+		Quarantined tests run as usual but never fail the suite. Every entry must link the
+		bug tracking the flake and expire on a date, after which the test fails the suite
+		again. The command fails if the list is invalid or any entry has expired, so that
+		expired entries are either fixed or extended.
+		`),
+
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return fmt.Errorf("a single quarantine file must be specified")
+			}
+			return testginkgo.VerifyQuarantineList(args[0], time.Now())
+		},
+	}
+}
+
+// loadDisruptionBudgets returns the budgets in path, or nil if it is empty.
+func loadDisruptionBudgets(path string) (*synthetictests.DisruptionBudgetList, error) {
+	if len(path) == 0 {
+		return nil, nil
+	}
+	return synthetictests.LoadDisruptionBudgets(path)
+}
+
+// loadDisruptionBackends returns the locators of the backends in the disruption config at
+// path mapped to their names, or nil if it is empty.
+func loadDisruptionBackends(path string) (map[string]string, error) {
+	if len(path) == 0 {
+		return nil, nil
+	}
+	config, err := monitor.LoadDisruptionBackendConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	return config.LocatorsToName(), nil
+}
+
+// loadPodLatencyThresholds returns the thresholds in path, or nil if it is empty.
+func loadPodLatencyThresholds(path string) (*synthetictests.PodLatencyThresholdList, error) {
+	if len(path) == 0 {
+		return nil, nil
+	}
+	return synthetictests.LoadPodLatencyThresholds(path)
+}
+
 type imagesOptions struct {
 	Repository string
 	Upstream   bool
@@ -203,10 +357,53 @@ type runOptions struct {
 	ToImage      string
 	TestOptions  []string
 
+	// DisruptionBudgets is a YAML file of the disruption tolerated for each backend
+	DisruptionBudgets string
+	// PodLatencyThresholds is a YAML file of the pod lifecycle latency tolerated in platform namespaces
+	PodLatencyThresholds string
+	// SuiteFile is a YAML file of suites to offer in addition to the built-in suites
+	SuiteFile string
+
 	// Shared by initialization code
 	config *cluster.ClusterConfiguration
 }
 
+// setInvariantContext evaluates the system event invariants of the suite with the
+// configuration of the run.
+func (opt *runOptions) setInvariantContext(suite *testSuite) error {
+	invariants, ok := suite.SyntheticEventTests.(synthetictests.EventInvariants)
+	if !ok {
+		return nil
+	}
+	backends, err := loadDisruptionBackends(opt.DisruptionConfig)
+	if err != nil {
+		return err
+	}
+	budgets, err := loadDisruptionBudgets(opt.DisruptionBudgets)
+	if err != nil {
+		return err
+	}
+	thresholds, err := loadPodLatencyThresholds(opt.PodLatencyThresholds)
+	if err != nil {
+		return err
+	}
+	ctx := synthetictests.InvariantContext{
+		DisruptionBackends:   backends,
+		DisruptionBudgets:    budgets,
+		PodLatencyThresholds: thresholds,
+	}
+	// the disruption budgets are selected by the cluster identified by the suite's provider
+	if opt.config != nil {
+		ctx.Platform = opt.config.ProviderName
+		ctx.Topology = configv1.HighlyAvailableTopologyMode
+		if opt.config.SingleReplicaTopology {
+			ctx.Topology = configv1.SingleReplicaTopologyMode
+		}
+	}
+	suite.SyntheticEventTests = invariants.WithContext(ctx)
+	return nil
+}
+
 func (opt *runOptions) AsEnv() []string {
 	var args []string
 	args = append(args, "KUBE_TEST_REPO_LIST=") // explicitly prevent selective override
@@ -270,7 +467,11 @@ func newRunCommand() *cobra.Command {
 		If you specify the --dry-run argument, the names of each individual test that is part of the
 		suite will be printed, one per line. You may filter this list and pass it back to the run
 		command with the --file argument. You may also pipe a list of test names, one per line, on
-		standard input by passing "-f -".
+		standard input by passing "-f -". Pass --output=json or --output=yaml with --dry-run to
+		print the tags, location and suites of each test for use by other tools.
+
+		Additional suites may be declared in a YAML file passed with --suite-file. They are listed
+		with the built-in suites and selected by name in the same way.
 
 		`) + testginkgo.SuitesString(staticSuites.TestSuites(), "\n\nAvailable test suites:\n\n"),
 
@@ -283,15 +484,27 @@ func newRunCommand() *cobra.Command {
 				}
 				opt.SyntheticEventTests = pulledInvalidImages(opt.FromRepository)
 
-				suite, err := opt.SelectSuite(staticSuites, args)
+				suites := staticSuites
+				if len(opt.SuiteFile) > 0 {
+					var err error
+					if suites, err = withSuiteFile(staticSuites, opt.SuiteFile); err != nil {
+						return err
+					}
+				}
+
+				suite, err := opt.SelectSuite(suites, args)
 				if err != nil {
 					return err
 				}
+				opt.AvailableSuites = append(suites.TestSuites(), upgradeSuites.TestSuites()...)
 				if suite.PreSuite != nil {
 					if err := suite.PreSuite(opt); err != nil {
 						return err
 					}
 				}
+				if err := opt.setInvariantContext(suite); err != nil {
+					return err
+				}
 				opt.CommandEnv = opt.AsEnv()
 				if !opt.DryRun {
 					fmt.Fprintf(os.Stderr, "%s version: %s\n", filepath.Base(os.Args[0]), version.Get().String())
@@ -305,6 +518,22 @@ func newRunCommand() *cobra.Command {
 		},
 	}
 	bindOptions(opt, cmd.Flags())
+	cmd.Flags().StringVar(&opt.SuiteFile, "suite-file", opt.SuiteFile, "A YAML file of additional suites, each declared with a name, description, include and exclude regexes, tags, parallelism, count, timeout, maxFlakes and invariants (stable, upgrade or system).")
+
+	// the suites in --suite-file can only be listed once the flags have been parsed
+	long, help := cmd.Long, cmd.HelpFunc()
+	cmd.SetHelpFunc(func(c *cobra.Command, args []string) {
+		c.Long = long
+		if len(opt.SuiteFile) > 0 {
+			suites, err := withSuiteFile(staticSuites, opt.SuiteFile)
+			if err != nil {
+				fmt.Fprintf(c.ErrOrStderr(), "error: %v\n\n", err)
+			} else {
+				c.Long += testginkgo.SuitesString(suites[len(staticSuites):].TestSuites(), "")
+			}
+		}
+		help(c, args)
+	})
 	return cmd
 }
 
@@ -332,6 +561,16 @@ func newRunUpgradeCommand() *cobra.Command {
 		* disrupt-reboot=POLICY - During upgrades, periodically reboot master nodes. If set to 'graceful'
 		the reboot will allow the node to shut down services in an orderly fashion. If set to 'force' the
 		machine will terminate immediately without clean shutdown.
+		* disrupt=INJECTOR[:ARGS] - During upgrades, periodically inject a disruption. May be repeated.
+		The injectors are reboot[:role=ROLE,mode=graceful|force], kill-pod:ns=NAMESPACE[,selector=SELECTOR],
+		cordon-drain[:role=ROLE,for=DURATION] and delete-leader-lease:NAMESPACE[,name=NAME], and each
+		accepts every=DURATION to set the time between disruptions (default 5m). Each disruption is
+		recorded as an interval, and the pod and node invariants ignore the objects it disrupted.
+
+		--to-image may list several releases, separated by commas or one per line in a file passed
+		as --to-image=@FILE, to upgrade through each in order. Each upgrade records its own tests,
+		is reported as "upgrade hop N of M" with its timing and disruption, and the hops are
+		summarized in upgrade-hops_TIMESTAMP.json in the artifact directory.
 
 		`) + testginkgo.SuitesString(upgradeSuites.TestSuites(), "\n\nAvailable upgrade suites:\n\n"),
 
@@ -342,6 +581,11 @@ func newRunUpgradeCommand() *cobra.Command {
 				if len(opt.ToImage) == 0 {
 					return fmt.Errorf("--to-image must be specified to run an upgrade test")
 				}
+				toImage, err := parseUpgradePath(opt.ToImage)
+				if err != nil {
+					return err
+				}
+				opt.ToImage = toImage
 				if err := verifyImages(); err != nil {
 					return err
 				}
@@ -352,11 +596,19 @@ func newRunUpgradeCommand() *cobra.Command {
 					return err
 				}
 				opt.UpgradeSuite = suite.Name
+				// give each upgrade of a multi-hop path the time the suite allows a single upgrade
+				if hops := len(strings.Split(opt.ToImage, ",")); hops > 1 && opt.Timeout == 0 && suite.TestTimeout > 0 {
+					opt.Timeout = time.Duration(hops) * suite.TestTimeout
+				}
+				opt.AvailableSuites = append(staticSuites.TestSuites(), upgradeSuites.TestSuites()...)
 				if suite.PreSuite != nil {
 					if err := suite.PreSuite(opt); err != nil {
 						return err
 					}
 				}
+				if err := opt.setInvariantContext(suite); err != nil {
+					return err
+				}
 				opt.CommandEnv = opt.AsEnv()
 				if !opt.DryRun {
 					fmt.Fprintf(os.Stderr, "%s version: %s\n", filepath.Base(os.Args[0]), version.Get().String())
@@ -464,15 +716,29 @@ func mirrorToFile(opt *testginkgo.Options, fn func() error) error {
 func bindOptions(opt *runOptions, flags *pflag.FlagSet) {
 	flags.StringVar(&opt.FromRepository, "from-repository", opt.FromRepository, "A container image repository to retrieve test images from.")
 	flags.StringVar(&opt.Provider, "provider", opt.Provider, "The cluster infrastructure provider. Will automatically default to the correct value.")
+	flags.StringVar(&opt.DisruptionBudgets, "disruption-budgets", opt.DisruptionBudgets, "A YAML file of the disruption tolerated for each backend by suite, platform and topology. Backends it does not select are allowed 1% of the run.")
+	flags.StringVar(&opt.PodLatencyThresholds, "pod-latency-thresholds", opt.PodLatencyThresholds, "A YAML file of the pod lifecycle latencies tolerated in platform namespaces by latency and namespace. Latencies it does not select keep the default thresholds.")
 	bindTestOptions(&opt.Options, flags)
 }
 
 func bindTestOptions(opt *testginkgo.Options, flags *pflag.FlagSet) {
 	flags.BoolVar(&opt.DryRun, "dry-run", opt.DryRun, "Print the tests to run without executing them.")
+	flags.StringVar(&opt.DryRunOutput, "output", opt.DryRunOutput, "With --dry-run, print each test with its tags, location, exclusion group and the suites that select it as json or yaml.")
 	flags.BoolVar(&opt.PrintCommands, "print-commands", opt.PrintCommands, "Print the sub-commands that would be executed instead.")
 	flags.StringVar(&opt.JUnitDir, "junit-dir", opt.JUnitDir, "The directory to write test reports to.")
+	flags.BoolVar(&opt.Resume, "resume", opt.Resume, "Resume an interrupted run by skipping the tests recorded as completed in the journal in --junit-dir.")
+	flags.StringVar(&opt.TimingsFile, "timings-file", opt.TimingsFile, "A JSON file of historical test durations, or a JUnit report or directory of reports, used to start the longest tests first.")
+	flags.BoolVar(&opt.UpdateTimings, "update-timings", opt.UpdateTimings, "Write the test durations observed in this run to --timings-file.")
+	flags.StringVar(&opt.Shard, "shard", opt.Shard, "Run only shard N of M (N/M) of the suite, balanced by --timings-file when set. Use merge-results to combine the reports of all shards.")
+	flags.StringVar(&opt.ListenAddress, "listen", opt.ListenAddress, "Serve the intervals recorded by the monitor and a live timeline over HTTP on this address (e.g. localhost:8080).")
+	flags.StringVar(&opt.MetricsListenAddress, "metrics-listen", opt.MetricsListenAddress, "Serve Prometheus metrics derived from the intervals recorded by the monitor at /metrics on this address (e.g. :9090).")
+	flags.StringVar(&opt.DisruptionConfig, "disruption-config", opt.DisruptionConfig, "A YAML file of additional routes, services or URLs the monitor polls for disruption.")
+	flags.StringArrayVar(&opt.ReportFormats, "report-format", opt.ReportFormats, "Write an additional report as FORMAT or FORMAT=PATH, where FORMAT is junit, jsonl (a live stream of test events), tap or markdown (a summary). Reports without a path are written to --junit-dir. May be repeated.")
+	flags.StringVar(&opt.QuarantineFile, "quarantine-file", opt.QuarantineFile, "A YAML list of known flaky tests, by name or regex, that run but never fail the suite. Each entry must link a bug and an expiry date.")
+	flags.StringVar(&opt.RetryPolicy, "retry-policy", opt.RetryPolicy, "Override how failed tests are retried to detect flakes with a comma delimited list of attempts=N (runs per test), budget=N (retries per suite), on=failure|timeout|any, and repeatable allow=REGEXP and deny=REGEXP settings.")
 	flags.StringVarP(&opt.TestFile, "file", "f", opt.TestFile, "Create a suite from the newline-delimited test names in this file.")
 	flags.StringVar(&opt.Regex, "run", opt.Regex, "Regular expression of tests to run.")
+	flags.StringVar(&opt.Select, "select", opt.Select, "A boolean expression of the bracketed tags of tests to run, combining tags with &&, || and ! and grouping with parentheses, e.g. 'sig-network && !Serial && (Feature:SCTP || Conformance)'.")
 	flags.StringVarP(&opt.OutFile, "output-file", "o", opt.OutFile, "Write all test output to this file.")
 	flags.IntVar(&opt.Count, "count", opt.Count, "Run each test a specified number of times. Defaults to 1 or the suite's preferred value. -1 will run forever.")
 	flags.BoolVar(&opt.FailFast, "fail-fast", opt.FailFast, "If a test fails, exit immediately.")
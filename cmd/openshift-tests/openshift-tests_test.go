@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunHelpListsSuiteFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "suites.yaml")
+	if err := ioutil.WriteFile(path, []byte(`
+suites:
+- name: network-edge/conformance
+  description: Router and ingress tests owned by the network edge team.
+  include:
+  - '\[sig-network-edge\]'
+`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		name string
+		args []string
+		want bool
+	}{
+		{name: "built-in suites", args: []string{"--help"}},
+		{name: "suite file", args: []string{"--suite-file", path, "--help"}, want: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			cmd := newRunCommand()
+			cmd.SetOut(out)
+			cmd.SetErr(out)
+			cmd.SetArgs(tt.args)
+			if err := cmd.Execute(); err != nil {
+				t.Fatal(err)
+			}
+			help := out.String()
+			if !strings.Contains(help, staticSuites[0].Name+"\n  ") {
+				t.Errorf("expected the built-in suites to be listed:\n%s", help)
+			}
+			listed := strings.Contains(help, "network-edge/conformance\n  Router and ingress tests owned by the network edge team.")
+			if listed != tt.want {
+				t.Errorf("expected the suite file to be listed %t, got:\n%s", tt.want, help)
+			}
+			if tt.want && strings.Index(help, "network-edge/conformance") < strings.Index(help, staticSuites[len(staticSuites)-1].Name) {
+				t.Errorf("expected the suite file to be listed after the built-in suites:\n%s", help)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"strings"
 	"time"
@@ -31,7 +32,7 @@ var upgradeSuites = testSuites{
 				return strings.Contains(name, "[Feature:ClusterUpgrade]") && !strings.Contains(name, "[Suite:k8s]")
 			},
 			TestTimeout:         240 * time.Minute,
-			SyntheticEventTests: ginkgo.JUnitForEventsFunc(synthetictests.SystemUpgradeEventInvariants),
+			SyntheticEventTests: synthetictests.SystemUpgradeEventInvariants,
 		},
 		PreSuite: upgradeTestPreSuite,
 	},
@@ -48,7 +49,7 @@ var upgradeSuites = testSuites{
 				return strings.Contains(name, "[Feature:ClusterUpgrade]") && !strings.Contains(name, "[Suite:k8s]")
 			},
 			TestTimeout:         240 * time.Minute,
-			SyntheticEventTests: ginkgo.JUnitForEventsFunc(synthetictests.SystemUpgradeEventInvariants),
+			SyntheticEventTests: synthetictests.SystemUpgradeEventInvariants,
 		},
 		PreSuite: upgradeTestPreSuite,
 	},
@@ -65,7 +66,7 @@ var upgradeSuites = testSuites{
 				return strings.Contains(name, "[Feature:ClusterUpgrade]") && !strings.Contains(name, "[Suite:k8s]")
 			},
 			TestTimeout:         240 * time.Minute,
-			SyntheticEventTests: ginkgo.JUnitForEventsFunc(synthetictests.SystemUpgradeEventInvariants),
+			SyntheticEventTests: synthetictests.SystemUpgradeEventInvariants,
 		},
 		PreSuite: upgradeTestPreSuite,
 	},
@@ -105,8 +106,27 @@ func upgradeTestPreTest() error {
 	}
 }
 
-func parseUpgradeOptions(options []string) error {
+// upgradeOptionKeys are the keys recognized by parseUpgradeOptions.
+var upgradeOptionKeys = map[string]bool{"abort-at": true, "disrupt-reboot": true, "disrupt": true}
+
+// joinDisruptArguments rejoins the arguments of a disrupt option that were split apart
+// because --options is a comma delimited list.
+func joinDisruptArguments(options []string) []string {
+	var joined []string
 	for _, opt := range options {
+		if last := len(joined) - 1; last >= 0 && strings.HasPrefix(joined[last], "disrupt=") {
+			if key := strings.SplitN(opt, "=", 2)[0]; !upgradeOptionKeys[key] {
+				joined[last] += "," + opt
+				continue
+			}
+		}
+		joined = append(joined, opt)
+	}
+	return joined
+}
+
+func parseUpgradeOptions(options []string) error {
+	for _, opt := range joinDisruptArguments(options) {
 		parts := strings.SplitN(opt, "=", 2)
 		if len(parts) != 2 {
 			return fmt.Errorf("expected option of the form KEY=VALUE instead of %q", opt)
@@ -120,6 +140,10 @@ func parseUpgradeOptions(options []string) error {
 			if err := upgrade.SetUpgradeDisruptReboot(parts[1]); err != nil {
 				return err
 			}
+		case "disrupt":
+			if err := upgrade.AddUpgradeDisruption(parts[1]); err != nil {
+				return err
+			}
 		default:
 			return fmt.Errorf("unrecognized upgrade option: %s", parts[0])
 		}
@@ -127,6 +151,42 @@ func parseUpgradeOptions(options []string) error {
 	return nil
 }
 
+// parseUpgradePath returns the comma delimited list of releases to upgrade through, in order,
+// from the value of --to-image. The value is either the list itself or @FILE, where FILE
+// names one release image or version per line and lines starting with # are ignored.
+func parseUpgradePath(value string) (string, error) {
+	source := "--to-image"
+	var releases []string
+	if strings.HasPrefix(value, "@") {
+		path := strings.TrimPrefix(value, "@")
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("unable to read the upgrade path: %v", err)
+		}
+		source = path
+		for _, line := range strings.Split(string(data), "\n") {
+			line = strings.TrimSpace(line)
+			if len(line) == 0 || strings.HasPrefix(line, "#") {
+				continue
+			}
+			releases = append(releases, line)
+		}
+		if len(releases) == 0 {
+			return "", fmt.Errorf("%s does not list any releases to upgrade to", path)
+		}
+	} else {
+		releases = strings.Split(value, ",")
+	}
+	for i, release := range releases {
+		release = strings.TrimSpace(release)
+		if len(release) == 0 || strings.ContainsAny(release, ", \t") {
+			return "", fmt.Errorf("release %d in %s must be a single image or version, got %q", i+1, source, release)
+		}
+		releases[i] = release
+	}
+	return strings.Join(releases, ","), nil
+}
+
 type UpgradeOptions struct {
 	Suite       string
 	ToImage     string
@@ -153,6 +213,6 @@ func filterUpgrade(tests []upgrades.Test, match func(string) bool) error {
 }
 
 func bindUpgradeOptions(opt *runOptions, flags *pflag.FlagSet) {
-	flags.StringVar(&opt.ToImage, "to-image", opt.ToImage, "Specify the image to test an upgrade to.")
+	flags.StringVar(&opt.ToImage, "to-image", opt.ToImage, "Specify the image to test an upgrade to. A comma delimited list, or @FILE listing one release per line, upgrades through each in order.")
 	flags.StringSliceVar(&opt.TestOptions, "options", opt.TestOptions, "A set of KEY=VALUE options to control the test. See the help text.")
 }
@@ -2,6 +2,7 @@ package monitor
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"io/ioutil"
 	"net"
@@ -17,6 +18,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
@@ -65,7 +67,13 @@ func GetMonitorRESTConfig() (*rest.Config, error) {
 // Start begins monitoring the cluster referenced by the default kube configuration until
 // context is finished.
 func Start(ctx context.Context, restConfig *rest.Config) (*Monitor, error) {
-	m := NewMonitorWithInterval(time.Second)
+	return StartWithStore(ctx, restConfig, NewMemoryIntervalStore(), NewMemoryIntervalStore())
+}
+
+// StartWithStore is Start, but keeps recorded events and sampled conditions in the provided
+// stores.
+func StartWithStore(ctx context.Context, restConfig *rest.Config, events, conditions IntervalStore) (*Monitor, error) {
+	m := NewMonitorWithStore(time.Second, events, conditions)
 	client, err := kubernetes.NewForConfig(restConfig)
 	if err != nil {
 		return nil, err
@@ -74,6 +82,10 @@ func Start(ctx context.Context, restConfig *rest.Config) (*Monitor, error) {
 	if err != nil {
 		return nil, err
 	}
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
 
 	if err := StartKubeAPIMonitoringWithNewConnections(ctx, m, restConfig, 5*time.Second); err != nil {
 		return nil, err
@@ -99,6 +111,7 @@ func Start(ctx context.Context, restConfig *rest.Config) (*Monitor, error) {
 
 	// add interval creation at the same point where we add the monitors
 	startClusterOperatorMonitoring(ctx, m, configClient)
+	startMachineConfigPoolMonitoring(ctx, m, client.Discovery(), dynamicClient)
 	m.intervalCreationFns = append(
 		m.intervalCreationFns,
 		intervalcreation.IntervalsFromEvents_OperatorAvailable,
@@ -106,6 +119,12 @@ func Start(ctx context.Context, restConfig *rest.Config) (*Monitor, error) {
 		intervalcreation.IntervalsFromEvents_OperatorDegraded,
 		intervalcreation.IntervalsFromEvents_E2ETests,
 		intervalcreation.IntervalsFromEvents_NodeChanges,
+		intervalcreation.IntervalsFromEvents_InjectedDisruptions,
+		intervalcreation.IntervalsFromEvents_MachineConfigPoolUpdates,
+		intervalcreation.IntervalsFromEvents_PodLifecycleLatency,
+		intervalcreation.IntervalsFromEvents_PodPhases,
+		intervalcreation.IntervalsFromEvents_ContainerNotReady,
+		intervalcreation.IntervalsFromEvents_ContainerBackOff,
 	)
 
 	m.StartSampling(ctx)
@@ -129,32 +148,11 @@ func startServerMonitoring(ctx context.Context, m *Monitor, clusterConfig *rest.
 	if err != nil {
 		return err
 	}
-	var httpTransport *http.Transport
-
-	connectionType := NewConnectionType
+	connectionType := ReusedConnectionType
 	if disableConnectionReuse {
 		connectionType = NewConnectionType
-		httpTransport = &http.Transport{
-			Dial: (&net.Dialer{
-				Timeout:   timeout,
-				KeepAlive: -1, // this looks unnecessary to me, but it was set in other code.
-			}).Dial,
-			TLSClientConfig:     tlsConfig,
-			TLSHandshakeTimeout: timeout,
-			DisableKeepAlives:   true, // this prevents connections from being reused
-			IdleConnTimeout:     timeout,
-		}
-	} else {
-		connectionType = ReusedConnectionType
-		httpTransport = &http.Transport{
-			Dial: (&net.Dialer{
-				Timeout: timeout,
-			}).Dial,
-			TLSClientConfig:     tlsConfig,
-			TLSHandshakeTimeout: timeout,
-			IdleConnTimeout:     timeout,
-		}
 	}
+	httpTransport := newDisruptionTransport(timeout, tlsConfig, disableConnectionReuse)
 
 	roundTripper := http.RoundTripper(httpTransport)
 	if kubeTransportConfig.HasTokenAuth() {
@@ -169,7 +167,7 @@ func startServerMonitoring(ctx context.Context, m *Monitor, clusterConfig *rest.
 		Transport: roundTripper,
 	}
 
-	go NewSampler(m, time.Second, func(previous bool) (condition *monitorapi.Condition, next bool) {
+	go startDisruptionSampler(ctx, m, time.Second, resourceLocator, connectionType, func() error {
 		resp, err := httpClient.Get(clusterConfig.Host + url)
 
 		// we don't have an error, but the response code was an error, then we have to set an artificial error for the logic below to work.
@@ -184,29 +182,63 @@ func startServerMonitoring(ctx context.Context, m *Monitor, clusterConfig *rest.
 		if resp != nil && resp.Body != nil {
 			defer resp.Body.Close()
 		}
+		return err
+	})
 
+	return nil
+}
+
+// newDisruptionTransport returns a transport for polling a backend. If disableConnectionReuse
+// is true every request is made over a new connection.
+func newDisruptionTransport(timeout time.Duration, tlsConfig *tls.Config, disableConnectionReuse bool) *http.Transport {
+	if disableConnectionReuse {
+		return &http.Transport{
+			Dial: (&net.Dialer{
+				Timeout:   timeout,
+				KeepAlive: -1, // this looks unnecessary to me, but it was set in other code.
+			}).Dial,
+			TLSClientConfig:     tlsConfig,
+			TLSHandshakeTimeout: timeout,
+			DisableKeepAlives:   true, // this prevents connections from being reused
+			IdleConnTimeout:     timeout,
+		}
+	}
+	return &http.Transport{
+		Dial: (&net.Dialer{
+			Timeout: timeout,
+		}).Dial,
+		TLSClientConfig:     tlsConfig,
+		TLSHandshakeTimeout: timeout,
+		IdleConnTimeout:     timeout,
+	}
+}
+
+// startDisruptionSampler invokes check every interval until the context is done, recording
+// when the backend identified by locator stops and starts responding and an interval for
+// as long as it is not responding.
+func startDisruptionSampler(ctx context.Context, m *Monitor, interval time.Duration, locator string, connectionType BackendConnectionType, check func() error) {
+	NewSampler(m, interval, func(previous bool) (condition *monitorapi.Condition, next bool) {
+		err := check()
 		switch {
 		case err == nil && !previous:
 			condition = &monitorapi.Condition{
 				Level:   monitorapi.Info,
-				Locator: resourceLocator,
-				Message: DisruptionEndedMessage(resourceLocator, connectionType),
+				Locator: locator,
+				Message: DisruptionEndedMessage(locator, connectionType),
 			}
 		case err != nil && previous:
 			condition = &monitorapi.Condition{
 				Level:   monitorapi.Error,
-				Locator: resourceLocator,
-				Message: DisruptionBeganMessage(resourceLocator, connectionType, err),
+				Locator: locator,
+				Message: DisruptionBeganMessage(locator, connectionType, err),
 			}
 		}
 		return condition, err == nil
 	}).WhenFailing(ctx, &monitorapi.Condition{
 		Level:   monitorapi.Error,
-		Locator: resourceLocator,
-		Message: DisruptionContinuingMessage(resourceLocator, connectionType, err),
+		Locator: locator,
+		Message: DisruptionContinuingMessage(locator, connectionType, nil),
 	})
-
-	return nil
 }
 
 func LocateRouteForDisruptionCheck(ns, name string, connectionType BackendConnectionType) string {
@@ -329,11 +361,19 @@ func locateEvent(event *corev1.Event) string {
 }
 
 func locatePod(pod *corev1.Pod) string {
-	return fmt.Sprintf("ns/%s pod/%s node/%s", pod.Namespace, pod.Name, pod.Spec.NodeName)
+	return podLocator(pod).String()
 }
 
 func locatePodContainer(pod *corev1.Pod, containerName string) string {
-	return fmt.Sprintf("ns/%s pod/%s node/%s container/%s", pod.Namespace, pod.Name, pod.Spec.NodeName, containerName)
+	return podContainerLocator(pod, containerName).String()
+}
+
+func podLocator(pod *corev1.Pod) monitorapi.Locator {
+	return monitorapi.LocatePod(pod.Namespace, pod.Name, pod.Spec.NodeName)
+}
+
+func podContainerLocator(pod *corev1.Pod, containerName string) monitorapi.Locator {
+	return monitorapi.LocateContainer(pod.Namespace, pod.Name, pod.Spec.NodeName, containerName)
 }
 
 func filterToSystemNamespaces(obj runtime.Object) bool {
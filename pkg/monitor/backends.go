@@ -0,0 +1,308 @@
+package monitor
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	routeclient "github.com/openshift/client-go/route/clientset/versioned"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/yaml"
+)
+
+// DisruptionBackendConfig lists additional backends to poll for disruption, for example:
+//
+//	backends:
+//	- name: my-app
+//	  route:
+//	    namespace: my-app
+//	    name: frontend
+//	  path: /healthz
+//	  connections: [new, reused]
+//	  expectedStatusCodes: [200]
+//	  expectBody: ok
+//	  interval: 1s
+//	  timeout: 5s
+type DisruptionBackendConfig struct {
+	Backends []DisruptionBackend `json:"backends"`
+}
+
+// DisruptionBackend is a target polled by the monitor. Exactly one of URL, Route or Service
+// must be set.
+type DisruptionBackend struct {
+	// Name identifies the backend in backend-disruption.json, with the connection type
+	// appended, and in the locator of URL and service backends.
+	Name string `json:"name"`
+
+	// URL is polled directly.
+	URL string `json:"url,omitempty"`
+	// Route is polled at the host of the route's spec.
+	Route *DisruptionBackendReference `json:"route,omitempty"`
+	// Service is polled at the first load balancer ingress of a LoadBalancer service.
+	Service *DisruptionBackendReference `json:"service,omitempty"`
+	// Path is appended to the address of the backend.
+	Path string `json:"path,omitempty"`
+
+	// Connections are the connection types to poll with. Both new and reused connections
+	// are polled by default.
+	Connections []BackendConnectionType `json:"connections,omitempty"`
+	// ExpectedStatusCodes are the response codes that indicate the backend is available.
+	// Defaults to any 2xx or 3xx response.
+	ExpectedStatusCodes []int `json:"expectedStatusCodes,omitempty"`
+	// ExpectBody, if set, must be contained in the response body.
+	ExpectBody string `json:"expectBody,omitempty"`
+	// Interval between requests, one second by default.
+	Interval metav1.Duration `json:"interval,omitempty"`
+	// Timeout of each request, five seconds by default.
+	Timeout metav1.Duration `json:"timeout,omitempty"`
+	// InsecureSkipTLSVerify disables verification of the backend's serving certificate.
+	InsecureSkipTLSVerify bool `json:"insecureSkipTLSVerify,omitempty"`
+}
+
+// DisruptionBackendReference identifies a route or service.
+type DisruptionBackendReference struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// Port of a service, defaulting to the first port of the service.
+	Port int32 `json:"port,omitempty"`
+	// Scheme used to reach a service, http by default.
+	Scheme string `json:"scheme,omitempty"`
+}
+
+// LoadDisruptionBackendConfig reads and validates a YAML list of disruption backends.
+func LoadDisruptionBackendConfig(path string) (*DisruptionBackendConfig, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	config := &DisruptionBackendConfig{}
+	if err := yaml.UnmarshalStrict(data, config); err != nil {
+		return nil, fmt.Errorf("unable to parse disruption backends from %s: %v", path, err)
+	}
+	if err := config.complete(); err != nil {
+		return nil, fmt.Errorf("invalid disruption backends in %s: %v", path, err)
+	}
+	return config, nil
+}
+
+// complete defaults and validates the backends.
+func (c *DisruptionBackendConfig) complete() error {
+	locators := make(map[string]string)
+	names := make(map[string]bool)
+	for _, name := range BackendDisruptionLocatorsToName {
+		names[name] = true
+	}
+	for i := range c.Backends {
+		backend := &c.Backends[i]
+		if len(backend.Name) == 0 {
+			return fmt.Errorf("backend %d has no name", i)
+		}
+		targets := 0
+		if len(backend.URL) > 0 {
+			targets++
+		}
+		for _, ref := range []*DisruptionBackendReference{backend.Route, backend.Service} {
+			if ref == nil {
+				continue
+			}
+			targets++
+			if len(ref.Namespace) == 0 || len(ref.Name) == 0 {
+				return fmt.Errorf("backend %s must reference a namespace and name", backend.Name)
+			}
+		}
+		if targets != 1 {
+			return fmt.Errorf("backend %s must set exactly one of url, route or service", backend.Name)
+		}
+		if len(backend.Connections) == 0 {
+			backend.Connections = []BackendConnectionType{NewConnectionType, ReusedConnectionType}
+		}
+		if backend.Interval.Duration <= 0 {
+			backend.Interval.Duration = time.Second
+		}
+		if backend.Timeout.Duration <= 0 {
+			backend.Timeout.Duration = 5 * time.Second
+		}
+		for _, connectionType := range backend.Connections {
+			if connectionType != NewConnectionType && connectionType != ReusedConnectionType {
+				return fmt.Errorf("backend %s has unrecognized connection type %q, must be %s or %s", backend.Name, connectionType, NewConnectionType, ReusedConnectionType)
+			}
+			locator := backend.locator(connectionType)
+			if other, ok := locators[locator]; ok {
+				return fmt.Errorf("backends %s and %s are both reported as %s", other, backend.Name, locator)
+			}
+			if _, ok := BackendDisruptionLocatorsToName[locator]; ok {
+				return fmt.Errorf("backend %s is already monitored as %s", backend.Name, locator)
+			}
+			locators[locator] = backend.Name
+			name := backend.disruptionName(connectionType)
+			if names[name] {
+				return fmt.Errorf("backend %s is already reported as %s", backend.Name, name)
+			}
+			names[name] = true
+		}
+	}
+	return nil
+}
+
+// locator returns the locator the backend's disruption is recorded with.
+func (b *DisruptionBackend) locator(connectionType BackendConnectionType) string {
+	if b.Route != nil {
+		return LocateRouteForDisruptionCheck(b.Route.Namespace, b.Route.Name, connectionType)
+	}
+	return LocateDisruptionCheck(b.Name, connectionType)
+}
+
+// disruptionName returns the name of the backend in backend-disruption.json.
+func (b *DisruptionBackend) disruptionName(connectionType BackendConnectionType) string {
+	return fmt.Sprintf("%s-%s-connections", b.Name, connectionType)
+}
+
+// LocatorsToName maps the locator each configured backend is recorded with to its name in
+// backend-disruption.json.
+func (c *DisruptionBackendConfig) LocatorsToName() map[string]string {
+	locators := make(map[string]string)
+	if c == nil {
+		return locators
+	}
+	for i := range c.Backends {
+		backend := &c.Backends[i]
+		for _, connectionType := range backend.Connections {
+			locators[backend.locator(connectionType)] = backend.disruptionName(connectionType)
+		}
+	}
+	return locators
+}
+
+// DisruptionBackendLocatorsToName returns the backends in BackendDisruptionLocatorsToName
+// together with the configured backends, without modifying either.
+func DisruptionBackendLocatorsToName(configured map[string]string) map[string]string {
+	locators := make(map[string]string, len(BackendDisruptionLocatorsToName)+len(configured))
+	for locator, name := range BackendDisruptionLocatorsToName {
+		locators[locator] = name
+	}
+	for locator, name := range configured {
+		locators[locator] = name
+	}
+	return locators
+}
+
+// StartDisruptionBackends resolves the address of each configured backend and polls it
+// until the context is done. It returns the locators of the backends mapped to their names,
+// which must be passed to the consumers of the recorded intervals.
+func StartDisruptionBackends(ctx context.Context, m *Monitor, restConfig *rest.Config, config *DisruptionBackendConfig) (map[string]string, error) {
+	if config == nil || len(config.Backends) == 0 {
+		return config.LocatorsToName(), nil
+	}
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	routeClient, err := routeclient.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range config.Backends {
+		backend := &config.Backends[i]
+		url, err := backend.resolveURL(ctx, kubeClient, routeClient)
+		if err != nil {
+			return nil, fmt.Errorf("unable to locate disruption backend %s: %v", backend.Name, err)
+		}
+		tlsConfig := &tls.Config{InsecureSkipVerify: backend.InsecureSkipTLSVerify}
+		for _, connectionType := range backend.Connections {
+			locator := backend.locator(connectionType)
+			client := &http.Client{
+				Transport: newDisruptionTransport(backend.Timeout.Duration, tlsConfig, connectionType == NewConnectionType),
+				Timeout:   backend.Timeout.Duration,
+			}
+			go startDisruptionSampler(ctx, m, backend.Interval.Duration, locator, connectionType, func() error {
+				return backend.check(client, url)
+			})
+		}
+	}
+	return config.LocatorsToName(), nil
+}
+
+// resolveURL returns the URL to poll for the backend.
+func (b *DisruptionBackend) resolveURL(ctx context.Context, kubeClient kubernetes.Interface, routeClient routeclient.Interface) (string, error) {
+	switch {
+	case b.Route != nil:
+		route, err := routeClient.RouteV1().Routes(b.Route.Namespace).Get(ctx, b.Route.Name, metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+		if len(route.Spec.Host) == 0 {
+			return "", fmt.Errorf("route %s/%s has no host", b.Route.Namespace, b.Route.Name)
+		}
+		scheme := "http"
+		if route.Spec.TLS != nil {
+			scheme = "https"
+		}
+		return fmt.Sprintf("%s://%s%s", scheme, route.Spec.Host, b.Path), nil
+
+	case b.Service != nil:
+		service, err := kubeClient.CoreV1().Services(b.Service.Namespace).Get(ctx, b.Service.Name, metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+		if service.Spec.Type != corev1.ServiceTypeLoadBalancer || len(service.Status.LoadBalancer.Ingress) == 0 {
+			return "", fmt.Errorf("service %s/%s has no load balancer ingress", b.Service.Namespace, b.Service.Name)
+		}
+		host := service.Status.LoadBalancer.Ingress[0].IP
+		if len(service.Status.LoadBalancer.Ingress[0].Hostname) > 0 {
+			host = service.Status.LoadBalancer.Ingress[0].Hostname
+		}
+		port := b.Service.Port
+		if port == 0 && len(service.Spec.Ports) > 0 {
+			port = service.Spec.Ports[0].Port
+		}
+		scheme := b.Service.Scheme
+		if len(scheme) == 0 {
+			scheme = "http"
+		}
+		return fmt.Sprintf("%s://%s:%d%s", scheme, host, port, b.Path), nil
+
+	default:
+		return b.URL + b.Path, nil
+	}
+}
+
+// check returns an error if the backend did not respond as expected.
+func (b *DisruptionBackend) check(client *http.Client, url string) error {
+	resp, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if !b.expectedStatus(resp.StatusCode) {
+		return fmt.Errorf("error running request: %v: %v", resp.Status, strings.TrimSpace(string(body)))
+	}
+	if len(b.ExpectBody) > 0 && !bytes.Contains(body, []byte(b.ExpectBody)) {
+		return fmt.Errorf("backend returned success but did not contain the correct body contents: %q", string(body))
+	}
+	return nil
+}
+
+func (b *DisruptionBackend) expectedStatus(code int) bool {
+	if len(b.ExpectedStatusCodes) == 0 {
+		return code >= 200 && code <= 399
+	}
+	for _, expected := range b.ExpectedStatusCodes {
+		if code == expected {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,198 @@
+package monitor
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadDisruptionBackendConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "disruption-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		name    string
+		config  string
+		wantErr string
+	}{
+		{
+			name: "route with defaults",
+			config: `
+backends:
+- name: my-app
+  route:
+    namespace: ns
+    name: frontend
+`,
+		},
+		{
+			name: "url and service",
+			config: `
+backends:
+- name: a
+  url: https://example.com
+  connections: [new]
+  interval: 2s
+- name: b
+  service:
+    namespace: ns
+    name: lb
+    port: 8080
+`,
+		},
+		{
+			name: "no target",
+			config: `
+backends:
+- name: a
+`,
+			wantErr: "must set exactly one of url, route or service",
+		},
+		{
+			name: "two targets",
+			config: `
+backends:
+- name: a
+  url: https://example.com
+  route:
+    namespace: ns
+    name: frontend
+`,
+			wantErr: "must set exactly one of url, route or service",
+		},
+		{
+			name: "unknown connection",
+			config: `
+backends:
+- name: a
+  url: https://example.com
+  connections: [old]
+`,
+			wantErr: "unrecognized connection type",
+		},
+		{
+			name: "duplicate route",
+			config: `
+backends:
+- name: a
+  route: {namespace: ns, name: frontend}
+- name: b
+  route: {namespace: ns, name: frontend}
+`,
+			wantErr: "are both reported as",
+		},
+		{
+			name: "already monitored",
+			config: `
+backends:
+- name: console
+  route: {namespace: openshift-console, name: console}
+`,
+			wantErr: "is already monitored",
+		},
+		{
+			name: "unknown field",
+			config: `
+backends:
+- name: a
+  url: https://example.com
+  expect: ok
+`,
+			wantErr: "unable to parse",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, "config.yaml")
+			if err := ioutil.WriteFile(path, []byte(tt.config), 0644); err != nil {
+				t.Fatal(err)
+			}
+			config, err := LoadDisruptionBackendConfig(path)
+			if len(tt.wantErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, backend := range config.Backends {
+				if len(backend.Connections) == 0 || backend.Interval.Duration == 0 || backend.Timeout.Duration == 0 {
+					t.Errorf("backend %s was not defaulted: %#v", backend.Name, backend)
+				}
+			}
+		})
+	}
+}
+
+func TestDisruptionBackendCheck(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+			w.Write([]byte("ok"))
+		case "/created":
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte("created"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	client := &http.Client{Timeout: 5 * time.Second}
+
+	tests := []struct {
+		name    string
+		backend DisruptionBackend
+		path    string
+		wantErr bool
+	}{
+		{name: "default status", path: "/ok"},
+		{name: "not found", path: "/missing", wantErr: true},
+		{name: "expected status", path: "/created", backend: DisruptionBackend{ExpectedStatusCodes: []int{201}}},
+		{name: "unexpected status", path: "/ok", backend: DisruptionBackend{ExpectedStatusCodes: []int{201}}, wantErr: true},
+		{name: "body matches", path: "/ok", backend: DisruptionBackend{ExpectBody: "ok"}},
+		{name: "body does not match", path: "/created", backend: DisruptionBackend{ExpectBody: "ok"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.backend.check(client, server.URL+tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestDisruptionBackendLocatorsToName(t *testing.T) {
+	builtin := len(BackendDisruptionLocatorsToName)
+	config := &DisruptionBackendConfig{Backends: []DisruptionBackend{
+		{Name: "my-app", Route: &DisruptionBackendReference{Namespace: "ns", Name: "frontend"}, Connections: []BackendConnectionType{NewConnectionType}},
+		{Name: "lb", URL: "https://example.com", Connections: []BackendConnectionType{NewConnectionType, ReusedConnectionType}},
+	}}
+	configured := config.LocatorsToName()
+	if len(configured) != 3 {
+		t.Fatalf("expected a locator per backend connection, got %v", configured)
+	}
+	locators := DisruptionBackendLocatorsToName(configured)
+	if name := locators[LocateRouteForDisruptionCheck("ns", "frontend", NewConnectionType)]; name != "my-app-new-connections" {
+		t.Errorf("unexpected name for the route backend: %q", name)
+	}
+	if name := locators[LocateDisruptionCheck("lb", ReusedConnectionType)]; name != "lb-reused-connections" {
+		t.Errorf("unexpected name for the url backend: %q", name)
+	}
+	if name := locators[LocatorKubeAPIServerNewConnection]; name != "kube-api-new-connections" {
+		t.Errorf("expected the builtin backends, got %q", name)
+	}
+	if len(BackendDisruptionLocatorsToName) != builtin {
+		t.Errorf("the builtin backends were modified: %v", BackendDisruptionLocatorsToName)
+	}
+}
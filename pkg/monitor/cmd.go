@@ -6,6 +6,7 @@ import (
 	"io"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"syscall"
 	"time"
 )
@@ -13,6 +14,17 @@ import (
 // Options is used to run a monitoring process against the provided server as
 // a command line interaction.
 type Options struct {
+	// StoreDir, if set, is a directory that recorded events and conditions are written
+	// to so that memory use stays bounded while the monitor runs.
+	StoreDir string
+	// ListenAddress, if set, serves the recorded intervals over HTTP.
+	ListenAddress string
+	// MetricsListenAddress, if set, serves Prometheus metrics derived from the recorded
+	// intervals over HTTP.
+	MetricsListenAddress string
+	// DisruptionConfig, if set, is a YAML file of additional backends to poll for disruption.
+	DisruptionConfig string
+
 	Out, ErrOut io.Writer
 }
 
@@ -38,14 +50,51 @@ func (opt *Options) Run() error {
 	}()
 	signal.Notify(abortCh, syscall.SIGINT, syscall.SIGTERM)
 
+	var backends *DisruptionBackendConfig
+	if len(opt.DisruptionConfig) > 0 {
+		config, err := LoadDisruptionBackendConfig(opt.DisruptionConfig)
+		if err != nil {
+			return err
+		}
+		backends = config
+	}
+
 	restConfig, err := GetMonitorRESTConfig()
 	if err != nil {
 		return err
 	}
-	m, err := Start(ctx, restConfig)
+	events, conditions := NewMemoryIntervalStore(), NewMemoryIntervalStore()
+	if len(opt.StoreDir) > 0 {
+		if events, err = NewSegmentIntervalStore(filepath.Join(opt.StoreDir, "events"), DefaultSegmentSize); err != nil {
+			return err
+		}
+		if conditions, err = NewSegmentIntervalStore(filepath.Join(opt.StoreDir, "conditions"), DefaultSegmentSize); err != nil {
+			return err
+		}
+	}
+	m, err := StartWithStore(ctx, restConfig, events, conditions)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := m.Close(); err != nil {
+			fmt.Fprintf(opt.ErrOut, "error: Unable to close the monitor store: %v\n", err)
+		}
+	}()
+	disruptionBackends, err := StartDisruptionBackends(ctx, m, restConfig, backends)
 	if err != nil {
 		return err
 	}
+	if len(opt.ListenAddress) > 0 {
+		if err := StartServer(ctx, m, opt.ListenAddress, opt.ErrOut); err != nil {
+			return err
+		}
+	}
+	if len(opt.MetricsListenAddress) > 0 {
+		if err := StartMetricsServer(ctx, m, opt.MetricsListenAddress, disruptionBackends, opt.ErrOut); err != nil {
+			return err
+		}
+	}
 
 	go func() {
 		ticker := time.NewTicker(100 * time.Millisecond)
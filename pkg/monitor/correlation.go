@@ -0,0 +1,89 @@
+package monitor
+
+import (
+	"sort"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+// The kinds of cluster disruption that may cause a concurrently running test to fail.
+const (
+	DisruptionKindBackend          = "BackendDisruption"
+	DisruptionKindInjected         = "InjectedDisruption"
+	DisruptionKindOperatorDegraded = "OperatorDegraded"
+)
+
+// DisruptionKind returns the kind of cluster disruption described by the interval, or an
+// empty string if it is not one that could cause a concurrently running test to fail.
+func DisruptionKind(interval monitorapi.EventInterval) string {
+	if monitorapi.IsE2ETest(interval.Locator) {
+		return ""
+	}
+	if monitorapi.IsDisruption(interval) {
+		return DisruptionKindBackend
+	}
+	if monitorapi.IsInjectedDisruption(interval) {
+		return DisruptionKindInjected
+	}
+	// the intervals an operator was degraded rather than the moments its condition changed
+	if interval.To.After(interval.From) && interval.TypedLocator().Type == monitorapi.LocatorTypeClusterOperator {
+		message := interval.TypedMessage()
+		if message.Annotations[monitorapi.AnnotationCondition] == string(configv1.OperatorDegraded) && message.Annotations[monitorapi.AnnotationStatus] == string(configv1.ConditionTrue) {
+			return DisruptionKindOperatorDegraded
+		}
+	}
+	return ""
+}
+
+// DisruptionIndex holds the cluster disruptions of a run sorted by start, so that the ones
+// that overlap a test can be found without scanning every interval.
+type DisruptionIndex struct {
+	disruptions monitorapi.Intervals
+	// ends is the latest end of the disruptions up to each index. It never decreases, so
+	// the first disruption that may still be ongoing at a time is found by binary search.
+	ends []time.Time
+}
+
+// NewDisruptionIndex returns an index of the intervals that describe a cluster disruption.
+func NewDisruptionIndex(events monitorapi.Intervals) *DisruptionIndex {
+	index := &DisruptionIndex{}
+	for _, event := range events {
+		if len(DisruptionKind(event)) > 0 {
+			index.disruptions = append(index.disruptions, event)
+		}
+	}
+	sort.Sort(index.disruptions)
+	index.ends = make([]time.Time, len(index.disruptions))
+	var last time.Time
+	for i, disruption := range index.disruptions {
+		if end := disruptionEnd(disruption); end.After(last) {
+			last = end
+		}
+		index.ends[i] = last
+	}
+	return index
+}
+
+// Overlapping returns the disruptions that overlap the time between start and end.
+func (d *DisruptionIndex) Overlapping(start, end time.Time) monitorapi.Intervals {
+	first := sort.Search(len(d.ends), func(i int) bool { return !d.ends[i].Before(start) })
+	last := sort.Search(len(d.disruptions), func(i int) bool { return d.disruptions[i].From.After(end) })
+	var overlapping monitorapi.Intervals
+	for i := first; i < last; i++ {
+		if !disruptionEnd(d.disruptions[i]).Before(start) {
+			overlapping = append(overlapping, d.disruptions[i])
+		}
+	}
+	return overlapping
+}
+
+// disruptionEnd returns the end of the disruption, which is its start if it has no end.
+func disruptionEnd(interval monitorapi.EventInterval) time.Time {
+	if interval.To.Before(interval.From) {
+		return interval.From
+	}
+	return interval.To
+}
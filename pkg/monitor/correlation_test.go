@@ -0,0 +1,70 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func TestDisruptionIndex(t *testing.T) {
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(seconds int) time.Time { return start.Add(time.Duration(seconds) * time.Second) }
+	events := monitorapi.Intervals{
+		{Condition: monitorapi.Condition{Level: monitorapi.Error, Locator: LocatorKubeAPIServerNewConnection, Message: DisruptionContinuingMessage(LocatorKubeAPIServerNewConnection, NewConnectionType, nil)}, From: at(10), To: at(22)},
+		{Condition: monitorapi.Condition{Level: monitorapi.Warning, Locator: "node/b", Message: "reason/InjectedDisruption injector/reboot triggered graceful reboot"}, From: at(0), To: at(14)},
+		// degraded for the whole run, the change of its condition is not a disruption itself
+		{Condition: monitorapi.Condition{Level: monitorapi.Error, Locator: "clusteroperator/etcd", Message: "condition/Degraded status/True reason/NodeControllerDegraded changed: node is not ready"}, From: at(1), To: at(1)},
+		{Condition: monitorapi.Condition{Level: monitorapi.Error, Locator: "clusteroperator/etcd", Message: "condition/Degraded status/True reason/node is not ready"}, From: at(1), To: at(60)},
+		// not disruptions
+		{Condition: monitorapi.Condition{Level: monitorapi.Warning, Locator: "node/a", Message: "roles/worker node is not ready"}, From: at(5), To: at(5)},
+		{Condition: monitorapi.Condition{Level: monitorapi.Error, Locator: "alert/KubeAPIErrorBudgetBurn ns/openshift-kube-apiserver", Message: "alertstate/firing severity/critical"}, From: at(15), To: at(25)},
+		{Condition: monitorapi.Condition{Level: monitorapi.Error, Locator: "ns/e2e-test-a pod/b node/a", Message: "reason/Evicted The node was low on resource"}, From: at(20), To: at(20)},
+		{Condition: monitorapi.Condition{Level: monitorapi.Error, Locator: "clusteroperator/dns", Message: "condition/Available status/False reason/DNSDegraded"}, From: at(2), To: at(30)},
+		{Condition: monitorapi.Condition{Level: monitorapi.Error, Locator: monitorapi.E2ETestLocator("another test"), Message: "finishedStatus/Failed"}, From: at(0), To: at(30)},
+		// after the test
+		{Condition: monitorapi.Condition{Level: monitorapi.Error, Locator: LocatorOAuthAPIServerNewConnection, Message: DisruptionContinuingMessage(LocatorOAuthAPIServerNewConnection, NewConnectionType, nil)}, From: at(50), To: at(55)},
+	}
+	index := NewDisruptionIndex(events)
+
+	tests := []struct {
+		name       string
+		start, end time.Time
+		want       map[string]int
+	}{
+		{
+			name:  "during the disruptions",
+			start: at(12),
+			end:   at(30),
+			want:  map[string]int{DisruptionKindBackend: 1, DisruptionKindInjected: 1, DisruptionKindOperatorDegraded: 1},
+		},
+		{
+			name:  "after the injected disruption",
+			start: at(23),
+			end:   at(40),
+			want:  map[string]int{DisruptionKindOperatorDegraded: 1},
+		},
+		{
+			name:  "before the run",
+			start: at(-10),
+			end:   at(-1),
+			want:  map[string]int{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kinds := map[string]int{}
+			for _, interval := range index.Overlapping(tt.start, tt.end) {
+				kinds[DisruptionKind(interval)]++
+			}
+			if len(kinds) != len(tt.want) {
+				t.Errorf("expected disruptions %v, got %v", tt.want, kinds)
+			}
+			for kind, count := range tt.want {
+				if kinds[kind] != count {
+					t.Errorf("expected %d %s disruptions, got %d", count, kind, kinds[kind])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package monitor
+
+import (
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+// injectedDisruptionRecovery is how long an object disrupted on purpose is given to recover
+// after the disruption ends before its events are considered unexpected again.
+const injectedDisruptionRecovery = 5 * time.Minute
+
+// WithoutInjectedDisruptionTargets removes the events about objects a test disrupted on
+// purpose that occurred while they were disrupted or recovering, so that invariants do not
+// fail on the consequences of the disruption. A disrupted pod excludes the events of that
+// pod, a disrupted node those of the node and the pods on it, and any other disrupted
+// object those of its namespace.
+func WithoutInjectedDisruptionTargets(events monitorapi.Intervals) monitorapi.Intervals {
+	var injected monitorapi.Intervals
+	for _, event := range events {
+		if monitorapi.IsInjectedDisruption(event) {
+			injected = append(injected, event)
+		}
+	}
+	if len(injected) == 0 {
+		return events
+	}
+	return events.Filter(func(event monitorapi.EventInterval) bool {
+		if monitorapi.IsInjectedDisruption(event) {
+			return true
+		}
+		for _, disruption := range injected {
+			if event.From.Before(disruption.From) || event.From.After(disruption.To.Add(injectedDisruptionRecovery)) {
+				continue
+			}
+			if disruptionTargets(disruption.TypedLocator(), event.TypedLocator()) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// disruptionTargets returns true if disrupting the target affects the located object.
+func disruptionTargets(target, locator monitorapi.Locator) bool {
+	namespace, pod, node := target.Keys[monitorapi.LocatorNamespaceKey], target.Keys[monitorapi.LocatorPodKey], target.Keys[monitorapi.LocatorNodeKey]
+	switch {
+	case len(pod) > 0:
+		return locator.Keys[monitorapi.LocatorNamespaceKey] == namespace && locator.Keys[monitorapi.LocatorPodKey] == pod
+	case len(node) > 0 && len(namespace) == 0:
+		return locator.Keys[monitorapi.LocatorNodeKey] == node
+	case len(namespace) > 0:
+		return locator.Keys[monitorapi.LocatorNamespaceKey] == namespace
+	}
+	return false
+}
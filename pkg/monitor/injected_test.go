@@ -0,0 +1,52 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func TestWithoutInjectedDisruptionTargets(t *testing.T) {
+	base := time.Date(2021, 8, 1, 10, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return base.Add(time.Duration(minutes) * time.Minute) }
+	event := func(locator, message string, from, to time.Time) monitorapi.EventInterval {
+		return monitorapi.EventInterval{
+			Condition: monitorapi.Condition{Level: monitorapi.Warning, Locator: locator, Message: message},
+			From:      from,
+			To:        to,
+		}
+	}
+	events := monitorapi.Intervals{
+		event("ns/openshift-etcd pod/etcd-a node/master-0", "reason/InjectedDisruption injector/kill-pod deleted pod", at(10), at(11)),
+		event("node/worker-1", "reason/InjectedDisruption injector/cordon-drain cordoned and drained node", at(20), at(25)),
+		event("ns/openshift-kube-scheduler lease/kube-scheduler", "reason/InjectedDisruption injector/delete-leader-lease deleted lease", at(40), at(40)),
+
+		// during or shortly after a disruption of the same object
+		event("ns/openshift-etcd pod/etcd-a node/master-0 container/etcd", "reason/ContainerExit code/137", at(11), at(11)),
+		event("ns/openshift-etcd pod/etcd-a node/master-0", "reason/GracefulDelete duration/0s", at(15), at(15)),
+		event("ns/e2e pod/web node/worker-1", "reason/Evicted", at(22), at(22)),
+		event("node/worker-1", "reason/NodeNotSchedulable", at(21), at(21)),
+		event("ns/openshift-kube-scheduler pod/scheduler-b node/master-1", "reason/LeaderElection", at(41), at(41)),
+
+		// unrelated object, before the disruption, or after it recovered
+		event("ns/openshift-etcd pod/etcd-b node/master-1", "reason/ContainerExit code/137", at(11), at(11)),
+		event("ns/openshift-etcd pod/etcd-a node/master-0", "reason/ContainerExit code/1", at(5), at(5)),
+		event("ns/openshift-etcd pod/etcd-a node/master-0", "reason/ContainerExit code/2", at(17), at(17)),
+		event("ns/e2e pod/web node/worker-2", "reason/Evicted", at(22), at(22)),
+	}
+	remaining := WithoutInjectedDisruptionTargets(events)
+	if len(remaining) != 7 {
+		t.Fatalf("unexpected events:\n%s", remaining.Strings())
+	}
+	for _, event := range remaining[3:] {
+		if event.Locator == "node/worker-1" || event.Locator == "ns/e2e pod/web node/worker-1" || event.Message == "reason/LeaderElection" || event.Message == "reason/ContainerExit code/137" && event.Locator == "ns/openshift-etcd pod/etcd-a node/master-0 container/etcd" {
+			t.Errorf("expected event to be removed: %s", event.String())
+		}
+	}
+
+	withoutInjected := events[3:]
+	if len(WithoutInjectedDisruptionTargets(withoutInjected)) != len(withoutInjected) {
+		t.Fatal("expected events to be unchanged without injected disruptions")
+	}
+}
@@ -10,7 +10,7 @@ import (
 
 func IntervalsFromEvents_E2ETests(events monitorapi.Intervals, beginning, end time.Time) monitorapi.Intervals {
 	ret := monitorapi.Intervals{}
-	testNameToLastStart := map[string]time.Time{}
+	testNameToLastStart := map[string]monitorapi.EventInterval{}
 
 	for _, event := range events {
 		testName, ok := monitorapi.E2ETestFromLocator(event.Locator)
@@ -18,7 +18,7 @@ func IntervalsFromEvents_E2ETests(events monitorapi.Intervals, beginning, end ti
 			continue
 		}
 		if event.Message == "started" {
-			testNameToLastStart[testName] = event.From
+			testNameToLastStart[testName] = event
 			continue
 		}
 		if !strings.Contains(event.Message, "finishedStatus/") {
@@ -26,8 +26,8 @@ func IntervalsFromEvents_E2ETests(events monitorapi.Intervals, beginning, end ti
 		}
 
 		from := beginning
-		if lastStart := testNameToLastStart[testName]; !lastStart.IsZero() {
-			from = lastStart
+		if lastStart, ok := testNameToLastStart[testName]; ok {
+			from = lastStart.From
 		}
 		level := monitorapi.Info
 		endState := "MISSING"
@@ -52,23 +52,25 @@ func IntervalsFromEvents_E2ETests(events monitorapi.Intervals, beginning, end ti
 		delete(testNameToLastStart, testName)
 		ret = append(ret, monitorapi.EventInterval{
 			Condition: monitorapi.Condition{
-				Level:   level,
-				Locator: event.Locator,
-				Message: fmt.Sprintf("e2e test finished As %q", endState),
+				Level:             level,
+				Locator:           event.Locator,
+				Message:           fmt.Sprintf("e2e test finished As %q", endState),
+				StructuredLocator: event.StructuredLocator,
 			},
 			From: from,
 			To:   event.From,
 		})
 	}
 
-	for testName, testStart := range testNameToLastStart {
+	for _, testStart := range testNameToLastStart {
 		ret = append(ret, monitorapi.EventInterval{
 			Condition: monitorapi.Condition{
-				Level:   monitorapi.Warning,
-				Locator: monitorapi.OperatorLocator(testName),
-				Message: fmt.Sprintf("e2e test did not finish %q", "DidNotFinish"),
+				Level:             monitorapi.Warning,
+				Locator:           testStart.Locator,
+				Message:           fmt.Sprintf("e2e test did not finish %q", "DidNotFinish"),
+				StructuredLocator: testStart.StructuredLocator,
 			},
-			From: testStart,
+			From: testStart.From,
 			To:   end,
 		})
 	}
@@ -0,0 +1,50 @@
+package intervalcreation
+
+import (
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func TestIntervalsFromEvents_E2ETestsKeepTestID(t *testing.T) {
+	testEvent := func(name, id, message, at string) monitorapi.EventInterval {
+		return monitorapi.EventInterval{
+			Condition: monitorapi.Condition{
+				Level:             monitorapi.Info,
+				Locator:           monitorapi.E2ETestLocator(name),
+				Message:           message,
+				StructuredLocator: monitorapi.LocateE2ETestWithID(name, id),
+			},
+			From: timeFor(at),
+			To:   timeFor(at),
+		}
+	}
+	events := monitorapi.Intervals{
+		testEvent("passes", "1111", "started", "2021-03-29T15:56:00Z"),
+		testEvent("hangs", "2222", "started", "2021-03-29T15:56:01Z"),
+		testEvent("passes", "1111", "finishedStatus/Passed", "2021-03-29T15:56:10Z"),
+	}
+
+	actual := IntervalsFromEvents_E2ETests(events, timeFor("2021-03-29T15:55:00Z"), timeFor("2021-03-29T16:00:00Z"))
+	if len(actual) != 2 {
+		t.Fatal(spew.Sdump(actual))
+	}
+	for i, want := range []struct {
+		locator string
+		id      string
+		message string
+	}{
+		{locator: monitorapi.E2ETestLocator("passes"), id: "1111", message: `e2e test finished As "Passed"`},
+		{locator: monitorapi.E2ETestLocator("hangs"), id: "2222", message: `e2e test did not finish "DidNotFinish"`},
+	} {
+		interval := actual[i]
+		if interval.Locator != want.locator || interval.Message != want.message {
+			t.Errorf("unexpected interval %d: %s", i, interval.String())
+		}
+		if id := interval.StructuredLocator.Keys[monitorapi.LocatorE2ETestIDKey]; id != want.id {
+			t.Errorf("expected interval %d to keep test ID %q, got %q", i, want.id, id)
+		}
+	}
+}
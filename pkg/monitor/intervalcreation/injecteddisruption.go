@@ -0,0 +1,66 @@
+package intervalcreation
+
+import (
+	"sort"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+// IntervalsFromEvents_InjectedDisruptions creates an interval for each disruption a test
+// injected into the cluster, from the event recorded when it started to the event recorded
+// when it ended, or to end if the disruption never ended.
+func IntervalsFromEvents_InjectedDisruptions(events monitorapi.Intervals, beginning, end time.Time) monitorapi.Intervals {
+	var intervals monitorapi.Intervals
+	type started struct {
+		locator string
+		from    time.Time
+		message monitorapi.Message
+	}
+	// disruptions are keyed by the disrupted object and the injector that disrupted it
+	open := map[string]started{}
+	closeInterval := func(start started, to time.Time) {
+		message := monitorapi.NewMessage().
+			Reason(monitorapi.InjectedDisruptionReason).
+			HumanMessage(start.message.HumanMessage)
+		for key, value := range start.message.Annotations {
+			message = message.WithAnnotation(key, value)
+		}
+		intervals = append(intervals, monitorapi.EventInterval{
+			Condition: monitorapi.Condition{
+				Level:   monitorapi.Warning,
+				Locator: start.locator,
+				Message: message.Build().String(),
+			},
+			From: start.from,
+			To:   to,
+		})
+	}
+
+	for _, event := range events {
+		message := event.TypedMessage()
+		if message.Reason != monitorapi.InjectedDisruptionStartedReason && message.Reason != monitorapi.InjectedDisruptionEndedReason {
+			continue
+		}
+		key := event.Locator + " " + message.Annotations[monitorapi.AnnotationInjector]
+		switch message.Reason {
+		case monitorapi.InjectedDisruptionStartedReason:
+			if _, ok := open[key]; !ok {
+				open[key] = started{locator: event.Locator, from: event.From, message: message}
+			}
+		case monitorapi.InjectedDisruptionEndedReason:
+			start, ok := open[key]
+			if !ok {
+				// the disruption started before the monitor did
+				start = started{locator: event.Locator, from: beginning, message: message}
+			}
+			delete(open, key)
+			closeInterval(start, event.From)
+		}
+	}
+	for _, start := range open {
+		closeInterval(start, end)
+	}
+	sort.Sort(intervals)
+	return intervals
+}
@@ -0,0 +1,47 @@
+package intervalcreation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func TestIntervalsFromEvents_InjectedDisruptions(t *testing.T) {
+	base := time.Date(2021, 8, 1, 10, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return base.Add(time.Duration(minutes) * time.Minute) }
+	event := func(locator, message string, from time.Time) monitorapi.EventInterval {
+		return monitorapi.EventInterval{
+			Condition: monitorapi.Condition{Level: monitorapi.Info, Locator: locator, Message: message},
+			From:      from,
+			To:        from,
+		}
+	}
+	events := monitorapi.Intervals{
+		event("ns/openshift-etcd pod/etcd-a", "reason/InjectedDisruptionEnded injector/kill-pod pod was deleted", at(1)),
+		event("node/worker-1", "reason/InjectedDisruptionStarted roles/worker injector/cordon-drain cordoned and drained node", at(2)),
+		event("node/worker-1", "reason/InjectedDisruptionStarted roles/worker injector/reboot rebooting node", at(3)),
+		event("node/worker-1", "reason/Other", at(4)),
+		event("node/worker-1", "reason/InjectedDisruptionEnded roles/worker injector/cordon-drain uncordoned node", at(6)),
+	}
+	intervals := IntervalsFromEvents_InjectedDisruptions(events, at(0), at(10))
+	if len(intervals) != 3 {
+		t.Fatalf("unexpected intervals:\n%s", intervals.Strings())
+	}
+	for i, expected := range []struct {
+		locator, message string
+		from, to         time.Time
+	}{
+		{"ns/openshift-etcd pod/etcd-a", "reason/InjectedDisruption injector/kill-pod pod was deleted", at(0), at(1)},
+		{"node/worker-1", "reason/InjectedDisruption roles/worker injector/cordon-drain cordoned and drained node", at(2), at(6)},
+		{"node/worker-1", "reason/InjectedDisruption roles/worker injector/reboot rebooting node", at(3), at(10)},
+	} {
+		interval := intervals[i]
+		if interval.Locator != expected.locator || interval.Message != expected.message || !interval.From.Equal(expected.from) || !interval.To.Equal(expected.to) {
+			t.Errorf("unexpected interval %d: %s", i, interval.String())
+		}
+		if !monitorapi.IsInjectedDisruption(interval) || interval.Level != monitorapi.Warning {
+			t.Errorf("expected interval %d to be an injected disruption: %s", i, interval.String())
+		}
+	}
+}
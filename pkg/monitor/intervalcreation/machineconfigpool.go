@@ -0,0 +1,61 @@
+package intervalcreation
+
+import (
+	"sort"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+// IntervalsFromEvents_MachineConfigPoolUpdates creates an interval for each rollout of a
+// machine config pool, from the moment the pool started updating until all of its nodes
+// had the new configuration.
+func IntervalsFromEvents_MachineConfigPoolUpdates(events monitorapi.Intervals, beginning, end time.Time) monitorapi.Intervals {
+	var intervals monitorapi.Intervals
+	type started struct {
+		from   time.Time
+		config string
+	}
+	open := map[string]started{}
+	closeInterval := func(locator string, start started, level monitorapi.EventLevel, humanMessage string, to time.Time) {
+		intervals = append(intervals, monitorapi.EventInterval{
+			Condition: monitorapi.Condition{
+				Level:   level,
+				Locator: locator,
+				Message: monitorapi.NewMessage().
+					Reason(monitorapi.MachineConfigPoolUpdateReason).
+					WithAnnotation(monitorapi.AnnotationConfig, start.config).
+					HumanMessage(humanMessage).Build().String(),
+			},
+			From: start.from,
+			To:   to,
+		})
+	}
+
+	for _, event := range events {
+		if event.TypedLocator().Type != monitorapi.LocatorTypeMachineConfigPool {
+			continue
+		}
+		message := event.TypedMessage()
+		switch message.Reason {
+		case monitorapi.MachineConfigPoolUpdatingReason:
+			if _, ok := open[event.Locator]; !ok {
+				open[event.Locator] = started{from: event.From, config: message.Annotations[monitorapi.AnnotationConfig]}
+			}
+		case monitorapi.MachineConfigPoolUpdatedReason:
+			start, ok := open[event.Locator]
+			if !ok {
+				// the pool started updating before the monitor did
+				start = started{from: beginning}
+			}
+			start.config = message.Annotations[monitorapi.AnnotationConfig]
+			delete(open, event.Locator)
+			closeInterval(event.Locator, start, monitorapi.Info, "pool updated", event.From)
+		}
+	}
+	for locator, start := range open {
+		closeInterval(locator, start, monitorapi.Warning, "pool never finished updating", end)
+	}
+	sort.Sort(intervals)
+	return intervals
+}
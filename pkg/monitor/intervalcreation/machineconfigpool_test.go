@@ -0,0 +1,45 @@
+package intervalcreation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func TestIntervalsFromEvents_MachineConfigPoolUpdates(t *testing.T) {
+	base := time.Date(2021, 8, 1, 10, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return base.Add(time.Duration(minutes) * time.Minute) }
+	event := func(locator, message string, from time.Time) monitorapi.EventInterval {
+		return monitorapi.EventInterval{
+			Condition: monitorapi.Condition{Level: monitorapi.Info, Locator: locator, Message: message},
+			From:      from,
+			To:        from,
+		}
+	}
+	events := monitorapi.Intervals{
+		event("machineconfigpool/worker", "reason/MachineConfigPoolUpdated config/rendered-worker-1 pool finished updating", at(1)),
+		event("machineconfigpool/master", "reason/MachineConfigPoolUpdating config/rendered-master-2 pool started updating", at(2)),
+		event("node/master-0", "reason/MachineConfigChange config/rendered-master-2 roles/master config change requested", at(3)),
+		event("machineconfigpool/master", "reason/MachineConfigPoolUpdated config/rendered-master-2 pool finished updating", at(8)),
+		event("machineconfigpool/worker", "reason/MachineConfigPoolUpdating config/rendered-worker-2 pool started updating", at(9)),
+	}
+	intervals := IntervalsFromEvents_MachineConfigPoolUpdates(events, at(0), at(10))
+	if len(intervals) != 3 {
+		t.Fatalf("unexpected intervals:\n%s", intervals.Strings())
+	}
+	for i, expected := range []struct {
+		locator, message string
+		level            monitorapi.EventLevel
+		from, to         time.Time
+	}{
+		{"machineconfigpool/worker", "reason/MachineConfigPoolUpdate config/rendered-worker-1 pool updated", monitorapi.Info, at(0), at(1)},
+		{"machineconfigpool/master", "reason/MachineConfigPoolUpdate config/rendered-master-2 pool updated", monitorapi.Info, at(2), at(8)},
+		{"machineconfigpool/worker", "reason/MachineConfigPoolUpdate config/rendered-worker-2 pool never finished updating", monitorapi.Warning, at(9), at(10)},
+	} {
+		interval := intervals[i]
+		if interval.Locator != expected.locator || interval.Message != expected.message || interval.Level != expected.level || !interval.From.Equal(expected.from) || !interval.To.Equal(expected.to) {
+			t.Errorf("unexpected interval %d: %s", i, interval.String())
+		}
+	}
+}
@@ -49,10 +49,10 @@ func IntervalsFromEvents_NodeChanges(events monitorapi.Intervals, beginning, end
 		if !ok {
 			continue
 		}
-		if !strings.HasPrefix(event.Message, "reason/") {
+		reason := event.TypedMessage().Reason
+		if len(reason) == 0 {
 			continue
 		}
-		reason := strings.SplitN(strings.TrimPrefix(event.Message, "reason/"), " ", 2)[0]
 
 		roles := monitorapi.GetNodeRoles(event)
 		nodeNameToRoles[node] = roles
@@ -0,0 +1,266 @@
+package intervalcreation
+
+import (
+	"sort"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+// podKey identifies a pod across the pod and container locators of its events.
+func podKey(locator monitorapi.Locator) string {
+	return locator.Keys[monitorapi.LocatorNamespaceKey] + "/" + locator.Keys[monitorapi.LocatorPodKey]
+}
+
+// podLocatorFrom returns the pod locator of a pod or container locator.
+func podLocatorFrom(locator monitorapi.Locator) monitorapi.Locator {
+	return monitorapi.LocatePod(locator.Keys[monitorapi.LocatorNamespaceKey], locator.Keys[monitorapi.LocatorPodKey], locator.Keys[monitorapi.LocatorNodeKey])
+}
+
+// isPodCreated reports whether the event is the pod monitor observing the pod being created,
+// rather than the kubelet reporting container/C reason/Created for one of its containers.
+func isPodCreated(locator monitorapi.Locator, message monitorapi.Message) bool {
+	return locator.Type == monitorapi.LocatorTypePod && message.Reason == "Created" && len(message.Annotations[monitorapi.AnnotationContainer]) == 0
+}
+
+func isPodDeleted(reason string) bool {
+	switch reason {
+	case "Deleted", "DeletedAfterCompletion", "DeletedBeforeScheduling":
+		return true
+	}
+	return false
+}
+
+func isPodDeleting(reason string) bool {
+	return reason == "GracefulDelete" || reason == "ForceDelete"
+}
+
+// podPhase is the phase a pod is in and when it entered it.
+type podPhase struct {
+	locator monitorapi.Locator
+	phase   string
+	since   time.Time
+}
+
+// IntervalsFromEvents_PodPhases creates an interval for each phase a pod was in during the run:
+// Pending from creation until it was running, Running until it was deleted and Terminating
+// from the deletion request until it was removed. Pods that were not created during the run
+// are Running from the beginning of the run until they are deleted.
+func IntervalsFromEvents_PodPhases(events monitorapi.Intervals, beginning, end time.Time) monitorapi.Intervals {
+	var intervals monitorapi.Intervals
+	pods := map[string]*podPhase{}
+	closePhase := func(pod *podPhase, to time.Time) {
+		if len(pod.phase) == 0 || to.Before(pod.since) {
+			return
+		}
+		intervals = append(intervals, monitorapi.EventInterval{
+			Condition: monitorapi.NewCondition(
+				monitorapi.Info,
+				pod.locator,
+				monitorapi.NewMessage().Reason(monitorapi.PodPhaseReason).WithAnnotation(monitorapi.AnnotationPhase, pod.phase).Build(),
+			),
+			From: pod.since,
+			To:   to,
+		})
+		pod.phase = ""
+	}
+	enterPhase := func(pod *podPhase, phase string, at time.Time) {
+		if pod.phase == phase {
+			return
+		}
+		closePhase(pod, at)
+		pod.phase, pod.since = phase, at
+	}
+
+	for _, event := range events {
+		locator := event.TypedLocator()
+		if locator.Type != monitorapi.LocatorTypePod && locator.Type != monitorapi.LocatorTypeContainer {
+			continue
+		}
+		message := event.TypedMessage()
+		reason := message.Reason
+		created := isPodCreated(locator, message)
+		key := podKey(locator)
+		pod, ok := pods[key]
+		if !ok {
+			if isPodDeleted(reason) {
+				continue
+			}
+			pod = &podPhase{}
+			pods[key] = pod
+			if !created {
+				// the pod existed before the run started
+				pod.phase, pod.since = monitorapi.PodPhaseRunning, beginning
+			}
+		}
+		if len(locator.Keys[monitorapi.LocatorNodeKey]) > 0 || pod.locator.IsZero() {
+			pod.locator = podLocatorFrom(locator)
+		}
+
+		switch {
+		case created:
+			// a pod recreated with the same name starts over
+			closePhase(pod, event.From)
+			enterPhase(pod, monitorapi.PodPhasePending, event.From)
+		case reason == "Running" && locator.Type == monitorapi.LocatorTypePod, reason == "Ready":
+			if pod.phase == monitorapi.PodPhasePending {
+				enterPhase(pod, monitorapi.PodPhaseRunning, event.From)
+			}
+		case isPodDeleting(reason):
+			enterPhase(pod, monitorapi.PodPhaseTerminating, event.From)
+		case isPodDeleted(reason):
+			closePhase(pod, event.From)
+			delete(pods, key)
+		}
+	}
+	for _, pod := range pods {
+		closePhase(pod, end)
+	}
+	sort.Sort(intervals)
+	return intervals
+}
+
+// IntervalsFromEvents_ContainerNotReady creates an interval for each time a container reported
+// it was not ready, until it was ready again or its pod was removed.
+func IntervalsFromEvents_ContainerNotReady(events monitorapi.Intervals, beginning, end time.Time) monitorapi.Intervals {
+	var intervals monitorapi.Intervals
+	notReady := map[string]map[string]monitorapi.EventInterval{}
+	closeInterval := func(key, container string, to time.Time) {
+		from, ok := notReady[key][container]
+		if !ok {
+			return
+		}
+		intervals = append(intervals, monitorapi.EventInterval{
+			Condition: monitorapi.NewCondition(
+				monitorapi.Warning,
+				from.TypedLocator(),
+				monitorapi.NewMessage().Reason(monitorapi.ContainerNotReadyReason).Build(),
+			),
+			From: from.From,
+			To:   to,
+		})
+		delete(notReady[key], container)
+	}
+
+	for _, event := range events {
+		locator := event.TypedLocator()
+		message := event.TypedMessage()
+		reason := message.Reason
+		key := podKey(locator)
+		switch locator.Type {
+		case monitorapi.LocatorTypeContainer:
+			container := locator.Keys[monitorapi.LocatorContainerKey]
+			switch reason {
+			case "NotReady":
+				if notReady[key] == nil {
+					notReady[key] = map[string]monitorapi.EventInterval{}
+				}
+				if _, ok := notReady[key][container]; !ok {
+					notReady[key][container] = event
+				}
+			case "Ready":
+				closeInterval(key, container, event.From)
+			}
+		case monitorapi.LocatorTypePod:
+			if isPodDeleted(reason) || isPodCreated(locator, message) {
+				for container := range notReady[key] {
+					closeInterval(key, container, event.From)
+				}
+			}
+		}
+	}
+	for key, containers := range notReady {
+		for container := range containers {
+			closeInterval(key, container, end)
+		}
+	}
+	sort.Sort(intervals)
+	return intervals
+}
+
+// containerExit is a container that exited and has not started again.
+type containerExit struct {
+	locator   monitorapi.Locator
+	code      string
+	from      time.Time
+	crashLoop bool
+}
+
+// IntervalsFromEvents_ContainerBackOff creates an interval for each time a container exited and
+// was started again, covering the time it was waiting to restart. Containers the kubelet put
+// into CrashLoopBackOff are warnings, and their back-off ends with the pod being deleted or the
+// run ending if they never restarted.
+func IntervalsFromEvents_ContainerBackOff(events monitorapi.Intervals, beginning, end time.Time) monitorapi.Intervals {
+	var intervals monitorapi.Intervals
+	exited := map[string]map[string]*containerExit{}
+	closeInterval := func(key, container string, to time.Time, restarted bool) {
+		exit, ok := exited[key][container]
+		if !ok {
+			return
+		}
+		delete(exited[key], container)
+		if !restarted && !exit.crashLoop {
+			// the container completed
+			return
+		}
+		level := monitorapi.Info
+		message := monitorapi.NewMessage().Reason(monitorapi.ContainerBackOffReason)
+		if exit.crashLoop {
+			level = monitorapi.Warning
+			message = message.Cause("CrashLoopBackOff")
+		}
+		if len(exit.code) > 0 {
+			message = message.WithAnnotation(monitorapi.AnnotationCode, exit.code)
+		}
+		if !restarted {
+			message = message.HumanMessage("container never restarted")
+		}
+		intervals = append(intervals, monitorapi.EventInterval{
+			Condition: monitorapi.NewCondition(level, exit.locator, message.Build()),
+			From:      exit.from,
+			To:        to,
+		})
+	}
+
+	for _, event := range events {
+		locator := event.TypedLocator()
+		message := event.TypedMessage()
+		key := podKey(locator)
+		switch locator.Type {
+		case monitorapi.LocatorTypeContainer:
+			container := locator.Keys[monitorapi.LocatorContainerKey]
+			switch message.Reason {
+			case "ContainerExit":
+				if exited[key] == nil {
+					exited[key] = map[string]*containerExit{}
+				}
+				if _, ok := exited[key][container]; !ok {
+					exited[key][container] = &containerExit{
+						locator: locator,
+						code:    message.Annotations[monitorapi.AnnotationCode],
+						from:    event.From,
+					}
+				}
+			case "ContainerWait":
+				if exit, ok := exited[key][container]; ok && message.Cause == "CrashLoopBackOff" {
+					exit.crashLoop = true
+				}
+			case "ContainerStart", "Ready":
+				closeInterval(key, container, event.From, true)
+			}
+		case monitorapi.LocatorTypePod:
+			if isPodDeleting(message.Reason) || isPodDeleted(message.Reason) || isPodCreated(locator, message) {
+				for container := range exited[key] {
+					closeInterval(key, container, event.From, false)
+				}
+			}
+		}
+	}
+	for key, containers := range exited {
+		for container := range containers {
+			closeInterval(key, container, end, false)
+		}
+	}
+	sort.Sort(intervals)
+	return intervals
+}
@@ -0,0 +1,89 @@
+package intervalcreation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func TestIntervalsFromEvents_PodStates(t *testing.T) {
+	base := time.Date(2021, 8, 1, 10, 0, 0, 0, time.UTC)
+	at := func(seconds int) time.Time { return base.Add(time.Duration(seconds) * time.Second) }
+	event := func(locator, message string, from time.Time) monitorapi.EventInterval {
+		return monitorapi.EventInterval{
+			Condition: monitorapi.Condition{Level: monitorapi.Info, Locator: locator, Message: message},
+			From:      from,
+			To:        from,
+		}
+	}
+	// the formats recorded by the pod monitor and by the event monitor for kubelet events
+	existing := "ns/openshift-etcd pod/etcd-0 node/master-0"
+	etcd := existing + " container/etcd"
+	created := "ns/openshift-dns pod/dns-1 node/worker-0"
+	dns := created + " container/dns"
+	events := monitorapi.Intervals{
+		// a pod that existed before the run crash loops
+		event(etcd, "reason/NotReady", at(10)),
+		event(etcd, "reason/ContainerExit code/1 cause/Error failed to start", at(11)),
+		event(etcd, "reason/ContainerWait cause/CrashLoopBackOff duration/5.00s back-off 10s restarting failed container=etcd pod=etcd-0_openshift-etcd(uid)", at(16)),
+		event(existing, "container/etcd reason/Created", at(19)),
+		event(existing, "container/etcd reason/Started", at(20)),
+		event(etcd, "reason/ContainerStart duration/9.00s", at(20)),
+		event(etcd, "reason/Ready", at(25)),
+		// a pod created during the run restarts once and is deleted
+		event("ns/openshift-dns pod/dns-1 node/", "reason/Created owner/DaemonSet", at(30)),
+		event("ns/openshift-dns pod/dns-1", "node/worker-0 reason/Scheduled", at(31)),
+		event(created, "container/dns reason/Pulled duration/1.000s image/registry/dns:latest", at(32)),
+		event(created, "container/dns reason/Created", at(33)),
+		event(created, "container/dns reason/Started", at(34)),
+		event(dns, "reason/ContainerStart duration/3.00s", at(34)),
+		event(created, "reason/Running owner/DaemonSet", at(35)),
+		event(dns, "reason/Ready", at(36)),
+		event(created, "reason/Ready owner/DaemonSet", at(36)),
+		event(dns, "reason/ContainerExit code/137 cause/Error", at(40)),
+		event(dns, "reason/NotReady", at(40)),
+		event(created, "container/dns reason/Created", at(42)),
+		event(created, "container/dns reason/Started", at(43)),
+		event(dns, "reason/ContainerStart duration/3.00s", at(43)),
+		event(dns, "reason/Restarted", at(43)),
+		event(dns, "reason/Ready", at(45)),
+		event(created, "reason/GracefulDelete duration/30s owner/DaemonSet", at(50)),
+		event(created, "container/dns reason/Killing", at(50)),
+		event(created, "reason/Deleted", at(55)),
+	}
+	check := func(name string, intervals monitorapi.Intervals, expected []monitorapi.EventInterval) {
+		t.Helper()
+		if len(intervals) != len(expected) {
+			t.Fatalf("unexpected %s intervals:\n%s", name, intervals.Strings())
+		}
+		for i := range expected {
+			interval := intervals[i]
+			if interval.Locator != expected[i].Locator || interval.Message != expected[i].Message || interval.Level != expected[i].Level || !interval.From.Equal(expected[i].From) || !interval.To.Equal(expected[i].To) {
+				t.Errorf("unexpected %s interval %d: %s", name, i, interval.String())
+			}
+		}
+	}
+	interval := func(level monitorapi.EventLevel, locator, message string, from, to time.Time) monitorapi.EventInterval {
+		return monitorapi.EventInterval{
+			Condition: monitorapi.Condition{Level: level, Locator: locator, Message: message},
+			From:      from,
+			To:        to,
+		}
+	}
+
+	check("pod phase", IntervalsFromEvents_PodPhases(events, at(0), at(60)), []monitorapi.EventInterval{
+		interval(monitorapi.Info, existing, "reason/PodPhase phase/Running", at(0), at(60)),
+		interval(monitorapi.Info, created, "reason/PodPhase phase/Pending", at(30), at(35)),
+		interval(monitorapi.Info, created, "reason/PodPhase phase/Running", at(35), at(50)),
+		interval(monitorapi.Info, created, "reason/PodPhase phase/Terminating", at(50), at(55)),
+	})
+	check("container not ready", IntervalsFromEvents_ContainerNotReady(events, at(0), at(60)), []monitorapi.EventInterval{
+		interval(monitorapi.Warning, etcd, "reason/ContainerNotReady", at(10), at(25)),
+		interval(monitorapi.Warning, dns, "reason/ContainerNotReady", at(40), at(45)),
+	})
+	check("container back-off", IntervalsFromEvents_ContainerBackOff(events, at(0), at(60)), []monitorapi.EventInterval{
+		interval(monitorapi.Warning, etcd, "reason/ContainerBackOff code/1 cause/CrashLoopBackOff", at(11), at(20)),
+		interval(monitorapi.Info, dns, "reason/ContainerBackOff code/137", at(40), at(43)),
+	})
+}
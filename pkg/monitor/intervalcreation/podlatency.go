@@ -0,0 +1,99 @@
+package intervalcreation
+
+import (
+	"sort"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+// podLifecycle holds the times a pod reached each point of its lifecycle.
+type podLifecycle struct {
+	locator   monitorapi.Locator
+	owner     string
+	created   time.Time
+	scheduled time.Time
+	running   time.Time
+	ready     time.Time
+	deleting  time.Time
+}
+
+// IntervalsFromEvents_PodLifecycleLatency creates an interval for each pod created during the
+// run from creation until it was scheduled, from then until it was running and from then
+// until it was ready, and for each pod deleted during the run from the deletion request until
+// it was removed. The owner annotation is the kind of the controller of the pod.
+func IntervalsFromEvents_PodLifecycleLatency(events monitorapi.Intervals, beginning, end time.Time) monitorapi.Intervals {
+	var intervals monitorapi.Intervals
+	pods := map[string]*podLifecycle{}
+	addInterval := func(pod *podLifecycle, latency string, from, to time.Time) {
+		if from.IsZero() || to.Before(from) {
+			return
+		}
+		message := monitorapi.NewMessage().
+			Reason(monitorapi.PodLifecycleLatencyReason).
+			WithAnnotation(monitorapi.AnnotationPhase, latency)
+		if len(pod.owner) > 0 {
+			message = message.WithAnnotation(monitorapi.AnnotationOwner, pod.owner)
+		}
+		intervals = append(intervals, monitorapi.EventInterval{
+			Condition: monitorapi.NewCondition(monitorapi.Info, pod.locator, message.Build()),
+			From:      from,
+			To:        to,
+		})
+	}
+
+	for _, event := range events {
+		locator := event.TypedLocator()
+		if locator.Type != monitorapi.LocatorTypePod {
+			continue
+		}
+		message := event.TypedMessage()
+		key := podKey(locator)
+		created := isPodCreated(locator, message)
+		pod, ok := pods[key]
+		if !ok || created {
+			// a pod recreated with the same name starts over
+			pod = &podLifecycle{}
+			pods[key] = pod
+		}
+		if len(locator.Keys[monitorapi.LocatorNodeKey]) > 0 || pod.locator.IsZero() {
+			pod.locator = podLocatorFrom(locator)
+		}
+		if owner := message.Annotations[monitorapi.AnnotationOwner]; len(owner) > 0 {
+			pod.owner = owner
+		}
+
+		switch {
+		case created:
+			pod.created = event.From
+		case message.Reason == "Scheduled":
+			if pod.scheduled.IsZero() {
+				// the scheduler's event names the node in its message rather than its locator
+				if node := message.Annotations[monitorapi.AnnotationKey(monitorapi.LocatorNodeKey)]; len(node) > 0 {
+					pod.locator.Keys[monitorapi.LocatorNodeKey] = node
+				}
+				pod.scheduled = event.From
+				addInterval(pod, monitorapi.PodCreateToScheduled, pod.created, pod.scheduled)
+			}
+		case message.Reason == "Running":
+			if pod.running.IsZero() {
+				pod.running = event.From
+				addInterval(pod, monitorapi.PodScheduledToRunning, pod.scheduled, pod.running)
+			}
+		case message.Reason == "Ready":
+			if pod.ready.IsZero() {
+				pod.ready = event.From
+				addInterval(pod, monitorapi.PodRunningToReady, pod.running, pod.ready)
+			}
+		case isPodDeleting(message.Reason):
+			if pod.deleting.IsZero() {
+				pod.deleting = event.From
+			}
+		case isPodDeleted(message.Reason):
+			addInterval(pod, monitorapi.PodDeleteToGone, pod.deleting, event.From)
+			delete(pods, key)
+		}
+	}
+	sort.Sort(intervals)
+	return intervals
+}
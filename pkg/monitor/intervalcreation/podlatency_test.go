@@ -0,0 +1,56 @@
+package intervalcreation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func TestIntervalsFromEvents_PodLifecycleLatency(t *testing.T) {
+	base := time.Date(2021, 8, 1, 10, 0, 0, 0, time.UTC)
+	at := func(seconds int) time.Time { return base.Add(time.Duration(seconds) * time.Second) }
+	event := func(locator, message string, from time.Time) monitorapi.EventInterval {
+		return monitorapi.EventInterval{
+			Condition: monitorapi.Condition{Level: monitorapi.Info, Locator: locator, Message: message},
+			From:      from,
+			To:        from,
+		}
+	}
+	// the formats recorded by the pod monitor and by the event monitor for scheduler and
+	// kubelet events
+	scheduled := "ns/openshift-dns pod/dns-1 node/worker-0"
+	events := monitorapi.Intervals{
+		// deleted before the run started, so only the deletion has a start
+		event("ns/openshift-dns pod/dns-0 node/worker-0", "reason/Deleted", at(1)),
+		event("ns/openshift-dns pod/dns-1 node/", "reason/Created owner/DaemonSet", at(2)),
+		event("ns/openshift-dns pod/dns-1", "node/worker-0 reason/Scheduled", at(5)),
+		event(scheduled, "container/dns reason/Pulled duration/1.000s image/registry/dns:latest", at(8)),
+		event(scheduled, "container/dns reason/Created", at(10)),
+		event(scheduled, "container/dns reason/Started", at(12)),
+		event(scheduled, "reason/Running owner/DaemonSet", at(15)),
+		event(scheduled+" container/dns", "reason/Ready", at(18)),
+		event(scheduled, "reason/Ready owner/DaemonSet", at(20)),
+		event(scheduled, "reason/GracefulDelete duration/30s owner/DaemonSet", at(30)),
+		event(scheduled, "container/dns reason/Killing", at(30)),
+		event(scheduled, "reason/Deleted", at(40)),
+	}
+	intervals := IntervalsFromEvents_PodLifecycleLatency(events, at(0), at(60))
+	if len(intervals) != 4 {
+		t.Fatalf("unexpected intervals:\n%s", intervals.Strings())
+	}
+	for i, expected := range []struct {
+		message  string
+		from, to time.Time
+	}{
+		{"reason/PodLifecycleLatency phase/CreateToScheduled owner/DaemonSet", at(2), at(5)},
+		{"reason/PodLifecycleLatency phase/ScheduledToRunning owner/DaemonSet", at(5), at(15)},
+		{"reason/PodLifecycleLatency phase/RunningToReady owner/DaemonSet", at(15), at(20)},
+		{"reason/PodLifecycleLatency phase/DeleteToGone owner/DaemonSet", at(30), at(40)},
+	} {
+		interval := intervals[i]
+		if interval.Locator != scheduled || interval.Message != expected.message || !interval.From.Equal(expected.from) || !interval.To.Equal(expected.to) {
+			t.Errorf("unexpected interval %d: %s", i, interval.String())
+		}
+	}
+}
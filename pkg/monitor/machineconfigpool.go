@@ -0,0 +1,139 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/cache"
+)
+
+var machineConfigPoolResource = schema.GroupVersionResource{Group: "machineconfiguration.openshift.io", Version: "v1", Resource: "machineconfigpools"}
+
+// machineConfigPoolsServed reports whether the cluster serves machineconfigpools. Clusters
+// without the machine config operator, such as MicroShift or HyperShift guests, do not.
+func machineConfigPoolsServed(client discovery.DiscoveryInterface) (bool, error) {
+	resources, err := client.ServerResourcesForGroupVersion(machineConfigPoolResource.GroupVersion().String())
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	for _, resource := range resources.APIResources {
+		if resource.Name == machineConfigPoolResource.Resource {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// startMachineConfigPoolMonitoring watches the machineconfigpools if the cluster serves them,
+// so that their absence is not recorded as an error contacting the API. If discovery fails
+// the failure is recorded and the rest of the monitor runs without watching the pools.
+// TODO: use the typed client when the machine config types are in openshift/api
+func startMachineConfigPoolMonitoring(ctx context.Context, m Recorder, discoveryClient discovery.DiscoveryInterface, client dynamic.Interface) {
+	served, err := machineConfigPoolsServed(discoveryClient)
+	if err != nil {
+		m.Record(monitorapi.Condition{
+			Level:   monitorapi.Warning,
+			Locator: "kube-apiserver",
+			Message: fmt.Sprintf("unable to determine whether machineconfigpools are served, not monitoring them: %v", err),
+		})
+		return
+	}
+	if !served {
+		return
+	}
+
+	mcpInformer := cache.NewSharedIndexInformer(
+		NewErrorRecordingListWatcher(m, &cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				return client.Resource(machineConfigPoolResource).List(ctx, options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				return client.Resource(machineConfigPoolResource).Watch(ctx, options)
+			},
+		}),
+		&unstructured.Unstructured{},
+		time.Hour,
+		nil,
+	)
+
+	mcpInformer.AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			UpdateFunc: func(old, obj interface{}) {
+				pool, ok := obj.(*unstructured.Unstructured)
+				if !ok {
+					return
+				}
+				oldPool, ok := old.(*unstructured.Unstructured)
+				if !ok {
+					return
+				}
+				if pool.GetUID() != oldPool.GetUID() {
+					return
+				}
+				m.Record(machineConfigPoolChanges(pool, oldPool)...)
+			},
+		},
+	)
+
+	go mcpInformer.Run(ctx.Done())
+}
+
+// machineConfigPoolChanges records when the Updating condition of a pool changes.
+func machineConfigPoolChanges(pool, oldPool *unstructured.Unstructured) []monitorapi.Condition {
+	updating, oldUpdating := machineConfigPoolCondition(pool, "Updating"), machineConfigPoolCondition(oldPool, "Updating")
+	if updating == oldUpdating {
+		return nil
+	}
+	locator := monitorapi.LocateMachineConfigPool(pool.GetName())
+	switch updating {
+	case "True":
+		config, _, _ := unstructured.NestedString(pool.Object, "spec", "configuration", "name")
+		return []monitorapi.Condition{monitorapi.NewCondition(
+			monitorapi.Info,
+			locator,
+			monitorapi.NewMessage().Reason(monitorapi.MachineConfigPoolUpdatingReason).
+				WithAnnotation(monitorapi.AnnotationConfig, config).HumanMessage("pool started updating").Build(),
+		)}
+	case "False":
+		if len(oldUpdating) == 0 {
+			return nil
+		}
+		config, _, _ := unstructured.NestedString(pool.Object, "status", "configuration", "name")
+		return []monitorapi.Condition{monitorapi.NewCondition(
+			monitorapi.Info,
+			locator,
+			monitorapi.NewMessage().Reason(monitorapi.MachineConfigPoolUpdatedReason).
+				WithAnnotation(monitorapi.AnnotationConfig, config).HumanMessage("pool finished updating").Build(),
+		)}
+	}
+	return nil
+}
+
+// machineConfigPoolCondition returns the status of the condition of the pool, or an empty
+// string if it is not set.
+func machineConfigPoolCondition(pool *unstructured.Unstructured, conditionType string) string {
+	conditions, _, _ := unstructured.NestedSlice(pool.Object, "status", "conditions")
+	for _, condition := range conditions {
+		c, ok := condition.(map[string]interface{})
+		if !ok || c["type"] != conditionType {
+			continue
+		}
+		status, _ := c["status"].(string)
+		return status
+	}
+	return ""
+}
@@ -0,0 +1,84 @@
+package monitor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func TestMachineConfigPoolsServed(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		resources string
+		want      bool
+		wantErr   bool
+	}{
+		{
+			name:      "served",
+			status:    http.StatusOK,
+			resources: `{"kind":"APIResourceList","groupVersion":"machineconfiguration.openshift.io/v1","resources":[{"name":"machineconfigs","kind":"MachineConfig"},{"name":"machineconfigpools","kind":"MachineConfigPool"}]}`,
+			want:      true,
+		},
+		{
+			name:      "group without pools",
+			status:    http.StatusOK,
+			resources: `{"kind":"APIResourceList","groupVersion":"machineconfiguration.openshift.io/v1","resources":[{"name":"machineconfigs","kind":"MachineConfig"}]}`,
+		},
+		{
+			name:   "group not served",
+			status: http.StatusNotFound,
+		},
+		{
+			name:    "unavailable",
+			status:  http.StatusServiceUnavailable,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/apis/machineconfiguration.openshift.io/v1" {
+					t.Errorf("unexpected request: %s", r.URL.Path)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.resources))
+			}))
+			defer server.Close()
+
+			served, err := machineConfigPoolsServed(discovery.NewDiscoveryClientForConfigOrDie(&rest.Config{Host: server.URL}))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if served != tt.want {
+				t.Errorf("expected served %t, got %t", tt.want, served)
+			}
+		})
+	}
+}
+
+func TestStartMachineConfigPoolMonitoringDiscoveryError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m := NewMonitor()
+	startMachineConfigPoolMonitoring(ctx, m, discovery.NewDiscoveryClientForConfigOrDie(&rest.Config{Host: server.URL}), nil)
+
+	intervals := m.Intervals(time.Time{}, time.Time{})
+	if len(intervals) != 1 || intervals[0].Level != monitorapi.Warning || !strings.Contains(intervals[0].Message, "unable to determine whether machineconfigpools are served") {
+		t.Fatalf("expected the discovery error to be recorded, got %v", intervals)
+	}
+}
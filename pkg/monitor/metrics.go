@@ -0,0 +1,140 @@
+package monitor
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+// intervalMetrics derives Prometheus metrics from the intervals recorded by a monitor.
+type intervalMetrics struct {
+	// backends maps the locator of each polled backend to its name
+	backends map[string]string
+	// nodeNotReadySince is the time each node was last observed to become not ready
+	nodeNotReadySince map[string]time.Time
+
+	disruptionSeconds    *prometheus.CounterVec
+	disruptionDuration   *prometheus.HistogramVec
+	containerRestarts    *prometheus.CounterVec
+	operatorTransitions  *prometheus.CounterVec
+	nodeNotReadyDuration *prometheus.HistogramVec
+	e2eTestOutcomes      *prometheus.CounterVec
+}
+
+// newIntervalMetrics reports disruption for the built-in backends and the configured
+// backends, keyed by locator.
+func newIntervalMetrics(backends map[string]string) *intervalMetrics {
+	return &intervalMetrics{
+		backends:          DisruptionBackendLocatorsToName(backends),
+		nodeNotReadySince: make(map[string]time.Time),
+
+		disruptionSeconds: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "openshift_monitor_backend_disruption_seconds_total",
+			Help: "Seconds each monitored backend did not respond.",
+		}, []string{"backend"}),
+		disruptionDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "openshift_monitor_backend_disruption_duration_seconds",
+			Help:    "Duration of each period in which a monitored backend did not respond.",
+			Buckets: prometheus.ExponentialBuckets(1, 2, 10),
+		}, []string{"backend"}),
+		containerRestarts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "openshift_monitor_container_restarts_total",
+			Help: "Container restarts observed by the monitor.",
+		}, []string{"namespace"}),
+		operatorTransitions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "openshift_monitor_operator_condition_transitions_total",
+			Help: "Changes to the status of cluster operator conditions.",
+		}, []string{"operator", "condition", "status"}),
+		nodeNotReadyDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "openshift_monitor_node_not_ready_duration_seconds",
+			Help:    "Duration of each period in which a node was not ready.",
+			Buckets: prometheus.ExponentialBuckets(1, 2, 12),
+		}, []string{"roles"}),
+		e2eTestOutcomes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "openshift_monitor_e2e_tests_total",
+			Help: "E2E tests that finished, by result.",
+		}, []string{"result"}),
+	}
+}
+
+func (m *intervalMetrics) collectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		m.disruptionSeconds,
+		m.disruptionDuration,
+		m.containerRestarts,
+		m.operatorTransitions,
+		m.nodeNotReadyDuration,
+		m.e2eTestOutcomes,
+	}
+}
+
+// observe updates the metrics for a recorded interval. It is invoked by the monitor, which
+// serializes calls.
+func (m *intervalMetrics) observe(interval monitorapi.EventInterval) {
+	if backend, ok := m.backends[interval.Locator]; ok {
+		if monitorapi.IsDisruption(interval) && interval.To.After(interval.From) {
+			seconds := interval.To.Sub(interval.From).Seconds()
+			m.disruptionSeconds.WithLabelValues(backend).Add(seconds)
+			m.disruptionDuration.WithLabelValues(backend).Observe(seconds)
+		}
+		return
+	}
+
+	locator := interval.TypedLocator()
+	message := interval.TypedMessage()
+	switch locator.Type {
+	case monitorapi.LocatorTypeContainer:
+		if message.Reason == "Restarted" {
+			m.containerRestarts.WithLabelValues(locator.Keys[monitorapi.LocatorNamespaceKey]).Inc()
+		}
+
+	case monitorapi.LocatorTypeClusterOperator:
+		condition, status := message.Annotations[monitorapi.AnnotationCondition], message.Annotations[monitorapi.AnnotationStatus]
+		if len(condition) > 0 && len(status) > 0 {
+			m.operatorTransitions.WithLabelValues(locator.Keys[monitorapi.LocatorClusterOperatorKey], condition, status).Inc()
+		}
+
+	case monitorapi.LocatorTypeNode:
+		if message.Annotations[monitorapi.AnnotationCondition] != "Ready" {
+			return
+		}
+		node := locator.Keys[monitorapi.LocatorNodeKey]
+		switch message.Annotations[monitorapi.AnnotationStatus] {
+		case "True":
+			if since, ok := m.nodeNotReadySince[node]; ok {
+				delete(m.nodeNotReadySince, node)
+				m.nodeNotReadyDuration.WithLabelValues(message.Annotations[monitorapi.AnnotationRoles]).Observe(interval.From.Sub(since).Seconds())
+			}
+		case "False", "Unknown":
+			if _, ok := m.nodeNotReadySince[node]; !ok {
+				m.nodeNotReadySince[node] = interval.From
+			}
+		}
+
+	case monitorapi.LocatorTypeE2ETest:
+		if result, ok := message.Annotations["finishedStatus"]; ok {
+			m.e2eTestOutcomes.WithLabelValues(result).Inc()
+		}
+	}
+}
+
+// StartMetricsServer listens on address and serves Prometheus metrics derived from the
+// intervals recorded by the monitor at /metrics until the context is done. Backends are the
+// backends returned by StartDisruptionBackends.
+func StartMetricsServer(ctx context.Context, m *Monitor, address string, backends map[string]string, errOut io.Writer) error {
+	metrics := newIntervalMetrics(backends)
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(metrics.collectors()...)
+	if err := m.AddIntervalObserver(metrics.observe); err != nil {
+		return err
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	return listenAndServe(ctx, address, "monitor metrics", mux, errOut)
+}
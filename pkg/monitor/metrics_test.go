@@ -0,0 +1,51 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func TestIntervalMetrics(t *testing.T) {
+	m := NewMonitorWithInterval(0)
+	at := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	// recorded before the metrics are observed
+	m.RecordAt(at, monitorapi.Condition{Level: monitorapi.Info, Locator: "ns/a pod/b node/c container/d", Message: "reason/Restarted"})
+
+	metrics := newIntervalMetrics(nil)
+	if err := m.AddIntervalObserver(metrics.observe); err != nil {
+		t.Fatal(err)
+	}
+	m.RecordAt(at, monitorapi.Condition{Level: monitorapi.Info, Locator: "ns/a pod/e node/c container/d", Message: "reason/Restarted"})
+	m.RecordAt(at, monitorapi.Condition{Level: monitorapi.Warning, Locator: "clusteroperator/dns", Message: "condition/Degraded status/True reason/Failing changed: x"})
+	m.RecordAt(at, monitorapi.Condition{Level: monitorapi.Warning, Locator: "node/c", Message: "condition/Ready status/False reason/KubeletNotReady roles/worker changed"})
+	m.RecordAt(at.Add(30*time.Second), monitorapi.Condition{Level: monitorapi.Warning, Locator: "node/c", Message: "condition/Ready status/True reason/KubeletReady roles/worker changed"})
+	m.RecordAt(at, monitorapi.Condition{Level: monitorapi.Info, Locator: monitorapi.E2ETestLocator("test"), Message: "finishedStatus/Passed"})
+	m.EndInterval(m.StartInterval(at, monitorapi.Condition{
+		Level:   monitorapi.Error,
+		Locator: LocatorKubeAPIServerNewConnection,
+		Message: "kube-apiserver-new-connection stopped responding to GET requests over new connections",
+	}), at.Add(5*time.Second))
+
+	if v := testutil.ToFloat64(metrics.containerRestarts.WithLabelValues("a")); v != 2 {
+		t.Errorf("expected 2 restarts, got %v", v)
+	}
+	if v := testutil.ToFloat64(metrics.operatorTransitions.WithLabelValues("dns", "Degraded", "True")); v != 1 {
+		t.Errorf("expected 1 operator transition, got %v", v)
+	}
+	if v := testutil.ToFloat64(metrics.e2eTestOutcomes.WithLabelValues("Passed")); v != 1 {
+		t.Errorf("expected 1 passed test, got %v", v)
+	}
+	if v := testutil.ToFloat64(metrics.disruptionSeconds.WithLabelValues("kube-api-new-connections")); v != 5 {
+		t.Errorf("expected 5s of disruption, got %v", v)
+	}
+	if n := testutil.CollectAndCount(metrics.nodeNotReadyDuration); n != 1 {
+		t.Errorf("expected a node not ready observation, got %d", n)
+	}
+	if len(metrics.nodeNotReadySince) != 0 {
+		t.Errorf("expected the node to be ready, got %v", metrics.nodeNotReadySince)
+	}
+}
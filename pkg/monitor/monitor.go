@@ -11,25 +11,43 @@ import (
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/tools/cache"
 )
 
-// Monitor records events that have occurred in memory and can also periodically
-// sample results.
+// Monitor records events that have occurred and can also periodically sample results.
+// Events and sampled conditions are kept in an IntervalStore, which is in memory unless
+// the monitor is created with NewMonitorWithStore.
 type Monitor struct {
 	interval            time.Duration
 	samplers            []SamplerFunc
 	intervalCreationFns []IntervalCreationFunc
-
-	lock           sync.Mutex
-	events         monitorapi.Intervals
-	unsortedEvents monitorapi.Intervals
-	samples        []*sample
+	intervalObservers   []IntervalObserverFunc
+
+	lock sync.Mutex
+	// events holds recorded events and the intervals that have ended
+	events IntervalStore
+	// openIntervals are the intervals started by StartInterval that have not ended
+	openIntervals map[int]*monitorapi.EventInterval
+	nextInterval  int
+	// conditions holds the intervals of sampled conditions that are no longer reported
+	conditions IntervalStore
+	// activeConditions are the intervals of the conditions reported by the last sample
+	activeConditions map[conditionKey]*monitorapi.EventInterval
 
 	recordedResourceLock sync.Mutex
 	recordedResources    monitorapi.ResourcesMap
 }
 
+// conditionKey identifies a sampled condition across samples. The structured locator and
+// message hold maps, so conditions are not comparable and the rendered form is used.
+type conditionKey struct {
+	level   monitorapi.EventLevel
+	locator string
+	message string
+}
+
 // NewMonitor creates a monitor with the default sampling interval.
 func NewMonitor() *Monitor {
 	return NewMonitorWithInterval(15 * time.Second)
@@ -38,12 +56,36 @@ func NewMonitor() *Monitor {
 // NewMonitorWithInterval creates a monitor that samples at the provided
 // interval.
 func NewMonitorWithInterval(interval time.Duration) *Monitor {
+	return NewMonitorWithStore(interval, NewMemoryIntervalStore(), NewMemoryIntervalStore())
+}
+
+// NewMonitorWithStore creates a monitor that samples at the provided interval and keeps
+// recorded events and sampled conditions in the provided stores.
+func NewMonitorWithStore(interval time.Duration, events, conditions IntervalStore) *Monitor {
 	return &Monitor{
 		interval:          interval,
+		events:            events,
+		openIntervals:     make(map[int]*monitorapi.EventInterval),
+		conditions:        conditions,
+		activeConditions:  make(map[conditionKey]*monitorapi.EventInterval),
 		recordedResources: monitorapi.ResourcesMap{},
 	}
 }
 
+// Close ends any open intervals and writes out and releases the stores of the monitor.
+func (m *Monitor) Close() error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	var intervals monitorapi.Intervals
+	for _, interval := range m.openIntervals {
+		intervals = append(intervals, *interval)
+	}
+	sort.Sort(intervals)
+	m.openIntervals = make(map[int]*monitorapi.EventInterval)
+	errs := []error{m.events.Add(intervals...), m.events.Close(), m.conditions.Close()}
+	return utilerrors.NewAggregate(errs)
+}
+
 var _ Interface = &Monitor{}
 
 // StartSampling starts sampling every interval until the provided context is done.
@@ -177,7 +219,7 @@ func (m *Monitor) Record(conditions ...monitorapi.Condition) {
 	defer m.lock.Unlock()
 	t := time.Now().UTC()
 	for _, condition := range conditions {
-		m.events = append(m.events, monitorapi.EventInterval{
+		m.addEvents(monitorapi.EventInterval{
 			Condition: condition,
 			From:      t,
 			To:        t,
@@ -190,11 +232,13 @@ func (m *Monitor) Record(conditions ...monitorapi.Condition) {
 func (m *Monitor) StartInterval(t time.Time, condition monitorapi.Condition) int {
 	m.lock.Lock()
 	defer m.lock.Unlock()
-	m.unsortedEvents = append(m.unsortedEvents, monitorapi.EventInterval{
+	id := m.nextInterval
+	m.nextInterval++
+	m.openIntervals[id] = &monitorapi.EventInterval{
 		Condition: condition,
 		From:      t,
-	})
-	return len(m.unsortedEvents) - 1
+	}
+	return id
 }
 
 // EndInterval updates the To of the interval started by StartInterval if t is greater than
@@ -202,11 +246,15 @@ func (m *Monitor) StartInterval(t time.Time, condition monitorapi.Condition) int
 func (m *Monitor) EndInterval(startedInterval int, t time.Time) {
 	m.lock.Lock()
 	defer m.lock.Unlock()
-	if startedInterval < len(m.unsortedEvents) {
-		if m.unsortedEvents[startedInterval].From.Before(t) {
-			m.unsortedEvents[startedInterval].To = t
-		}
+	interval, ok := m.openIntervals[startedInterval]
+	if !ok {
+		return
+	}
+	if interval.From.Before(t) {
+		interval.To = t
 	}
+	delete(m.openIntervals, startedInterval)
+	m.addEvents(*interval)
 }
 
 // RecordAt captures one or more conditions at the provided time. All conditions are recorded
@@ -218,7 +266,7 @@ func (m *Monitor) RecordAt(t time.Time, conditions ...monitorapi.Condition) {
 	m.lock.Lock()
 	defer m.lock.Unlock()
 	for _, condition := range conditions {
-		m.unsortedEvents = append(m.unsortedEvents, monitorapi.EventInterval{
+		m.addEvents(monitorapi.EventInterval{
 			Condition: condition,
 			From:      t,
 			To:        t,
@@ -226,6 +274,35 @@ func (m *Monitor) RecordAt(t time.Time, conditions ...monitorapi.Condition) {
 	}
 }
 
+// AddIntervalObserver invokes fn with each recorded event and each interval started by
+// StartInterval once it has ended, beginning with those already recorded. Observers are
+// invoked while the monitor is locked and must not call back into the monitor.
+func (m *Monitor) AddIntervalObserver(fn IntervalObserverFunc) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	err := m.events.Walk(time.Time{}, time.Time{}, func(interval monitorapi.EventInterval) error {
+		fn(interval)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	m.intervalObservers = append(m.intervalObservers, fn)
+	return nil
+}
+
+// addEvents adds intervals to the event store. The caller must hold the lock.
+func (m *Monitor) addEvents(intervals ...monitorapi.EventInterval) {
+	if err := m.events.Add(intervals...); err != nil {
+		utilruntime.HandleError(fmt.Errorf("unable to store monitor events: %v", err))
+	}
+	for _, fn := range m.intervalObservers {
+		for _, interval := range intervals {
+			fn(interval)
+		}
+	}
+}
+
 func (m *Monitor) sample(hasPrevious bool) bool {
 	m.lock.Lock()
 	samplers := m.samplers
@@ -244,28 +321,75 @@ func (m *Monitor) sample(hasPrevious bool) bool {
 
 	m.lock.Lock()
 	defer m.lock.Unlock()
-	m.samples = append(m.samples, &sample{
-		at:         now,
-		conditions: conditions,
-	})
+	m.addSample(now, conditions)
 	return len(conditions) > 0
 }
 
-func (m *Monitor) snapshot() ([]*sample, monitorapi.Intervals, monitorapi.Intervals) {
-	m.lock.Lock()
-	defer m.lock.Unlock()
-	return m.samples, m.events, m.unsortedEvents
+// addSample extends the interval of each condition that was also reported by the previous
+// sample, starts an interval for each new condition, and moves the intervals of conditions
+// that are no longer reported to the condition store. The caller must hold the lock.
+func (m *Monitor) addSample(at time.Time, conditions []*monitorapi.Condition) {
+	active := make(map[conditionKey]*monitorapi.EventInterval, len(conditions))
+	for _, condition := range conditions {
+		key := conditionKey{level: condition.Level, locator: condition.Locator, message: condition.Message}
+		if _, ok := active[key]; ok {
+			continue
+		}
+		if interval, ok := m.activeConditions[key]; ok {
+			interval.To = at
+			active[key] = interval
+			delete(m.activeConditions, key)
+			continue
+		}
+		active[key] = &monitorapi.EventInterval{
+			Condition: *condition,
+			From:      at,
+			To:        at.Add(time.Second),
+		}
+	}
+
+	ended := make(monitorapi.Intervals, 0, len(m.activeConditions))
+	for _, interval := range m.activeConditions {
+		ended = append(ended, *interval)
+	}
+	sort.Sort(ended)
+	if err := m.conditions.Add(ended...); err != nil {
+		utilruntime.HandleError(fmt.Errorf("unable to store monitor conditions: %v", err))
+	}
+	m.activeConditions = active
+}
+
+// conditionIntervals returns the sorted intervals of sampled conditions that start within
+// (from,to]. The caller must hold the lock.
+func (m *Monitor) conditionIntervals(from, to time.Time) (monitorapi.Intervals, error) {
+	stored, err := m.conditions.Intervals(from, to)
+	if err != nil {
+		return nil, err
+	}
+	var active monitorapi.Intervals
+	for _, interval := range m.activeConditions {
+		if intervalInRange(*interval, from, to) {
+			active = append(active, *interval)
+		}
+	}
+	sort.Sort(active)
+	return mergeIntervals(stored, active), nil
 }
 
-// Conditions returns all conditions that were sampled in the interval
-// between from and to. If that does not include a sample interval, no
-// results will be returned. Intervals are returned in order of
-// their first sampling. A condition that was only sampled once is
-// returned with from == to. No duplicate conditions are returned
-// unless a sampling interval did not report that value.
+// Conditions returns the intervals of the sampled conditions that were first sampled
+// between from and to, in order of their first sampling. A condition that was first
+// sampled before from is not returned, even if samples between from and to still
+// reported it. A condition that was only sampled once ends a second after that sample.
+// No duplicate conditions are returned unless a sampling interval did not report that
+// value.
 func (m *Monitor) Conditions(from, to time.Time) monitorapi.Intervals {
-	samples, _, _ := m.snapshot()
-	return filterSamples(samples, from, to)
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	conditions, err := m.conditionIntervals(from, to)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("unable to read monitor conditions: %v", err))
+	}
+	return conditions
 }
 
 // EventIntervals returns all events that occur between from and to, including
@@ -273,9 +397,25 @@ func (m *Monitor) Conditions(from, to time.Time) monitorapi.Intervals {
 // Intervals are returned in order of their occurrence. The returned slice
 // is a copy of the monitor's state and is safe to update.
 func (m *Monitor) Intervals(from, to time.Time) monitorapi.Intervals {
-	samples, sortedEvents, unsortedEvents := m.snapshot()
+	m.lock.Lock()
+	events, err := m.events.Intervals(from, to)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("unable to read monitor events: %v", err))
+	}
+	conditions, err := m.conditionIntervals(from, to)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("unable to read monitor conditions: %v", err))
+	}
+	var open monitorapi.Intervals
+	for _, interval := range m.openIntervals {
+		if intervalInRange(*interval, from, to) {
+			open = append(open, *interval)
+		}
+	}
+	m.lock.Unlock()
+	sort.Sort(open)
 
-	intervals := mergeIntervals(sortedEvents.Slice(from, to), unsortedEvents.CopyAndSort(from, to), filterSamples(samples, from, to))
+	intervals := mergeIntervals(events, conditions, open)
 	originalLen := len(intervals)
 
 	// create additional intervals from events
@@ -291,65 +431,8 @@ func (m *Monitor) Intervals(from, to time.Time) monitorapi.Intervals {
 	return intervals
 }
 
-// filterSamples converts the sorted samples that are within [from,to) to a set of
-// intervals.
-// TODO: simplify this by having the monitor samplers produce intervals themselves
-//   and make the streaming print logic simply show transitions.
-func filterSamples(samples []*sample, from, to time.Time) monitorapi.Intervals {
-	if len(samples) == 0 {
-		return nil
-	}
-
-	if !from.IsZero() {
-		first := sort.Search(len(samples), func(i int) bool {
-			return samples[i].at.After(from)
-		})
-		if first == -1 {
-			return nil
-		}
-		samples = samples[first:]
-	}
-
-	if !to.IsZero() {
-		for i, sample := range samples {
-			if sample.at.After(to) {
-				samples = samples[:i]
-				break
-			}
-		}
-	}
-	if len(samples) == 0 {
-		return nil
-	}
-
-	intervals := make(monitorapi.Intervals, 0, len(samples)*2)
-	last, next := make(map[monitorapi.Condition]*monitorapi.EventInterval), make(map[monitorapi.Condition]*monitorapi.EventInterval)
-	for _, sample := range samples {
-		for _, condition := range sample.conditions {
-			interval, ok := last[*condition]
-			if ok {
-				interval.To = sample.at
-				next[*condition] = interval
-				continue
-			}
-			intervals = append(intervals, monitorapi.EventInterval{
-				Condition: *condition,
-				From:      sample.at,
-				To:        sample.at.Add(time.Second),
-			})
-			next[*condition] = &intervals[len(intervals)-1]
-		}
-		for k := range last {
-			delete(last, k)
-		}
-		last, next = next, last
-	}
-	return intervals
-}
-
-// mergeEvents returns a sorted list of all events provided as sources. This could be
-// more efficient by requiring all sources to be sorted and then performing a zipper
-// merge.
+// mergeIntervals returns a sorted list of all intervals provided as sources, each of which
+// must already be sorted.
 func mergeIntervals(sets ...monitorapi.Intervals) monitorapi.Intervals {
 	total := 0
 	for _, set := range sets {
@@ -357,8 +440,27 @@ func mergeIntervals(sets ...monitorapi.Intervals) monitorapi.Intervals {
 	}
 	merged := make(monitorapi.Intervals, 0, total)
 	for _, set := range sets {
-		merged = append(merged, set...)
+		if len(set) == 0 {
+			continue
+		}
+		if len(merged) == 0 || !intervalLess(set[0], merged[len(merged)-1]) {
+			merged = append(merged, set...)
+			continue
+		}
+		next := make(monitorapi.Intervals, 0, total)
+		i, j := 0, 0
+		for i < len(merged) && j < len(set) {
+			if intervalLess(set[j], merged[i]) {
+				next = append(next, set[j])
+				j++
+			} else {
+				next = append(next, merged[i])
+				i++
+			}
+		}
+		next = append(next, merged[i:]...)
+		next = append(next, set[j:]...)
+		merged = next
 	}
-	sort.Sort(merged)
 	return merged
 }
@@ -1,12 +1,13 @@
 package monitor
 
 import (
-	"reflect"
 	"testing"
 	"time"
 
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
-	"k8s.io/apimachinery/pkg/util/diff"
 )
 
 func TestMonitor_Newlines(t *testing.T) {
@@ -74,7 +75,7 @@ func TestMonitor_Events(t *testing.T) {
 			from: time.Unix(1, 0),
 			want: monitorapi.Intervals{
 				{Condition: monitorapi.Condition{Message: "2"}, From: time.Unix(2, 0), To: time.Unix(3, 0)},
-				{Condition: monitorapi.Condition{Message: "A"}, From: time.Unix(3, 0), To: time.Unix(3, 0)},
+				{Condition: monitorapi.Condition{Message: "A"}, From: time.Unix(3, 0), To: time.Unix(4, 0)},
 			},
 		},
 		{
@@ -84,23 +85,46 @@ func TestMonitor_Events(t *testing.T) {
 				{at: time.Unix(3, 0), conditions: []*monitorapi.Condition{{Message: "2"}, {Message: "A"}}},
 			},
 			want: monitorapi.Intervals{
-				{Condition: monitorapi.Condition{Message: "1"}, From: time.Unix(1, 0), To: time.Unix(1, 0)},
-				{Condition: monitorapi.Condition{Message: "A"}, From: time.Unix(1, 0), To: time.Unix(1, 0)},
+				{Condition: monitorapi.Condition{Message: "1"}, From: time.Unix(1, 0), To: time.Unix(2, 0)},
+				{Condition: monitorapi.Condition{Message: "A"}, From: time.Unix(1, 0), To: time.Unix(2, 0)},
 				{Condition: monitorapi.Condition{Message: "2"}, From: time.Unix(2, 0), To: time.Unix(3, 0)},
-				{Condition: monitorapi.Condition{Message: "A"}, From: time.Unix(3, 0), To: time.Unix(3, 0)},
+				{Condition: monitorapi.Condition{Message: "A"}, From: time.Unix(3, 0), To: time.Unix(4, 0)},
 			},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			m := &Monitor{
-				events:            tt.events,
-				samples:           tt.samples,
-				recordedResources: monitorapi.ResourcesMap{},
+			m := NewMonitorWithInterval(0)
+			m.events.Add(tt.events...)
+			for _, sample := range tt.samples {
+				m.addSample(sample.at, sample.conditions)
 			}
-			if got := m.Intervals(tt.from, tt.to); !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("%s", diff.ObjectReflectDiff(tt.want, got))
+			if diff := cmp.Diff(tt.want, m.Intervals(tt.from, tt.to), cmpopts.IgnoreUnexported(monitorapi.Message{})); diff != "" {
+				t.Errorf("unexpected intervals (-want +got):\n%s", diff)
 			}
 		})
 	}
 }
+
+func TestMonitor_Conditions(t *testing.T) {
+	m := NewMonitorWithInterval(0)
+	m.addSample(time.Unix(1, 0), []*monitorapi.Condition{{Message: "A"}})
+	m.addSample(time.Unix(2, 0), []*monitorapi.Condition{{Message: "A"}, {Message: "B"}})
+	m.addSample(time.Unix(3, 0), []*monitorapi.Condition{{Message: "A"}})
+
+	// A was first sampled before from, so it is not returned although later samples
+	// reported it
+	want := monitorapi.Intervals{
+		{Condition: monitorapi.Condition{Message: "B"}, From: time.Unix(2, 0), To: time.Unix(3, 0)},
+	}
+	if diff := cmp.Diff(want, m.Conditions(time.Unix(1, 0), time.Time{}), cmpopts.IgnoreUnexported(monitorapi.Message{})); diff != "" {
+		t.Errorf("unexpected conditions (-want +got):\n%s", diff)
+	}
+
+	want = monitorapi.Intervals{
+		{Condition: monitorapi.Condition{Message: "A"}, From: time.Unix(1, 0), To: time.Unix(3, 0)},
+	}
+	if diff := cmp.Diff(want, m.Conditions(time.Time{}, time.Unix(1, 0)), cmpopts.IgnoreUnexported(monitorapi.Message{})); diff != "" {
+		t.Errorf("unexpected conditions (-want +got):\n%s", diff)
+	}
+}
@@ -9,17 +9,7 @@ import (
 func BackendDisruptionSeconds(locator string, events Intervals) (time.Duration, []string, string) {
 	disruptionEvents := events.Filter(
 		func(i EventInterval) bool {
-			if i.Locator != locator {
-				return false
-			}
-			switch {
-			case strings.Contains(i.Message, "stopped responding to"):
-				return true
-			case strings.Contains(i.Message, "is not responding to"):
-				return true
-			default:
-				return false
-			}
+			return i.Locator == locator && IsDisruption(i)
 		},
 	)
 	disruptionMessages := disruptionEvents.Strings()
@@ -36,3 +26,34 @@ func BackendDisruptionSeconds(locator string, events Intervals) (time.Duration,
 	}
 	return disruptionEvents.Duration(0, 1*time.Second), disruptionMessages, connectionType
 }
+
+// IsDisruption returns true if the interval records a backend that was not responding.
+func IsDisruption(interval EventInterval) bool {
+	switch {
+	case strings.Contains(interval.Message, "stopped responding to"):
+		return true
+	case strings.Contains(interval.Message, "is not responding to"):
+		return true
+	default:
+		return false
+	}
+}
+
+const (
+	// InjectedDisruptionStartedReason and InjectedDisruptionEndedReason are the reasons of the
+	// cluster events a test records when it deliberately disrupts an object in the cluster and
+	// when it stops.
+	InjectedDisruptionStartedReason = "InjectedDisruptionStarted"
+	InjectedDisruptionEndedReason   = "InjectedDisruptionEnded"
+	// InjectedDisruptionReason is the reason of the interval covering an injected disruption.
+	InjectedDisruptionReason = "InjectedDisruption"
+
+	// AnnotationInjector names the injector that disrupted the located object.
+	AnnotationInjector AnnotationKey = "injector"
+)
+
+// IsInjectedDisruption returns true if the interval covers a disruption a test caused on
+// purpose.
+func IsInjectedDisruption(interval EventInterval) bool {
+	return interval.TypedMessage().Reason == InjectedDisruptionReason
+}
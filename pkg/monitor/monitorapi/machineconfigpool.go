@@ -0,0 +1,11 @@
+package monitorapi
+
+const (
+	// MachineConfigPoolUpdatingReason is recorded when a pool starts rolling out a new
+	// configuration to its nodes and MachineConfigPoolUpdatedReason when all of its nodes
+	// have it.
+	MachineConfigPoolUpdatingReason = "MachineConfigPoolUpdating"
+	MachineConfigPoolUpdatedReason  = "MachineConfigPoolUpdated"
+	// MachineConfigPoolUpdateReason is the reason of the interval covering the rollout.
+	MachineConfigPoolUpdateReason = "MachineConfigPoolUpdate"
+)
@@ -0,0 +1,38 @@
+package monitorapi
+
+const (
+	// PodLifecycleLatencyReason is the reason of the intervals measuring how long a pod took
+	// to move between two points of its lifecycle. The phase annotation names the latency.
+	PodLifecycleLatencyReason = "PodLifecycleLatency"
+
+	// PodCreateToScheduled is the time from creation until the pod was bound to a node.
+	PodCreateToScheduled = "CreateToScheduled"
+	// PodScheduledToRunning is the time from binding until the pod was running.
+	PodScheduledToRunning = "ScheduledToRunning"
+	// PodRunningToReady is the time from running until the pod was ready.
+	PodRunningToReady = "RunningToReady"
+	// PodDeleteToGone is the time from the deletion request until the pod was removed.
+	PodDeleteToGone = "DeleteToGone"
+)
+
+// PodLifecycleLatencies are the latencies measured for each pod, in lifecycle order.
+var PodLifecycleLatencies = []string{PodCreateToScheduled, PodScheduledToRunning, PodRunningToReady, PodDeleteToGone}
+
+const (
+	// PodPhaseReason is the reason of the intervals a pod spent in a phase, named by the
+	// phase annotation.
+	PodPhaseReason = "PodPhase"
+	// ContainerNotReadyReason is the reason of the intervals a container reported it was
+	// not ready.
+	ContainerNotReadyReason = "ContainerNotReady"
+	// ContainerBackOffReason is the reason of the intervals from a container exiting until it
+	// was started again.
+	ContainerBackOffReason = "ContainerBackOff"
+
+	// PodPhasePending is from creation until the pod was running.
+	PodPhasePending = "Pending"
+	// PodPhaseRunning is from when the pod was running until it was deleted.
+	PodPhaseRunning = "Running"
+	// PodPhaseTerminating is from the deletion request until the pod was removed.
+	PodPhaseTerminating = "Terminating"
+)
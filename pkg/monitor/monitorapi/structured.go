@@ -0,0 +1,423 @@
+package monitorapi
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// locatorKeyOrder is the order keys are rendered in for each locator type, matching the
+// free-form locators the monitor has historically produced. Keys not listed are
+// rendered afterwards in sorted order.
+var locatorKeyOrder = map[LocatorType][]LocatorKey{
+	LocatorTypePod:               {LocatorNamespaceKey, LocatorPodKey, LocatorNodeKey},
+	LocatorTypeContainer:         {LocatorNamespaceKey, LocatorPodKey, LocatorNodeKey, LocatorContainerKey},
+	LocatorTypeNode:              {LocatorNodeKey},
+	LocatorTypeClusterOperator:   {LocatorClusterOperatorKey},
+	LocatorTypeAlert:             {LocatorAlertKey, LocatorNodeKey, LocatorNamespaceKey, LocatorPodKey, LocatorContainerKey},
+	LocatorTypeE2ETest:           {LocatorE2ETestKey},
+	LocatorTypeRoute:             {LocatorNamespaceKey, LocatorRouteKey, LocatorConnectionKey},
+	LocatorTypeDisruption:        {LocatorDisruptionKey, LocatorConnectionKey},
+	LocatorTypeMachineConfigPool: {LocatorMachineConfigPoolKey},
+}
+
+// knownLocatorKeys are always rendered as key/value, even when the value is empty.
+var knownLocatorKeys = map[LocatorKey]bool{
+	LocatorNamespaceKey:         true,
+	LocatorPodKey:               true,
+	LocatorNodeKey:              true,
+	LocatorContainerKey:         true,
+	LocatorClusterOperatorKey:   true,
+	LocatorAlertKey:             true,
+	LocatorE2ETestKey:           true,
+	LocatorRouteKey:             true,
+	LocatorDisruptionKey:        true,
+	LocatorConnectionKey:        true,
+	LocatorMachineConfigPoolKey: true,
+}
+
+func LocatePod(namespace, name, node string) Locator {
+	return Locator{
+		Type: LocatorTypePod,
+		Keys: map[LocatorKey]string{
+			LocatorNamespaceKey: namespace,
+			LocatorPodKey:       name,
+			LocatorNodeKey:      node,
+		},
+	}
+}
+
+func LocateContainer(namespace, pod, node, container string) Locator {
+	return Locator{
+		Type: LocatorTypeContainer,
+		Keys: map[LocatorKey]string{
+			LocatorNamespaceKey: namespace,
+			LocatorPodKey:       pod,
+			LocatorNodeKey:      node,
+			LocatorContainerKey: container,
+		},
+	}
+}
+
+func LocateNode(name string) Locator {
+	return Locator{Type: LocatorTypeNode, Keys: map[LocatorKey]string{LocatorNodeKey: name}}
+}
+
+func LocateClusterOperator(name string) Locator {
+	return Locator{Type: LocatorTypeClusterOperator, Keys: map[LocatorKey]string{LocatorClusterOperatorKey: name}}
+}
+
+func LocateMachineConfigPool(name string) Locator {
+	return Locator{Type: LocatorTypeMachineConfigPool, Keys: map[LocatorKey]string{LocatorMachineConfigPoolKey: name}}
+}
+
+func LocateE2ETest(testName string) Locator {
+	return Locator{Type: LocatorTypeE2ETest, Keys: map[LocatorKey]string{LocatorE2ETestKey: testName}}
+}
+
+// LocateE2ETestWithID identifies a test by its name and stable ID. The ID is only kept in
+// the structured locator, the rendered locator is the one LocateE2ETest renders.
+func LocateE2ETestWithID(testName, id string) Locator {
+	locator := LocateE2ETest(testName)
+	if len(id) > 0 {
+		locator.Keys[LocatorE2ETestIDKey] = id
+	}
+	return locator
+}
+
+// LocateAlert identifies a firing or pending alert. The node, namespace, pod and
+// container are taken from the alert labels and are omitted when empty.
+func LocateAlert(name, node, namespace, pod, container string) Locator {
+	keys := map[LocatorKey]string{LocatorAlertKey: name}
+	for key, value := range map[LocatorKey]string{
+		LocatorNodeKey:      node,
+		LocatorNamespaceKey: namespace,
+		LocatorPodKey:       pod,
+		LocatorContainerKey: container,
+	} {
+		if len(value) > 0 {
+			keys[key] = value
+		}
+	}
+	return Locator{Type: LocatorTypeAlert, Keys: keys}
+}
+
+func LocateRoute(namespace, name, connection string) Locator {
+	return Locator{
+		Type: LocatorTypeRoute,
+		Keys: map[LocatorKey]string{
+			LocatorNamespaceKey:  namespace,
+			LocatorRouteKey:      name,
+			LocatorConnectionKey: connection,
+		},
+	}
+}
+
+func LocateDisruption(backend, connection string) Locator {
+	return Locator{
+		Type: LocatorTypeDisruption,
+		Keys: map[LocatorKey]string{
+			LocatorDisruptionKey: backend,
+			LocatorConnectionKey: connection,
+		},
+	}
+}
+
+// IsZero returns true if the locator identifies nothing.
+func (l Locator) IsZero() bool {
+	return len(l.Type) == 0 && len(l.Keys) == 0
+}
+
+// String renders the locator in the key/value form used by Condition.Locator.
+func (l Locator) String() string {
+	if l.Type == LocatorTypeE2ETest {
+		return E2ETestLocator(l.Keys[LocatorE2ETestKey])
+	}
+
+	rendered := map[LocatorKey]bool{}
+	var parts []string
+	add := func(key LocatorKey) {
+		value, ok := l.Keys[key]
+		if !ok || rendered[key] {
+			return
+		}
+		rendered[key] = true
+		if len(value) == 0 && !knownLocatorKeys[key] {
+			parts = append(parts, string(key))
+			return
+		}
+		parts = append(parts, fmt.Sprintf("%s/%s", key, value))
+	}
+	for _, key := range locatorKeyOrder[l.Type] {
+		add(key)
+	}
+	var remaining []string
+	for key := range l.Keys {
+		if !rendered[key] {
+			remaining = append(remaining, string(key))
+		}
+	}
+	sort.Strings(remaining)
+	for _, key := range remaining {
+		add(LocatorKey(key))
+	}
+	return strings.Join(parts, " ")
+}
+
+// LocatorFromString parses a free-form locator such as "ns/a pod/b node/c" into a
+// Locator, inferring the type from the keys that are present.
+func LocatorFromString(locator string) Locator {
+	if len(locator) == 0 {
+		return Locator{}
+	}
+	if testName, ok := E2ETestFromLocator(locator); ok {
+		return LocateE2ETest(testName)
+	}
+
+	keys := map[LocatorKey]string{}
+	for key, value := range LocatorParts(locator) {
+		keys[LocatorKey(key)] = value
+	}
+	has := func(key LocatorKey) bool {
+		_, ok := keys[key]
+		return ok
+	}
+
+	locatorType := LocatorTypeOther
+	switch {
+	case has(LocatorAlertKey):
+		locatorType = LocatorTypeAlert
+	case has(LocatorDisruptionKey):
+		locatorType = LocatorTypeDisruption
+	case has(LocatorRouteKey):
+		locatorType = LocatorTypeRoute
+	case has(LocatorPodKey) && has(LocatorContainerKey):
+		locatorType = LocatorTypeContainer
+	case has(LocatorPodKey):
+		locatorType = LocatorTypePod
+	case has(LocatorClusterOperatorKey):
+		locatorType = LocatorTypeClusterOperator
+	case has(LocatorMachineConfigPoolKey):
+		locatorType = LocatorTypeMachineConfigPool
+	case has(LocatorNodeKey) && len(keys) == 1:
+		locatorType = LocatorTypeNode
+	}
+	return Locator{Type: locatorType, Keys: keys}
+}
+
+// leadingAnnotations are rendered before the reason, as in the condition changes the monitor
+// has historically recorded ("condition/Available status/False reason/Down ...").
+var leadingAnnotations = []AnnotationKey{
+	AnnotationCondition,
+	AnnotationStatus,
+}
+
+// annotationOrder is the order annotations are rendered in after the reason. Annotations
+// not listed are rendered afterwards in sorted order.
+var annotationOrder = []AnnotationKey{
+	AnnotationPhase,
+	AnnotationCode,
+	AnnotationDuration,
+	AnnotationMirrored,
+	AnnotationConfig,
+	AnnotationRoles,
+}
+
+// MessageBuilder assembles a Message.
+type MessageBuilder struct {
+	message Message
+}
+
+// NewMessage returns a builder for a Message.
+func NewMessage() *MessageBuilder {
+	return &MessageBuilder{}
+}
+
+func (b *MessageBuilder) Reason(reason string) *MessageBuilder {
+	b.message.Reason = reason
+	return b
+}
+
+func (b *MessageBuilder) Cause(cause string) *MessageBuilder {
+	b.message.Cause = cause
+	return b
+}
+
+func (b *MessageBuilder) WithAnnotation(key AnnotationKey, value string) *MessageBuilder {
+	if b.message.Annotations == nil {
+		b.message.Annotations = map[AnnotationKey]string{}
+	}
+	b.message.Annotations[key] = value
+	return b
+}
+
+func (b *MessageBuilder) HumanMessage(message string) *MessageBuilder {
+	b.message.HumanMessage = message
+	return b
+}
+
+func (b *MessageBuilder) HumanMessagef(format string, args ...interface{}) *MessageBuilder {
+	return b.HumanMessage(fmt.Sprintf(format, args...))
+}
+
+func (b *MessageBuilder) Build() Message {
+	return b.message
+}
+
+// IsZero returns true if the message has no content.
+func (m Message) IsZero() bool {
+	return len(m.Reason) == 0 && len(m.Cause) == 0 && len(m.HumanMessage) == 0 && len(m.Annotations) == 0
+}
+
+// String renders the message in the form used by Condition.Message: the condition and
+// status, the reason, the other annotations and then the cause, each as key/value, followed
+// by the human message. A message parsed by MessageFromString keeps the order of its
+// key/value pairs.
+func (m Message) String() string {
+	var parts []string
+	rendered := map[string]bool{}
+	add := func(key string) {
+		if rendered[key] {
+			return
+		}
+		switch key {
+		case "reason":
+			if len(m.Reason) == 0 && !containsKey(m.keys, key) {
+				return
+			}
+			parts = append(parts, "reason/"+m.Reason)
+		case "cause":
+			if len(m.Cause) == 0 && !containsKey(m.keys, key) {
+				return
+			}
+			parts = append(parts, "cause/"+m.Cause)
+		default:
+			value, ok := m.Annotations[AnnotationKey(key)]
+			if !ok {
+				return
+			}
+			parts = append(parts, fmt.Sprintf("%s/%s", key, value))
+		}
+		rendered[key] = true
+	}
+	for _, key := range strings.Fields(m.keys) {
+		add(key)
+	}
+	for _, key := range leadingAnnotations {
+		add(string(key))
+	}
+	add("reason")
+	for _, key := range annotationOrder {
+		add(string(key))
+	}
+	var remaining []string
+	for key := range m.Annotations {
+		if !rendered[string(key)] {
+			remaining = append(remaining, string(key))
+		}
+	}
+	sort.Strings(remaining)
+	for _, key := range remaining {
+		add(key)
+	}
+	add("cause")
+	if len(m.HumanMessage) > 0 {
+		parts = append(parts, m.HumanMessage)
+	}
+	return strings.Join(parts, " ")
+}
+
+func containsKey(keys string, key string) bool {
+	for _, k := range strings.Fields(keys) {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+var messageAnnotationRE = regexp.MustCompile(`^([a-zA-Z][a-zA-Z0-9.-]*)/(\S*)$`)
+
+// MessageFromString parses a free-form message such as
+// "reason/ContainerExit code/1 cause/Error failed" into a Message. Leading key/value
+// pairs become the reason, cause and annotations and the remainder is the human message.
+// String renders the parsed message as it was written.
+func MessageFromString(message string) Message {
+	var m Message
+	var keys []string
+	remaining := message
+	for len(remaining) > 0 {
+		parts := strings.SplitN(remaining, " ", 2)
+		match := messageAnnotationRE.FindStringSubmatch(parts[0])
+		if match == nil {
+			break
+		}
+		keys = append(keys, match[1])
+		switch key, value := match[1], match[2]; key {
+		case "reason":
+			m.Reason = value
+		case "cause":
+			m.Cause = value
+		default:
+			if m.Annotations == nil {
+				m.Annotations = map[AnnotationKey]string{}
+			}
+			m.Annotations[AnnotationKey(key)] = value
+		}
+		if len(parts) == 1 {
+			remaining = ""
+			break
+		}
+		remaining = parts[1]
+	}
+	m.HumanMessage = remaining
+	if m.String() != message {
+		m.keys = strings.Join(keys, " ")
+	}
+	return m
+}
+
+// NewCondition builds a condition from a structured locator and message, setting
+// Locator and Message to their rendered form so that string consumers are unaffected.
+func NewCondition(level EventLevel, locator Locator, message Message) Condition {
+	return Condition{
+		Level:             level,
+		Locator:           locator.String(),
+		Message:           message.String(),
+		StructuredLocator: locator,
+		StructuredMessage: message,
+	}
+}
+
+// TypedLocator returns the structured locator of the condition, parsing Locator if
+// the condition was not built with one.
+func (c Condition) TypedLocator() Locator {
+	if !c.StructuredLocator.IsZero() {
+		return c.StructuredLocator
+	}
+	return LocatorFromString(c.Locator)
+}
+
+// TypedMessage returns the structured message of the condition, parsing Message if
+// the condition was not built with one.
+func (c Condition) TypedMessage() Message {
+	if !c.StructuredMessage.IsZero() {
+		return c.StructuredMessage
+	}
+	return MessageFromString(c.Message)
+}
+
+// Code returns the numeric code/ annotation of the message, if present.
+func (m Message) Code() (int, bool) {
+	value, ok := m.Annotations[AnnotationCode]
+	if !ok {
+		return 0, false
+	}
+	code, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, false
+	}
+	return code, true
+}
@@ -0,0 +1,132 @@
+package monitorapi
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLocatorString(t *testing.T) {
+	var testCases = []struct {
+		locator  Locator
+		expected string
+	}{
+		{
+			locator:  LocatePod("ns1", "pod1", "node1"),
+			expected: "ns/ns1 pod/pod1 node/node1",
+		},
+		{
+			locator:  LocateContainer("ns1", "pod1", "", "c1"),
+			expected: "ns/ns1 pod/pod1 node/ container/c1",
+		},
+		{
+			locator:  LocateAlert("KubePodNotReady", "", "ns1", "pod1", ""),
+			expected: "alert/KubePodNotReady ns/ns1 pod/pod1",
+		},
+		{
+			locator:  LocateE2ETest("[sig-node] a test"),
+			expected: `e2e-test/"[sig-node] a test"`,
+		},
+		{
+			locator:  LocateDisruption("ingress-to-oauth-server", "new"),
+			expected: "disruption/ingress-to-oauth-server connection/new",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.expected, func(t *testing.T) {
+			if actual := tc.locator.String(); actual != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, actual)
+			}
+			if actual := LocatorFromString(tc.expected); !reflect.DeepEqual(actual, tc.locator) {
+				t.Errorf("expected %#v, got %#v", tc.locator, actual)
+			}
+		})
+	}
+}
+
+func TestLocateE2ETestWithID(t *testing.T) {
+	locator := LocateE2ETestWithID("[sig-node] a \"quoted\" test", "0123456789abcdef")
+	if expected := `e2e-test/"[sig-node] a \"quoted\" test"`; locator.String() != expected {
+		t.Errorf("expected the ID to be left out of %q, got %q", expected, locator.String())
+	}
+	if id := locator.Keys[LocatorE2ETestIDKey]; id != "0123456789abcdef" {
+		t.Errorf("expected the structured locator to keep the ID, got %q", id)
+	}
+}
+
+func TestMessageFromString(t *testing.T) {
+	var testCases = []struct {
+		message  string
+		expected Message
+	}{
+		{
+			message:  "reason/ContainerExit code/1 cause/Error failed to start",
+			expected: NewMessage().Reason("ContainerExit").Cause("Error").WithAnnotation(AnnotationCode, "1").HumanMessage("failed to start").Build(),
+		},
+		{
+			message:  "condition/Available status/False reason/Down changed: operator is down",
+			expected: NewMessage().Reason("Down").WithAnnotation(AnnotationCondition, "Available").WithAnnotation(AnnotationStatus, "False").HumanMessage("changed: operator is down").Build(),
+		},
+		{
+			message:  "reason/Failed (Evicted): see https://example.com/a",
+			expected: NewMessage().Reason("Failed").HumanMessage("(Evicted): see https://example.com/a").Build(),
+		},
+		{
+			message:  "created",
+			expected: NewMessage().HumanMessage("created").Build(),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.message, func(t *testing.T) {
+			if actual := MessageFromString(tc.message); !reflect.DeepEqual(actual, tc.expected) {
+				t.Errorf("expected %#v, got %#v", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestMessageRoundTrip(t *testing.T) {
+	// messages in the formats the monitor and the event recorders have historically written
+	for _, message := range []string{
+		"reason/ContainerExit code/1 cause/Error oops",
+		"reason/ContainerExit code/0 cause/ completed",
+		"reason/ContainerWait cause/CrashLoopBackOff duration/5.00s back-off 10s restarting failed container",
+		"reason/GracefulDelete duration/30s",
+		"reason/ForceDelete mirrored/false",
+		"container/etcd reason/Created",
+		"container/etcd reason/Pulled duration/1.234s image/quay.io/openshift/etcd:latest",
+		"node/worker-0 reason/Scheduled",
+		"condition/Available status/False reason/Down changed: operator is down",
+		"condition/Ready status/True reason/KubeletReady roles/worker changed",
+		"reason/NodeUpdate phase/Drain roles/worker drained node",
+		"reason/UpgradeStarted version/4.8.2 image/registry/release:4.8.2",
+		"reason/InjectedDisruptionStarted roles/worker injector/reboot rebooting node",
+		"alertstate/firing severity/critical",
+		"reason/Failed (Evicted): see https://example.com/a",
+		"roles/worker node is not ready",
+	} {
+		t.Run(message, func(t *testing.T) {
+			if actual := MessageFromString(message).String(); actual != message {
+				t.Errorf("expected %q, got %q", message, actual)
+			}
+		})
+	}
+}
+
+func TestNewCondition(t *testing.T) {
+	condition := NewCondition(Error, LocateContainer("ns1", "pod1", "node1", "c1"),
+		NewMessage().Reason("ContainerExit").Cause("Error").WithAnnotation(AnnotationCode, "1").HumanMessage("oops").Build())
+	if expected := "ns/ns1 pod/pod1 node/node1 container/c1"; condition.Locator != expected {
+		t.Errorf("expected locator %q, got %q", expected, condition.Locator)
+	}
+	if expected := "reason/ContainerExit code/1 cause/Error oops"; condition.Message != expected {
+		t.Errorf("expected message %q, got %q", expected, condition.Message)
+	}
+
+	legacy := Condition{Locator: condition.Locator, Message: condition.Message}
+	if !reflect.DeepEqual(legacy.TypedLocator(), condition.TypedLocator()) {
+		t.Errorf("expected %#v, got %#v", condition.TypedLocator(), legacy.TypedLocator())
+	}
+	if !reflect.DeepEqual(legacy.TypedMessage(), condition.TypedMessage()) {
+		t.Errorf("expected %#v, got %#v", condition.TypedMessage(), legacy.TypedMessage())
+	}
+}
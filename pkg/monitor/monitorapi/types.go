@@ -61,6 +61,84 @@ type Condition struct {
 
 	Locator string
 	Message string
+
+	// StructuredLocator and StructuredMessage hold the typed form of Locator and Message
+	// when the condition was built with NewCondition. They are empty for conditions that
+	// only set the strings; use TypedLocator and TypedMessage to read either kind.
+	StructuredLocator Locator
+	StructuredMessage Message
+}
+
+// LocatorType describes the kind of object a Locator identifies.
+type LocatorType string
+
+const (
+	LocatorTypePod               LocatorType = "Pod"
+	LocatorTypeContainer         LocatorType = "Container"
+	LocatorTypeNode              LocatorType = "Node"
+	LocatorTypeClusterOperator   LocatorType = "ClusterOperator"
+	LocatorTypeAlert             LocatorType = "Alert"
+	LocatorTypeE2ETest           LocatorType = "E2ETest"
+	LocatorTypeRoute             LocatorType = "Route"
+	LocatorTypeDisruption        LocatorType = "Disruption"
+	LocatorTypeMachineConfigPool LocatorType = "MachineConfigPool"
+	LocatorTypeOther             LocatorType = "Other"
+)
+
+// LocatorKey is the name of one part of a Locator, rendered as the prefix of a
+// key/value pair in the string form.
+type LocatorKey string
+
+const (
+	LocatorNamespaceKey         LocatorKey = "ns"
+	LocatorPodKey               LocatorKey = "pod"
+	LocatorNodeKey              LocatorKey = "node"
+	LocatorContainerKey         LocatorKey = "container"
+	LocatorClusterOperatorKey   LocatorKey = "clusteroperator"
+	LocatorAlertKey             LocatorKey = "alert"
+	LocatorE2ETestKey           LocatorKey = "e2e-test"
+	LocatorE2ETestIDKey         LocatorKey = "id"
+	LocatorRouteKey             LocatorKey = "route"
+	LocatorDisruptionKey        LocatorKey = "disruption"
+	LocatorConnectionKey        LocatorKey = "connection"
+	LocatorMachineConfigPoolKey LocatorKey = "machineconfigpool"
+)
+
+// Locator identifies the object an event or interval is about.
+type Locator struct {
+	Type LocatorType
+	Keys map[LocatorKey]string
+}
+
+// AnnotationKey is the name of a key/value pair in a Message, such as code/1.
+type AnnotationKey string
+
+const (
+	AnnotationCode      AnnotationKey = "code"
+	AnnotationDuration  AnnotationKey = "duration"
+	AnnotationCondition AnnotationKey = "condition"
+	AnnotationStatus    AnnotationKey = "status"
+	AnnotationRoles     AnnotationKey = "roles"
+	AnnotationPhase     AnnotationKey = "phase"
+	AnnotationMirrored  AnnotationKey = "mirrored"
+	AnnotationConfig    AnnotationKey = "config"
+	AnnotationOwner     AnnotationKey = "owner"
+	AnnotationContainer AnnotationKey = "container"
+)
+
+// Message describes what happened to the located object. Reason is a short
+// CamelCase identifier, Cause is the underlying reason reported by the object (if
+// any), Annotations carry additional key/value data and HumanMessage is free form.
+type Message struct {
+	Reason       string
+	Cause        string
+	HumanMessage string
+	Annotations  map[AnnotationKey]string
+
+	// keys is the space separated order of the key/value pairs of a message parsed by
+	// MessageFromString, if it differs from the order String renders them in, so that the
+	// message renders as it was written. It is a string so that it compares by value.
+	keys string
 }
 
 type EventInterval struct {
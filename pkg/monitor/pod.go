@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
@@ -162,17 +163,19 @@ func startPodMonitoring(ctx context.Context, m Recorder, client kubernetes.Inter
 					// terminal pods are immediately deleted (do not undergo graceful deletion)
 				default:
 					if *pod.DeletionGracePeriodSeconds == 0 {
-						conditions = append(conditions, monitorapi.Condition{
-							Level:   monitorapi.Info,
-							Locator: locatePod(pod),
-							Message: fmt.Sprintf("reason/ForceDelete mirrored/%t", isMirrorPod(pod)),
-						})
+						conditions = append(conditions, podCondition(
+							monitorapi.Info,
+							pod,
+							monitorapi.NewMessage().Reason("ForceDelete").
+								WithAnnotation(monitorapi.AnnotationMirrored, strconv.FormatBool(isMirrorPod(pod))),
+						))
 					} else {
-						conditions = append(conditions, monitorapi.Condition{
-							Level:   monitorapi.Info,
-							Locator: locatePod(pod),
-							Message: fmt.Sprintf("reason/GracefulDelete duration/%ds", *pod.DeletionGracePeriodSeconds),
-						})
+						conditions = append(conditions, podCondition(
+							monitorapi.Info,
+							pod,
+							monitorapi.NewMessage().Reason("GracefulDelete").
+								WithAnnotation(monitorapi.AnnotationDuration, fmt.Sprintf("%ds", *pod.DeletionGracePeriodSeconds)),
+						))
 					}
 				}
 			}
@@ -185,6 +188,17 @@ func startPodMonitoring(ctx context.Context, m Recorder, client kubernetes.Inter
 			}
 			return conditions
 		},
+		// record when the pod starts running and becomes ready to measure its startup latency
+		func(pod, oldPod *corev1.Pod) []monitorapi.Condition {
+			var conditions []monitorapi.Condition
+			if pod.Status.Phase == corev1.PodRunning && oldPod.Status.Phase != corev1.PodRunning {
+				conditions = append(conditions, podCondition(monitorapi.Info, pod, monitorapi.NewMessage().Reason("Running")))
+			}
+			if isPodReady(pod) && !isPodReady(oldPod) {
+				conditions = append(conditions, podCondition(monitorapi.Info, pod, monitorapi.NewMessage().Reason("Ready")))
+			}
+			return conditions
+		},
 		// check restarts, readiness drop outs, or other status changes
 		func(pod, oldPod *corev1.Pod) []monitorapi.Condition {
 			var conditions []monitorapi.Condition
@@ -230,19 +244,7 @@ func startPodMonitoring(ctx context.Context, m Recorder, client kubernetes.Inter
 					}
 				}
 				if t := s.State.Terminated; t != nil && previous.State.Terminated == nil {
-					if t.ExitCode != 0 {
-						conditions = append(conditions, monitorapi.Condition{
-							Level:   monitorapi.Error,
-							Locator: locatePodContainer(pod, s.Name),
-							Message: fmt.Sprintf("reason/ContainerExit code/%d cause/%s %s", t.ExitCode, t.Reason, t.Message),
-						})
-					} else {
-						conditions = append(conditions, monitorapi.Condition{
-							Level:   monitorapi.Info,
-							Locator: locatePodContainer(pod, s.Name),
-							Message: fmt.Sprintf("reason/ContainerExit code/0 cause/%s %s", t.Reason, t.Message),
-						})
-					}
+					conditions = append(conditions, containerExitCondition(pod, s.Name, t))
 				}
 				if s.RestartCount != previous.RestartCount && s.RestartCount != 0 {
 					conditions = append(conditions, monitorapi.Condition{
@@ -289,19 +291,7 @@ func startPodMonitoring(ctx context.Context, m Recorder, client kubernetes.Inter
 					}
 				}
 				if t := s.State.Terminated; t != nil && previous.State.Terminated == nil {
-					if t.ExitCode != 0 {
-						conditions = append(conditions, monitorapi.Condition{
-							Level:   monitorapi.Error,
-							Locator: locatePodContainer(pod, s.Name),
-							Message: fmt.Sprintf("reason/ContainerExit code/%d cause/%s %s", t.ExitCode, t.Reason, t.Message),
-						})
-					} else {
-						conditions = append(conditions, monitorapi.Condition{
-							Level:   monitorapi.Info,
-							Locator: locatePodContainer(pod, s.Name),
-							Message: fmt.Sprintf("reason/ContainerExit code/0 cause/%s %s", t.Reason, t.Message),
-						})
-					}
+					conditions = append(conditions, containerExitCondition(pod, s.Name, t))
 				}
 				if s.RestartCount != previous.RestartCount {
 					conditions = append(conditions, monitorapi.Condition{
@@ -357,11 +347,7 @@ func startPodMonitoring(ctx context.Context, m Recorder, client kubernetes.Inter
 				if pod.CreationTimestamp.Time.Before(startTime) {
 					return
 				}
-				m.Record(monitorapi.Condition{
-					Level:   monitorapi.Info,
-					Locator: locatePod(pod),
-					Message: "reason/Created",
-				})
+				m.Record(podCondition(monitorapi.Info, pod, monitorapi.NewMessage().Reason("Created")))
 			},
 			DeleteFunc: func(obj interface{}) {
 				pod, ok := obj.(*corev1.Pod)
@@ -437,6 +423,24 @@ func lastContainerTimeFromStatus(current, previous *corev1.ContainerStatus) time
 	return time.Time{}
 }
 
+// containerExitCondition records that a container exited, as an error if its exit code is not
+// zero. The message always names the cause, even when the kubelet reported no reason.
+func containerExitCondition(pod *corev1.Pod, containerName string, t *corev1.ContainerStateTerminated) monitorapi.Condition {
+	level := monitorapi.Info
+	if t.ExitCode != 0 {
+		level = monitorapi.Error
+	}
+	condition := monitorapi.NewCondition(
+		level,
+		podContainerLocator(pod, containerName),
+		monitorapi.NewMessage().Reason("ContainerExit").Cause(t.Reason).
+			WithAnnotation(monitorapi.AnnotationCode, strconv.Itoa(int(t.ExitCode))).
+			HumanMessage(t.Message).Build(),
+	)
+	condition.Message = fmt.Sprintf("reason/ContainerExit code/%d cause/%s %s", t.ExitCode, t.Reason, t.Message)
+	return condition
+}
+
 func conditionsForTransitioningContainer(pod *corev1.Pod, current, previous *corev1.ContainerStatus, init bool, reason, cause, message string, currentTime time.Time, lastContainerTime time.Time) []monitorapi.Condition {
 	var conditions []monitorapi.Condition
 	switch cause {
@@ -479,6 +483,22 @@ func conditionsForTransitioningContainer(pod *corev1.Pod, current, previous *cor
 	return conditions
 }
 
+// podCondition records a message about the pod. The structured message is annotated with
+// the kind of the controller of the pod, if any, so that pod lifecycle latencies can be
+// grouped by the kind of workload, while the message is written as it always has been.
+func podCondition(level monitorapi.EventLevel, pod *corev1.Pod, message *monitorapi.MessageBuilder) monitorapi.Condition {
+	condition := monitorapi.NewCondition(level, podLocator(pod), message.Build())
+	if owner := metav1.GetControllerOf(pod); owner != nil {
+		condition.StructuredMessage = message.WithAnnotation(monitorapi.AnnotationOwner, owner.Kind).Build()
+	}
+	return condition
+}
+
+func isPodReady(pod *corev1.Pod) bool {
+	c := findPodCondition(pod.Status.Conditions, corev1.PodReady)
+	return c != nil && c.Status == corev1.ConditionTrue
+}
+
 func isMirrorPod(pod *corev1.Pod) bool {
 	return len(pod.Annotations["kubernetes.io/config.mirror"]) > 0
 }
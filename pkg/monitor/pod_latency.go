@@ -0,0 +1,102 @@
+package monitor
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PodLifecycleLatencyList is the distribution of each pod lifecycle latency by namespace and
+// by the kind of the controller of the pod.
+type PodLifecycleLatencyList struct {
+	// Namespaces is keyed by namespace, then by latency, such as CreateToScheduled
+	Namespaces map[string]map[string]*LatencyDistribution
+	// OwnerKinds is keyed by the kind of the controller of the pod, or None, then by latency
+	OwnerKinds map[string]map[string]*LatencyDistribution
+}
+
+// LatencyDistribution summarizes a set of observed latencies.
+type LatencyDistribution struct {
+	Count int
+	P50   metav1.Duration
+	P90   metav1.Duration
+	P99   metav1.Duration
+	Max   metav1.Duration
+}
+
+// noPodOwner groups the pods that have no controller.
+const noPodOwner = "None"
+
+// ComputePodLifecycleLatency aggregates the pod lifecycle latency intervals.
+func ComputePodLifecycleLatency(events monitorapi.Intervals) *PodLifecycleLatencyList {
+	byNamespace := map[string]map[string][]time.Duration{}
+	byOwner := map[string]map[string][]time.Duration{}
+	add := func(groups map[string]map[string][]time.Duration, group, latency string, d time.Duration) {
+		if groups[group] == nil {
+			groups[group] = map[string][]time.Duration{}
+		}
+		groups[group][latency] = append(groups[group][latency], d)
+	}
+	for _, event := range events {
+		message := event.TypedMessage()
+		if message.Reason != monitorapi.PodLifecycleLatencyReason {
+			continue
+		}
+		latency := message.Annotations[monitorapi.AnnotationPhase]
+		owner := message.Annotations[monitorapi.AnnotationOwner]
+		if len(owner) == 0 {
+			owner = noPodOwner
+		}
+		d := event.To.Sub(event.From)
+		add(byNamespace, event.TypedLocator().Keys[monitorapi.LocatorNamespaceKey], latency, d)
+		add(byOwner, owner, latency, d)
+	}
+	return &PodLifecycleLatencyList{
+		Namespaces: latencyDistributions(byNamespace),
+		OwnerKinds: latencyDistributions(byOwner),
+	}
+}
+
+func latencyDistributions(groups map[string]map[string][]time.Duration) map[string]map[string]*LatencyDistribution {
+	distributions := map[string]map[string]*LatencyDistribution{}
+	for group, latencies := range groups {
+		distributions[group] = map[string]*LatencyDistribution{}
+		for latency, durations := range latencies {
+			distributions[group][latency] = newLatencyDistribution(durations)
+		}
+	}
+	return distributions
+}
+
+func newLatencyDistribution(durations []time.Duration) *LatencyDistribution {
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	return &LatencyDistribution{
+		Count: len(durations),
+		P50:   metav1.Duration{Duration: percentile(durations, 50)},
+		P90:   metav1.Duration{Duration: percentile(durations, 90)},
+		P99:   metav1.Duration{Duration: percentile(durations, 99)},
+		Max:   metav1.Duration{Duration: durations[len(durations)-1]},
+	}
+}
+
+// percentile returns the nearest-rank percentile of the sorted durations.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+func writePodLifecycleLatency(filename string, latency *PodLifecycleLatencyList) error {
+	jsonContent, err := json.MarshalIndent(latency, "", "    ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, jsonContent, 0644)
+}
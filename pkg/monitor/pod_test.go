@@ -0,0 +1,81 @@
+package monitor
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func TestContainerExitCondition(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-etcd", Name: "etcd-a"},
+		Spec:       corev1.PodSpec{NodeName: "master-0"},
+	}
+	tests := []struct {
+		name       string
+		terminated corev1.ContainerStateTerminated
+		level      monitorapi.EventLevel
+		message    string
+	}{
+		{
+			name:       "error",
+			terminated: corev1.ContainerStateTerminated{ExitCode: 1, Reason: "Error", Message: "failed to start"},
+			level:      monitorapi.Error,
+			message:    "reason/ContainerExit code/1 cause/Error failed to start",
+		},
+		{
+			name:       "no reason",
+			terminated: corev1.ContainerStateTerminated{ExitCode: 137},
+			level:      monitorapi.Error,
+			message:    "reason/ContainerExit code/137 cause/ ",
+		},
+		{
+			name:       "completed",
+			terminated: corev1.ContainerStateTerminated{Reason: "Completed"},
+			level:      monitorapi.Info,
+			message:    "reason/ContainerExit code/0 cause/Completed ",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition := containerExitCondition(pod, "etcd", &tt.terminated)
+			if condition.Level != tt.level || condition.Message != tt.message {
+				t.Errorf("expected %s %q, got %s %q", tt.level, tt.message, condition.Level, condition.Message)
+			}
+			if code, ok := condition.TypedMessage().Code(); !ok || code != int(tt.terminated.ExitCode) {
+				t.Errorf("expected code %d in the structured message, got %v", tt.terminated.ExitCode, condition.StructuredMessage)
+			}
+		})
+	}
+}
+
+func TestPodCondition(t *testing.T) {
+	controller := true
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       "openshift-dns",
+			Name:            "dns-1",
+			OwnerReferences: []metav1.OwnerReference{{Kind: "DaemonSet", Name: "dns", Controller: &controller}},
+		},
+		Spec: corev1.PodSpec{NodeName: "worker-0"},
+	}
+
+	for _, tt := range []struct {
+		message *monitorapi.MessageBuilder
+		want    string
+	}{
+		{message: monitorapi.NewMessage().Reason("Created"), want: "reason/Created"},
+		{message: monitorapi.NewMessage().Reason("GracefulDelete").WithAnnotation(monitorapi.AnnotationDuration, "30s"), want: "reason/GracefulDelete duration/30s"},
+	} {
+		condition := podCondition(monitorapi.Info, pod, tt.message)
+		if condition.Message != tt.want {
+			t.Errorf("expected message %q, got %q", tt.want, condition.Message)
+		}
+		if owner := condition.TypedMessage().Annotations[monitorapi.AnnotationOwner]; owner != "DaemonSet" {
+			t.Errorf("expected the structured message of %q to name the owner, got %q", tt.want, owner)
+		}
+	}
+}
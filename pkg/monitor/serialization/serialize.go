@@ -18,10 +18,28 @@ type EventInterval struct {
 	Locator string `json:"locator"`
 	Message string `json:"message"`
 
+	// StructuredLocator and StructuredMessage are only written for conditions that were built
+	// with them, which keeps files the size they were before they were added. When they are
+	// absent the locator and message strings are parsed on demand.
+	StructuredLocator *Locator `json:"structuredLocator,omitempty"`
+	StructuredMessage *Message `json:"structuredMessage,omitempty"`
+
 	From metav1.Time `json:"from"`
 	To   metav1.Time `json:"to"`
 }
 
+type Locator struct {
+	Type string            `json:"type"`
+	Keys map[string]string `json:"keys,omitempty"`
+}
+
+type Message struct {
+	Reason       string            `json:"reason,omitempty"`
+	Cause        string            `json:"cause,omitempty"`
+	HumanMessage string            `json:"humanMessage,omitempty"`
+	Annotations  map[string]string `json:"annotations,omitempty"`
+}
+
 // EventList is not an interval.  It is an instant.  The instant removes any ambiguity about "when"
 type EventIntervalList struct {
 	Items []EventInterval `json:"items"`
@@ -44,18 +62,25 @@ func EventsFromFile(filename string) (monitorapi.Intervals, error) {
 	if err := json.Unmarshal(data, &list); err != nil {
 		return nil, err
 	}
-	events := make(monitorapi.Intervals, len(list.Items))
+	events := make(monitorapi.Intervals, 0, len(list.Items))
 	for _, interval := range list.Items {
 		level, err := monitorapi.EventLevelFromString(interval.Level)
 		if err != nil {
 			return nil, err
 		}
+		condition := monitorapi.Condition{
+			Level:   level,
+			Locator: interval.Locator,
+			Message: interval.Message,
+		}
+		if interval.StructuredLocator != nil {
+			condition.StructuredLocator = toMonitorLocator(*interval.StructuredLocator)
+		}
+		if interval.StructuredMessage != nil {
+			condition.StructuredMessage = toMonitorMessage(*interval.StructuredMessage)
+		}
 		events = append(events, monitorapi.EventInterval{
-			Condition: monitorapi.Condition{
-				Level:   level,
-				Locator: interval.Locator,
-				Message: interval.Message,
-			},
+			Condition: condition,
 
 			From: interval.From.Time,
 			To:   interval.To.Time,
@@ -67,7 +92,7 @@ func EventsFromFile(filename string) (monitorapi.Intervals, error) {
 func EventsToJSON(events monitorapi.Intervals) ([]byte, error) {
 	outputEvents := []EventInterval{}
 	for _, curr := range events {
-		outputEvents = append(outputEvents, monitorEventIntervalToEventInterval(curr))
+		outputEvents = append(outputEvents, MonitorEventIntervalToEventInterval(curr))
 	}
 
 	sort.Sort(byTime(outputEvents))
@@ -89,7 +114,7 @@ func EventsIntervalsToJSON(events monitorapi.Intervals) ([]byte, error) {
 		if curr.From == curr.To {
 			continue
 		}
-		outputEvents = append(outputEvents, monitorEventIntervalToEventInterval(curr))
+		outputEvents = append(outputEvents, MonitorEventIntervalToEventInterval(curr))
 	}
 
 	sort.Sort(byTime(outputEvents))
@@ -97,7 +122,9 @@ func EventsIntervalsToJSON(events monitorapi.Intervals) ([]byte, error) {
 	return json.MarshalIndent(list, "", "    ")
 }
 
-func monitorEventIntervalToEventInterval(interval monitorapi.EventInterval) EventInterval {
+// MonitorEventIntervalToEventInterval converts an interval to its serialized form. The
+// structured locator and message are only included if the condition was built with them.
+func MonitorEventIntervalToEventInterval(interval monitorapi.EventInterval) EventInterval {
 	ret := EventInterval{
 		Level:   fmt.Sprintf("%v", interval.Level),
 		Locator: interval.Locator,
@@ -106,7 +133,57 @@ func monitorEventIntervalToEventInterval(interval monitorapi.EventInterval) Even
 		From: metav1.Time{Time: interval.From},
 		To:   metav1.Time{Time: interval.To},
 	}
+	if !interval.StructuredLocator.IsZero() {
+		ret.StructuredLocator = fromMonitorLocator(interval.StructuredLocator)
+	}
+	if !interval.StructuredMessage.IsZero() {
+		ret.StructuredMessage = fromMonitorMessage(interval.StructuredMessage)
+	}
+
+	return ret
+}
+
+func fromMonitorLocator(locator monitorapi.Locator) *Locator {
+	ret := &Locator{Type: string(locator.Type)}
+	if len(locator.Keys) > 0 {
+		ret.Keys = make(map[string]string, len(locator.Keys))
+		for k, v := range locator.Keys {
+			ret.Keys[string(k)] = v
+		}
+	}
+	return ret
+}
+
+func toMonitorLocator(locator Locator) monitorapi.Locator {
+	ret := monitorapi.Locator{Type: monitorapi.LocatorType(locator.Type)}
+	if len(locator.Keys) > 0 {
+		ret.Keys = make(map[monitorapi.LocatorKey]string, len(locator.Keys))
+		for k, v := range locator.Keys {
+			ret.Keys[monitorapi.LocatorKey(k)] = v
+		}
+	}
+	return ret
+}
 
+func fromMonitorMessage(message monitorapi.Message) *Message {
+	ret := &Message{Reason: message.Reason, Cause: message.Cause, HumanMessage: message.HumanMessage}
+	if len(message.Annotations) > 0 {
+		ret.Annotations = make(map[string]string, len(message.Annotations))
+		for k, v := range message.Annotations {
+			ret.Annotations[string(k)] = v
+		}
+	}
+	return ret
+}
+
+func toMonitorMessage(message Message) monitorapi.Message {
+	ret := monitorapi.Message{Reason: message.Reason, Cause: message.Cause, HumanMessage: message.HumanMessage}
+	if len(message.Annotations) > 0 {
+		ret.Annotations = make(map[monitorapi.AnnotationKey]string, len(message.Annotations))
+		for k, v := range message.Annotations {
+			ret.Annotations[monitorapi.AnnotationKey(k)] = v
+		}
+	}
 	return ret
 }
 
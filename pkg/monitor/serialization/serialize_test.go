@@ -0,0 +1,68 @@
+package monitorserialization
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func TestEventsFromFileWithoutStructuredFields(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "e2e-events.json")
+	legacy := `{"items": [{"level": "Error", "locator": "ns/ns1 pod/pod1 node/node1 container/c1", "message": "reason/ContainerExit code/1 cause/Error oops", "from": "2021-01-01T00:00:00Z", "to": "2021-01-01T00:00:01Z"}]}`
+	if err := ioutil.WriteFile(path, []byte(legacy), 0644); err != nil {
+		t.Fatal(err)
+	}
+	events, err := EventsFromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	if pod := events[0].TypedLocator().Keys[monitorapi.LocatorPodKey]; pod != "pod1" {
+		t.Errorf("expected pod1, got %q", pod)
+	}
+	if code, _ := events[0].TypedMessage().Code(); code != 1 {
+		t.Errorf("expected code 1, got %d", code)
+	}
+
+	// intervals recorded without structured fields are written without them
+	data, err := EventsToJSON(events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "structured") {
+		t.Errorf("expected no structured fields, got:\n%s", data)
+	}
+}
+
+func TestEventsRoundTrip(t *testing.T) {
+	from := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	events := monitorapi.Intervals{
+		{
+			Condition: monitorapi.NewCondition(monitorapi.Info, monitorapi.LocateNode("node1"),
+				monitorapi.NewMessage().Reason("NodeUpdate").WithAnnotation(monitorapi.AnnotationPhase, "Drain").Build()),
+			From: from,
+			To:   from.Add(time.Minute),
+		},
+	}
+	path := filepath.Join(t.TempDir(), "e2e-events.json")
+	if err := EventsToFile(path, events); err != nil {
+		t.Fatal(err)
+	}
+	actual, err := EventsFromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range actual {
+		actual[i].From, actual[i].To = actual[i].From.UTC(), actual[i].To.UTC()
+	}
+	if !reflect.DeepEqual(actual, events) {
+		t.Errorf("expected %#v, got %#v", events, actual)
+	}
+}
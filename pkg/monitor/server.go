@@ -0,0 +1,214 @@
+package monitor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+	"github.com/openshift/origin/test/extended/testdata"
+)
+
+// streamInterval is how often the interval stream checks the monitor for new intervals.
+const streamInterval = time.Second
+
+// chartRefreshScript is appended to the timeline chart when it is served live so that it
+// is periodically redrawn from the current intervals.
+const chartRefreshScript = `<script>
+    setInterval(() => {
+        fetch("api/intervals?duration=true").then((response) => response.json()).then((data) => {
+            document.querySelector('#chart').innerHTML = ''
+            renderChart(data)
+        })
+    }, 10000)
+</script>
+`
+
+// Server serves the intervals and resources recorded by a running monitor over HTTP:
+//
+//	/                      the e2e timeline chart, redrawn as intervals are recorded
+//	/api/intervals         the intervals as JSON, filtered by the from, to and locator
+//	                       parameters (duration=true omits instantaneous events)
+//	/api/intervals/stream  a server-sent event for each new interval as it starts
+//	/api/resources         the current state of the resources recorded by the monitor
+type Server struct {
+	monitor *Monitor
+	mux     *http.ServeMux
+}
+
+var _ http.Handler = &Server{}
+
+// NewServer returns a handler for the monitor's live API.
+func NewServer(m *Monitor) *Server {
+	s := &Server{
+		monitor: m,
+		mux:     http.NewServeMux(),
+	}
+	s.mux.HandleFunc("/", s.chart)
+	s.mux.HandleFunc("/api/intervals", s.intervals)
+	s.mux.HandleFunc("/api/intervals/stream", s.stream)
+	s.mux.HandleFunc("/api/resources", s.resources)
+	return s
+}
+
+// StartServer listens on address and serves the monitor's live API until the context is
+// done. It returns an error if the address cannot be listened on.
+func StartServer(ctx context.Context, m *Monitor, address string, errOut io.Writer) error {
+	return listenAndServe(ctx, address, "monitor API", NewServer(m), errOut)
+}
+
+// listenAndServe serves handler on address in the background until the context is done.
+func listenAndServe(ctx context.Context, address, name string, handler http.Handler, errOut io.Writer) error {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return fmt.Errorf("unable to serve the %s: %v", name, err)
+	}
+	server := &http.Server{Handler: handler}
+	go func() {
+		<-ctx.Done()
+		server.Close()
+	}()
+	go func() {
+		fmt.Fprintf(errOut, "Serving the %s on http://%s\n", name, listener.Addr())
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			fmt.Fprintf(errOut, "error: The %s stopped: %v\n", name, err)
+		}
+	}()
+	return nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// intervalQuery holds the filters accepted by the interval endpoints.
+type intervalQuery struct {
+	from, to time.Time
+	locator  string
+	duration bool
+}
+
+func parseIntervalQuery(r *http.Request) (*intervalQuery, error) {
+	q := &intervalQuery{
+		locator:  r.URL.Query().Get("locator"),
+		duration: r.URL.Query().Get("duration") == "true",
+	}
+	for name, t := range map[string]*time.Time{"from": &q.from, "to": &q.to} {
+		value := r.URL.Query().Get(name)
+		if len(value) == 0 {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be an RFC3339 time: %v", name, err)
+		}
+		*t = parsed
+	}
+	return q, nil
+}
+
+func (q *intervalQuery) matches(interval monitorapi.EventInterval) bool {
+	if len(q.locator) > 0 && !strings.Contains(interval.Locator, q.locator) {
+		return false
+	}
+	if q.duration && interval.From.Equal(interval.To) {
+		return false
+	}
+	return true
+}
+
+func (s *Server) intervals(w http.ResponseWriter, r *http.Request) {
+	q, err := parseIntervalQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	intervals := s.monitor.Intervals(q.from, q.to).Filter(q.matches)
+	data, err := monitorserialization.EventsToJSON(intervals)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+// stream sends each interval that starts after the from parameter (or the time of the
+// request) as a server-sent event, until the client disconnects.
+func (s *Server) stream(w http.ResponseWriter, r *http.Request) {
+	q, err := parseIntervalQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	last := q.from
+	if last.IsZero() {
+		last = time.Now().UTC()
+	}
+	ticker := time.NewTicker(streamInterval)
+	defer ticker.Stop()
+	for {
+		intervals := s.monitor.Intervals(last, q.to)
+		if len(intervals) > 0 {
+			last = intervals[len(intervals)-1].From
+		}
+		for _, interval := range intervals.Filter(q.matches) {
+			data, err := json.Marshal(monitorserialization.MonitorEventIntervalToEventInterval(interval))
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "data: %s\n\n", data)
+		}
+		flusher.Flush()
+
+		select {
+		case <-ticker.C:
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func (s *Server) resources(w http.ResponseWriter, r *http.Request) {
+	data, err := json.Marshal(s.monitor.CurrentResourceState())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+func (s *Server) chart(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	intervals := s.monitor.Intervals(time.Time{}, time.Time{})
+	data, err := monitorserialization.EventsIntervalsToJSON(intervals)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	html := bytes.ReplaceAll(testdata.MustAsset("e2echart/e2e-chart-template.html"), []byte("EVENT_INTERVAL_JSON_GOES_HERE"), data)
+	html = bytes.Replace(html, []byte("</body>"), []byte(chartRefreshScript+"</body>"), 1)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(html)
+}
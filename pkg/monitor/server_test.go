@@ -0,0 +1,71 @@
+package monitor
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+)
+
+func TestServerIntervals(t *testing.T) {
+	m := NewMonitorWithInterval(0)
+	at := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	m.RecordAt(at, monitorapi.Condition{Level: monitorapi.Info, Locator: "node/a", Message: "1"})
+	m.RecordAt(at.Add(time.Minute), monitorapi.Condition{Level: monitorapi.Info, Locator: "node/b", Message: "2"})
+	m.EndInterval(m.StartInterval(at.Add(2*time.Minute), monitorapi.Condition{Level: monitorapi.Error, Locator: "node/a", Message: "3"}), at.Add(3*time.Minute))
+
+	server := httptest.NewServer(NewServer(m))
+	defer server.Close()
+
+	for _, tc := range []struct {
+		query    string
+		messages []string
+	}{
+		{query: "", messages: []string{"1", "2", "3"}},
+		{query: "?locator=node/a", messages: []string{"1", "3"}},
+		{query: "?from=" + at.Format(time.RFC3339), messages: []string{"2", "3"}},
+		{query: "?duration=true", messages: []string{"3"}},
+	} {
+		resp, err := server.Client().Get(server.URL + "/api/intervals" + tc.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var list monitorserialization.EventIntervalList
+		err = json.NewDecoder(resp.Body).Decode(&list)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var messages []string
+		for _, item := range list.Items {
+			messages = append(messages, item.Message)
+		}
+		if strings.Join(messages, ",") != strings.Join(tc.messages, ",") {
+			t.Errorf("%q: expected %v, got %v", tc.query, tc.messages, messages)
+		}
+	}
+
+	resp, err := server.Client().Get(server.URL + "/api/intervals?from=yesterday")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != 400 {
+		t.Errorf("expected a bad request for an invalid time, got %d", resp.StatusCode)
+	}
+
+	resp, err = server.Client().Get(server.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	html, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if strings.Contains(string(html), "EVENT_INTERVAL_JSON_GOES_HERE") || !strings.Contains(string(html), "renderChart(data)") {
+		t.Errorf("expected the chart to be rendered with the current intervals")
+	}
+}
@@ -0,0 +1,346 @@
+package monitor
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+// IntervalStore holds the intervals recorded by a Monitor. The Monitor serializes access
+// to the store, so implementations do not need to be safe for concurrent use.
+type IntervalStore interface {
+	// Add records intervals whose start and end are known. Intervals may be added in
+	// any order, but adding them in order of From is cheapest.
+	Add(intervals ...monitorapi.EventInterval) error
+	// Intervals returns a sorted copy of the intervals that start after from and, if to
+	// is set, no later than to. The zero value of from returns intervals from the start.
+	Intervals(from, to time.Time) (monitorapi.Intervals, error)
+	// Walk invokes fn in order with the intervals Intervals would return, stopping at the
+	// first error fn returns. Stores that keep intervals on disk read them as they are
+	// visited rather than all at once.
+	Walk(from, to time.Time, fn func(monitorapi.EventInterval) error) error
+	// Close releases any resources held by the store.
+	Close() error
+}
+
+// intervalInRange returns true if the interval starts within (from,to]. Zero values of
+// from or to are unbounded.
+func intervalInRange(interval monitorapi.EventInterval, from, to time.Time) bool {
+	if !from.IsZero() && !interval.From.After(from) {
+		return false
+	}
+	if !to.IsZero() && interval.From.After(to) {
+		return false
+	}
+	return true
+}
+
+// intervalLess orders intervals the same way as sort.Sort(monitorapi.Intervals).
+func intervalLess(a, b monitorapi.EventInterval) bool {
+	return monitorapi.Intervals{a, b}.Less(0, 1)
+}
+
+// sliceSortedIntervals returns the subset of sorted intervals that start within (from,to].
+func sliceSortedIntervals(intervals monitorapi.Intervals, from, to time.Time) monitorapi.Intervals {
+	first := 0
+	if !from.IsZero() {
+		first = sort.Search(len(intervals), func(i int) bool { return intervals[i].From.After(from) })
+	}
+	last := len(intervals)
+	if !to.IsZero() {
+		last = sort.Search(len(intervals), func(i int) bool { return intervals[i].From.After(to) })
+	}
+	if first >= last {
+		return nil
+	}
+	return intervals[first:last]
+}
+
+// memoryIntervalStore keeps every interval in memory. Intervals added in order are
+// appended directly to the sorted set, while out of order intervals are sorted and merged
+// in on the next read so that repeated reads do not re-sort the whole set.
+type memoryIntervalStore struct {
+	sorted   monitorapi.Intervals
+	unsorted monitorapi.Intervals
+}
+
+// NewMemoryIntervalStore returns an IntervalStore that holds all intervals in memory. It
+// is the default store of a Monitor.
+func NewMemoryIntervalStore() IntervalStore {
+	return &memoryIntervalStore{}
+}
+
+func (s *memoryIntervalStore) Add(intervals ...monitorapi.EventInterval) error {
+	for _, interval := range intervals {
+		if len(s.unsorted) == 0 && (len(s.sorted) == 0 || !intervalLess(interval, s.sorted[len(s.sorted)-1])) {
+			s.sorted = append(s.sorted, interval)
+			continue
+		}
+		s.unsorted = append(s.unsorted, interval)
+	}
+	return nil
+}
+
+func (s *memoryIntervalStore) Intervals(from, to time.Time) (monitorapi.Intervals, error) {
+	return append(monitorapi.Intervals(nil), s.slice(from, to)...), nil
+}
+
+func (s *memoryIntervalStore) Walk(from, to time.Time, fn func(monitorapi.EventInterval) error) error {
+	for _, interval := range s.slice(from, to) {
+		if err := fn(interval); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// slice merges the out of order intervals into the sorted set and returns the intervals
+// that start within (from,to]. The result must not be modified.
+func (s *memoryIntervalStore) slice(from, to time.Time) monitorapi.Intervals {
+	if len(s.unsorted) > 0 {
+		sort.Sort(s.unsorted)
+		s.sorted = mergeIntervals(s.sorted, s.unsorted)
+		s.unsorted = nil
+	}
+	return sliceSortedIntervals(s.sorted, from, to)
+}
+
+func (s *memoryIntervalStore) Close() error {
+	return nil
+}
+
+// DefaultSegmentSize is the number of intervals a segmented store holds in memory before
+// writing them to a segment on disk.
+const DefaultSegmentSize = 10000
+
+// segment is an immutable file of intervals sorted by From, one JSON object per line.
+type segment struct {
+	path string
+	// from and to are the earliest and latest From of the intervals in the segment.
+	from, to time.Time
+}
+
+// segmentIntervalStore keeps at most segmentSize intervals in memory. When that many have
+// been added they are sorted and appended to the directory as a new segment file. Only the
+// time range of each segment is kept in memory, so a read opens just the segments that
+// overlap the requested range and streams their intervals in order.
+type segmentIntervalStore struct {
+	dir         string
+	segmentSize int
+
+	pending  *memoryIntervalStore
+	count    int
+	segments []segment
+}
+
+// NewSegmentIntervalStore returns an IntervalStore that writes intervals to segment files
+// in dir, holding at most segmentSize intervals in memory between reads. The directory is
+// created if it does not exist and must not be shared with another store.
+func NewSegmentIntervalStore(dir string, segmentSize int) (IntervalStore, error) {
+	if segmentSize <= 0 {
+		segmentSize = DefaultSegmentSize
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	existing, err := filepath.Glob(filepath.Join(dir, "segment-*.jsonl"))
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) > 0 {
+		return nil, fmt.Errorf("interval store directory %s already contains segments", dir)
+	}
+	return &segmentIntervalStore{
+		dir:         dir,
+		segmentSize: segmentSize,
+		pending:     &memoryIntervalStore{},
+	}, nil
+}
+
+func (s *segmentIntervalStore) Add(intervals ...monitorapi.EventInterval) error {
+	for _, interval := range intervals {
+		s.pending.Add(interval)
+		s.count++
+		if s.count >= s.segmentSize {
+			if err := s.flush(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// flush writes the pending intervals to a new segment.
+func (s *segmentIntervalStore) flush() error {
+	intervals, _ := s.pending.Intervals(time.Time{}, time.Time{})
+	if len(intervals) == 0 {
+		return nil
+	}
+	path := filepath.Join(s.dir, fmt.Sprintf("segment-%06d.jsonl", len(s.segments)))
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	encoder := json.NewEncoder(w)
+	for _, interval := range intervals {
+		if err := encoder.Encode(interval); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	s.segments = append(s.segments, segment{
+		path: path,
+		from: intervals[0].From,
+		to:   intervals[len(intervals)-1].From,
+	})
+	s.pending = &memoryIntervalStore{}
+	s.count = 0
+	return nil
+}
+
+func (s *segmentIntervalStore) Intervals(from, to time.Time) (monitorapi.Intervals, error) {
+	var intervals monitorapi.Intervals
+	err := s.Walk(from, to, func(interval monitorapi.EventInterval) error {
+		intervals = append(intervals, interval)
+		return nil
+	})
+	return intervals, err
+}
+
+// Walk merges the intervals of the segments and the pending intervals in order. Segments
+// may overlap when intervals were added out of order, so a segment is opened once the
+// merge reaches its earliest interval and closed when it has been read, which keeps only
+// the overlapping segments open at a time.
+func (s *segmentIntervalStore) Walk(from, to time.Time, fn func(monitorapi.EventInterval) error) error {
+	var remaining []segment
+	for _, segment := range s.segments {
+		if !from.IsZero() && !segment.to.After(from) {
+			continue
+		}
+		if !to.IsZero() && segment.from.After(to) {
+			continue
+		}
+		remaining = append(remaining, segment)
+	}
+	sort.SliceStable(remaining, func(i, j int) bool { return remaining[i].from.Before(remaining[j].from) })
+	pending := s.pending.slice(from, to)
+
+	var open []*segmentReader
+	defer func() {
+		for _, r := range open {
+			r.Close()
+		}
+	}()
+	for {
+		var next *monitorapi.EventInterval
+		nextReader := -1
+		if len(pending) > 0 {
+			next = &pending[0]
+		}
+		for i, r := range open {
+			if next == nil || intervalLess(r.next, *next) {
+				next, nextReader = &r.next, i
+			}
+		}
+		// a segment that starts no later than the next interval may hold an earlier one
+		if len(remaining) > 0 && (next == nil || !remaining[0].from.After(next.From)) {
+			r, err := openSegmentReader(remaining[0], from, to)
+			if err != nil {
+				return err
+			}
+			remaining = remaining[1:]
+			if r != nil {
+				open = append(open, r)
+			}
+			continue
+		}
+		if next == nil {
+			return nil
+		}
+		if err := fn(*next); err != nil {
+			return err
+		}
+		if nextReader < 0 {
+			pending = pending[1:]
+			continue
+		}
+		r := open[nextReader]
+		ok, err := r.advance(to)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			r.Close()
+			open = append(open[:nextReader], open[nextReader+1:]...)
+		}
+	}
+}
+
+// segmentReader reads the intervals of a segment in order.
+type segmentReader struct {
+	path    string
+	file    *os.File
+	decoder *json.Decoder
+	// next is the interval the reader is positioned at.
+	next monitorapi.EventInterval
+}
+
+// openSegmentReader opens a segment positioned at its first interval that starts within
+// (from,to], or returns nil if it has none.
+func openSegmentReader(segment segment, from, to time.Time) (*segmentReader, error) {
+	f, err := os.Open(segment.path)
+	if err != nil {
+		return nil, err
+	}
+	r := &segmentReader{path: segment.path, file: f, decoder: json.NewDecoder(bufio.NewReader(f))}
+	for {
+		ok, err := r.advance(to)
+		if err != nil || !ok {
+			f.Close()
+			return nil, err
+		}
+		if from.IsZero() || r.next.From.After(from) {
+			return r, nil
+		}
+	}
+}
+
+// advance reads the next interval of the segment. It returns false at the end of the
+// segment or once the intervals start after to.
+func (r *segmentReader) advance(to time.Time) (bool, error) {
+	var interval monitorapi.EventInterval
+	if err := r.decoder.Decode(&interval); err != nil {
+		if err == io.EOF {
+			return false, nil
+		}
+		return false, fmt.Errorf("unable to read interval segment %s: %v", r.path, err)
+	}
+	if !to.IsZero() && interval.From.After(to) {
+		return false, nil
+	}
+	r.next = interval
+	return true, nil
+}
+
+func (r *segmentReader) Close() error {
+	return r.file.Close()
+}
+
+func (s *segmentIntervalStore) Close() error {
+	return s.flush()
+}
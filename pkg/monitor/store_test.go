@@ -0,0 +1,121 @@
+package monitor
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"k8s.io/apimachinery/pkg/util/diff"
+)
+
+func TestIntervalStores(t *testing.T) {
+	at := func(seconds int64, message string) monitorapi.EventInterval {
+		return monitorapi.EventInterval{
+			Condition: monitorapi.Condition{Level: monitorapi.Info, Locator: "node/a", Message: message},
+			From:      time.Unix(seconds, 0).UTC(),
+			To:        time.Unix(seconds+1, 0).UTC(),
+		}
+	}
+	// out of order intervals span the segment boundaries
+	added := monitorapi.Intervals{at(1, "1"), at(3, "3"), at(2, "2"), at(6, "6"), at(4, "4"), at(5, "5"), at(8, "8"), at(7, "7")}
+
+	segmentStore, err := NewSegmentIntervalStore(filepath.Join(t.TempDir(), "events"), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stores := map[string]IntervalStore{
+		"memory":  NewMemoryIntervalStore(),
+		"segment": segmentStore,
+	}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			for _, interval := range added {
+				if err := store.Add(interval); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for _, tc := range []struct {
+				from, to time.Time
+				want     monitorapi.Intervals
+			}{
+				{want: monitorapi.Intervals{at(1, "1"), at(2, "2"), at(3, "3"), at(4, "4"), at(5, "5"), at(6, "6"), at(7, "7"), at(8, "8")}},
+				{from: time.Unix(2, 0), to: time.Unix(5, 0), want: monitorapi.Intervals{at(3, "3"), at(4, "4"), at(5, "5")}},
+				{from: time.Unix(6, 0), want: monitorapi.Intervals{at(7, "7"), at(8, "8")}},
+				{from: time.Unix(8, 0)},
+			} {
+				got, err := store.Intervals(tc.from, tc.to)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(got) == 0 && len(tc.want) == 0 {
+					continue
+				}
+				if !reflect.DeepEqual(got, tc.want) {
+					t.Errorf("(%v, %v]: %s", tc.from, tc.to, diff.ObjectReflectDiff(tc.want, got))
+				}
+				var walked monitorapi.Intervals
+				if err := store.Walk(tc.from, tc.to, func(interval monitorapi.EventInterval) error {
+					walked = append(walked, interval)
+					return nil
+				}); err != nil {
+					t.Fatal(err)
+				}
+				if !reflect.DeepEqual(walked, got) {
+					t.Errorf("(%v, %v]: walk %s", tc.from, tc.to, diff.ObjectReflectDiff(got, walked))
+				}
+			}
+
+			// the walk stops at the first error
+			stop := errors.New("stop")
+			var visited int
+			err := store.Walk(time.Time{}, time.Time{}, func(interval monitorapi.EventInterval) error {
+				visited++
+				if visited == 4 {
+					return stop
+				}
+				return nil
+			})
+			if err != stop || visited != 4 {
+				t.Errorf("expected the walk to stop after 4 intervals, got %d: %v", visited, err)
+			}
+			if err := store.Close(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+
+	if segments, _ := filepath.Glob(filepath.Join(segmentStore.(*segmentIntervalStore).dir, "segment-*.jsonl")); len(segments) != 3 {
+		t.Errorf("expected 3 segments, got %v", segments)
+	}
+}
+
+func TestSegmentIntervalStoreOverlappingSegments(t *testing.T) {
+	at := func(seconds int64) monitorapi.EventInterval {
+		return monitorapi.EventInterval{
+			Condition: monitorapi.Condition{Level: monitorapi.Info, Locator: "node/a", Message: fmt.Sprintf("%d", seconds)},
+			From:      time.Unix(seconds, 0).UTC(),
+			To:        time.Unix(seconds, 0).UTC(),
+		}
+	}
+	store, err := NewSegmentIntervalStore(filepath.Join(t.TempDir(), "events"), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	// each segment spans the ones written after it
+	if err := store.Add(at(1), at(5), at(2), at(6), at(3), at(4), at(7)); err != nil {
+		t.Fatal(err)
+	}
+	got, err := store.Intervals(time.Unix(1, 0), time.Unix(6, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := monitorapi.Intervals{at(2), at(3), at(4), at(5), at(6)}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected intervals: %s", diff.ObjectReflectDiff(want, got))
+	}
+}
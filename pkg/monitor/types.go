@@ -11,6 +11,9 @@ type IntervalCreationFunc func(intervals monitorapi.Intervals, beginning, end ti
 
 type SamplerFunc func(time.Time) []*monitorapi.Condition
 
+// IntervalObserverFunc is invoked with each interval recorded by a Monitor once its end is known.
+type IntervalObserverFunc func(interval monitorapi.EventInterval)
+
 type Interface interface {
 	Intervals(from, to time.Time) monitorapi.Intervals
 	Conditions(from, to time.Time) monitorapi.Intervals
@@ -0,0 +1,152 @@
+package monitor
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// UpgradeHop describes one of the sequential upgrades of a run, from the moment the test
+// requested it until the cluster completed it, failed, or the run ended.
+type UpgradeHop struct {
+	// Hop is the position of the upgrade in the path, starting at 1.
+	Hop     int
+	Version string
+	Image   string
+	// Result is Complete, Failed or Incomplete when the run ended before either.
+	Result  string
+	Failure string `json:",omitempty"`
+
+	Started time.Time
+	Ended   time.Time
+
+	// TimeToAcknowledged is how long the cluster version operator took to accept the upgrade.
+	TimeToAcknowledged *metav1.Duration `json:",omitempty"`
+	// TimeToVersionReached is how long it took until the cluster version operator reported
+	// that all operators were at the new version.
+	TimeToVersionReached *metav1.Duration `json:",omitempty"`
+	// TimeToPoolsUpdated is how long it took until all machine config pools had rolled out.
+	TimeToPoolsUpdated *metav1.Duration `json:",omitempty"`
+	// TimeToComplete is how long it took until all operators settled on the new version.
+	TimeToComplete *metav1.Duration `json:",omitempty"`
+
+	// BackendDisruption is the disruption observed between Started and Ended.
+	BackendDisruption *BackendDisruptionList
+}
+
+const (
+	UpgradeHopComplete   = "Complete"
+	UpgradeHopFailed     = "Failed"
+	UpgradeHopIncomplete = "Incomplete"
+)
+
+var (
+	upgradeEventReasonPattern  = regexp.MustCompile(`(?:^| )reason/(\S+)`)
+	upgradeEventVersionPattern = regexp.MustCompile(`(?:^| )version/(\S+)`)
+	upgradeEventImagePattern   = regexp.MustCompile(`(?:^| )image/(\S+)`)
+)
+
+// ComputeUpgradeHops splits the run into the upgrades recorded by the upgrade test through
+// cluster events on the cluster version and reports the timing and disruption of each.
+// Backends are the backends returned by StartDisruptionBackends.
+func ComputeUpgradeHops(events monitorapi.Intervals, backends map[string]string) []*UpgradeHop {
+	var hops []*UpgradeHop
+	var current *UpgradeHop
+	var last time.Time
+	for _, event := range events {
+		if event.To.After(last) {
+			last = event.To
+		}
+		if event.From.After(last) {
+			last = event.From
+		}
+		if !strings.HasSuffix(event.Locator, "clusterversion/cluster") {
+			continue
+		}
+		m := upgradeEventReasonPattern.FindStringSubmatch(event.Message)
+		if m == nil {
+			continue
+		}
+		reason := m[1]
+		if reason == "UpgradeStarted" {
+			current = &UpgradeHop{
+				Hop:     len(hops) + 1,
+				Result:  UpgradeHopIncomplete,
+				Started: event.From,
+			}
+			if m := upgradeEventVersionPattern.FindStringSubmatch(event.Message); m != nil {
+				current.Version = m[1]
+			}
+			if m := upgradeEventImagePattern.FindStringSubmatch(event.Message); m != nil {
+				current.Image = m[1]
+			}
+			hops = append(hops, current)
+			continue
+		}
+		if current == nil {
+			continue
+		}
+		since := &metav1.Duration{Duration: event.From.Sub(current.Started)}
+		switch reason {
+		case "UpgradeAcknowledged":
+			current.TimeToAcknowledged = since
+		case "UpgradeVersion":
+			current.TimeToVersionReached = since
+		case "UpgradePoolsComplete":
+			current.TimeToPoolsUpdated = since
+		case "UpgradeComplete":
+			current.TimeToComplete = since
+			current.Result = UpgradeHopComplete
+			current.Ended = event.From
+			current = nil
+		case "UpgradeFailed":
+			current.Result = UpgradeHopFailed
+			current.Failure = strings.TrimSpace(upgradeEventReasonPattern.ReplaceAllString(event.Message, ""))
+			current.Ended = event.From
+			current = nil
+		}
+	}
+
+	for _, hop := range hops {
+		if hop.Ended.IsZero() {
+			hop.Ended = last
+		}
+		hop.BackendDisruption = computeDisruptionData(intervalsBetween(events, hop.Started, hop.Ended), backends)
+	}
+	return hops
+}
+
+// intervalsBetween returns the intervals that overlap [from, to], truncated to that range.
+func intervalsBetween(events monitorapi.Intervals, from, to time.Time) monitorapi.Intervals {
+	var between monitorapi.Intervals
+	for _, event := range events {
+		end := event.To
+		if end.Before(event.From) {
+			end = event.From
+		}
+		if end.Before(from) || event.From.After(to) {
+			continue
+		}
+		if event.From.Before(from) {
+			event.From = from
+		}
+		if event.To.After(to) {
+			event.To = to
+		}
+		between = append(between, event)
+	}
+	return between
+}
+
+func writeUpgradeHops(filename string, hops []*UpgradeHop) error {
+	jsonContent, err := json.MarshalIndent(hops, "", "    ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, jsonContent, 0644)
+}
@@ -0,0 +1,83 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func TestComputeUpgradeHops(t *testing.T) {
+	base := time.Date(2021, 8, 1, 10, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return base.Add(time.Duration(minutes) * time.Minute) }
+	event := func(locator, message string, from, to time.Time) monitorapi.EventInterval {
+		return monitorapi.EventInterval{
+			Condition: monitorapi.Condition{Level: monitorapi.Info, Locator: locator, Message: message},
+			From:      from,
+			To:        to,
+		}
+	}
+	cv := "ns/openshift-cluster-version clusterversion/cluster"
+	events := monitorapi.Intervals{
+		event(cv, "reason/UpgradeStarted version/4.8.2 image/registry/release:4.8.2", at(0), at(0)),
+		event(cv, "reason/UpgradeAcknowledged version/4.8.2 image/registry/release:4.8.2", at(1), at(1)),
+		event(LocatorKubeAPIServerNewConnection, "kube-apiserver-new-connection stopped responding to GET requests over new connections", at(20), at(22)),
+		event(cv, "reason/UpgradeVersion version/4.8.2 image/4.8.2", at(40), at(40)),
+		event(cv, "reason/UpgradePoolsComplete version/4.8.2 image/registry/release:4.8.2", at(60), at(60)),
+		event(cv, "reason/UpgradeComplete version/4.8.2 image/registry/release:4.8.2", at(65), at(65)),
+		// spans both hops and counts towards each for the time it overlaps them
+		event(LocatorKubeAPIServerNewConnection, "kube-apiserver-new-connection stopped responding to GET requests over new connections", at(64), at(67)),
+		event(cv, "reason/UpgradeStarted version/0.0.0 image/registry/release:4.9.0", at(66), at(66)),
+		event(cv, "reason/UpgradeAcknowledged version/0.0.0 image/registry/release:4.9.0", at(67), at(67)),
+		event(cv, "reason/UpgradeFailed failed to reach cluster version: timed out", at(100), at(100)),
+		event(cv, "reason/UpgradeStarted version/0.0.0 image/registry/release:4.10.0", at(110), at(110)),
+		event("ns/e2e pod/web node/worker-1", "reason/Created", at(120), at(120)),
+	}
+
+	hops := ComputeUpgradeHops(events, nil)
+	if len(hops) != 3 {
+		t.Fatalf("expected 3 hops, got %d", len(hops))
+	}
+
+	first := hops[0]
+	if first.Hop != 1 || first.Version != "4.8.2" || first.Image != "registry/release:4.8.2" || first.Result != UpgradeHopComplete {
+		t.Errorf("unexpected first hop: %#v", first)
+	}
+	for _, phase := range []struct {
+		name     string
+		actual   time.Duration
+		expected time.Duration
+	}{
+		{"acknowledged", first.TimeToAcknowledged.Duration, time.Minute},
+		{"version reached", first.TimeToVersionReached.Duration, 40 * time.Minute},
+		{"pools updated", first.TimeToPoolsUpdated.Duration, time.Hour},
+		{"complete", first.TimeToComplete.Duration, 65 * time.Minute},
+	} {
+		if phase.actual != phase.expected {
+			t.Errorf("expected first hop %s after %s, got %s", phase.name, phase.expected, phase.actual)
+		}
+	}
+	if d := first.BackendDisruption.BackendDisruptions["kube-api-new-connections"].DisruptedDuration.Duration; d != 3*time.Minute {
+		t.Errorf("expected 3m of disruption in the first hop, got %s", d)
+	}
+
+	second := hops[1]
+	if second.Result != UpgradeHopFailed || second.Failure != "failed to reach cluster version: timed out" || !second.Ended.Equal(at(100)) {
+		t.Errorf("unexpected second hop: %#v", second)
+	}
+	if second.TimeToVersionReached != nil || second.TimeToAcknowledged == nil {
+		t.Errorf("unexpected second hop timing: %#v", second)
+	}
+	if d := second.BackendDisruption.BackendDisruptions["kube-api-new-connections"].DisruptedDuration.Duration; d != time.Minute {
+		t.Errorf("expected 1m of disruption in the second hop, got %s", d)
+	}
+
+	third := hops[2]
+	if third.Result != UpgradeHopIncomplete || !third.Ended.Equal(at(120)) || third.Image != "registry/release:4.10.0" {
+		t.Errorf("unexpected third hop: %#v", third)
+	}
+
+	if hops := ComputeUpgradeHops(events[2:3], nil); len(hops) != 0 {
+		t.Errorf("expected no hops without upgrade events, got %d", len(hops))
+	}
+}
@@ -0,0 +1,263 @@
+package monitor
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// UpgradePhases breaks one upgrade down into the time the cluster version operator, each
+// operator, each machine config pool and each node took, to show what made it slow.
+type UpgradePhases struct {
+	// Hop is the position of the upgrade in the path, starting at 1.
+	Hop     int
+	Version string
+	Image   string
+	Started time.Time
+	Ended   time.Time
+
+	Operators          []OperatorUpgradePhase
+	MachineConfigPools []UpgradePhase
+	Nodes              []NodeUpgradePhase
+
+	// CriticalPath is the chain of phases that accounts for when the upgrade ended: starting
+	// from the end, each phase is the one that finished last before the phase after it began.
+	// Time between two phases is not explained by any recorded phase.
+	CriticalPath []UpgradePhase
+}
+
+// UpgradePhase is part of an upgrade attributed to one object.
+type UpgradePhase struct {
+	Phase    string
+	Locator  string
+	From     time.Time
+	To       time.Time
+	Duration metav1.Duration
+}
+
+// OperatorUpgradePhase describes when an operator reached the new version.
+type OperatorUpgradePhase struct {
+	Name string
+	// VersionChanged is when the operator last reported a new version during the upgrade.
+	VersionChanged time.Time
+	// TimeToVersion is how long after the upgrade started the operator reported it.
+	TimeToVersion metav1.Duration
+	// Progressing is how long the operator reported Progressing=True during the upgrade.
+	Progressing metav1.Duration
+}
+
+// NodeUpgradePhase describes how long a node took to update, and how long it spent in each
+// phase of the update.
+type NodeUpgradePhase struct {
+	Name                  string
+	Roles                 string
+	From                  time.Time
+	To                    time.Time
+	Update                metav1.Duration
+	Drain                 metav1.Duration
+	OperatingSystemUpdate metav1.Duration
+	Reboot                metav1.Duration
+}
+
+const (
+	UpgradePhaseAcknowledge               = "Acknowledge"
+	UpgradePhaseOperatorUpdate            = "OperatorUpdate"
+	UpgradePhaseMachineConfigPoolUpdate   = "MachineConfigPoolUpdate"
+	UpgradePhaseNodeUpdate                = "NodeUpdate"
+	UpgradePhaseNodeDrain                 = "NodeDrain"
+	UpgradePhaseNodeOperatingSystemUpdate = "NodeOperatingSystemUpdate"
+	UpgradePhaseNodeReboot                = "NodeReboot"
+)
+
+// ComputeUpgradePhases breaks each upgrade of the run down into phases from the version
+// changes of the cluster operators, the machine config pool rollouts and the node update
+// intervals recorded while it ran.
+func ComputeUpgradePhases(events monitorapi.Intervals) []*UpgradePhases {
+	var all []*UpgradePhases
+	for _, hop := range ComputeUpgradeHops(events, nil) {
+		all = append(all, computeUpgradePhases(hop, intervalsBetween(events, hop.Started, hop.Ended)))
+	}
+	return all
+}
+
+func computeUpgradePhases(hop *UpgradeHop, events monitorapi.Intervals) *UpgradePhases {
+	phases := &UpgradePhases{
+		Hop:                hop.Hop,
+		Version:            hop.Version,
+		Image:              hop.Image,
+		Started:            hop.Started,
+		Ended:              hop.Ended,
+		Operators:          []OperatorUpgradePhase{},
+		MachineConfigPools: []UpgradePhase{},
+		Nodes:              []NodeUpgradePhase{},
+	}
+	// operators start updating once the cluster version operator accepted the upgrade
+	updateStarted := hop.Started
+	var activities []UpgradePhase
+	if hop.TimeToAcknowledged != nil {
+		updateStarted = hop.Started.Add(hop.TimeToAcknowledged.Duration)
+		activities = append(activities, newUpgradePhase(UpgradePhaseAcknowledge, "clusterversion/cluster", hop.Started, updateStarted))
+	}
+
+	versionChanged := map[string]time.Time{}
+	progressing := map[string]monitorapi.Intervals{}
+	nodes := map[string]*NodeUpgradePhase{}
+	nodePhases := map[string][]UpgradePhase{}
+	for _, event := range events {
+		if operator, ok := monitorapi.OperatorFromLocator(event.Locator); ok {
+			switch {
+			case strings.HasPrefix(event.Message, "versions: "):
+				versionChanged[operator] = event.From
+			case monitorapi.HasDuration(event) && strings.HasPrefix(event.Message, "condition/Progressing status/True"):
+				progressing[operator] = append(progressing[operator], event)
+			}
+			continue
+		}
+		if !monitorapi.HasDuration(event) {
+			continue
+		}
+		message := event.TypedMessage()
+		switch message.Reason {
+		case monitorapi.MachineConfigPoolUpdateReason:
+			phases.MachineConfigPools = append(phases.MachineConfigPools, newUpgradePhase(UpgradePhaseMachineConfigPoolUpdate, event.Locator, event.From, event.To))
+		case "NodeUpdate":
+			name, ok := monitorapi.NodeFromLocator(event.Locator)
+			if !ok {
+				continue
+			}
+			node, ok := nodes[name]
+			if !ok {
+				node = &NodeUpgradePhase{Name: name, From: event.From, To: event.To}
+				nodes[name] = node
+			}
+			if roles := message.Annotations[monitorapi.AnnotationRoles]; len(roles) > 0 {
+				node.Roles = roles
+			}
+			if event.From.Before(node.From) {
+				node.From = event.From
+			}
+			if event.To.After(node.To) {
+				node.To = event.To
+			}
+			duration := event.To.Sub(event.From)
+			phase := message.Annotations[monitorapi.AnnotationPhase]
+			switch phase {
+			case "Update":
+				node.Update.Duration += duration
+			case "Drain":
+				node.Drain.Duration += duration
+			case "OperatingSystemUpdate":
+				node.OperatingSystemUpdate.Duration += duration
+			case "Reboot":
+				node.Reboot.Duration += duration
+			default:
+				continue
+			}
+			nodePhases[name] = append(nodePhases[name], newUpgradePhase("Node"+phase, monitorapi.NodeLocator(name), event.From, event.To))
+		}
+	}
+	activities = append(activities, phases.MachineConfigPools...)
+
+	for operator, changed := range versionChanged {
+		from := updateStarted
+		var progressingDuration time.Duration
+		for _, interval := range progressing[operator] {
+			progressingDuration += interval.To.Sub(interval.From)
+			if !interval.From.After(changed) && interval.From.After(from) {
+				from = interval.From
+			}
+		}
+		if from.After(changed) {
+			from = changed
+		}
+		phases.Operators = append(phases.Operators, OperatorUpgradePhase{
+			Name:           operator,
+			VersionChanged: changed,
+			TimeToVersion:  metav1.Duration{Duration: changed.Sub(hop.Started)},
+			Progressing:    metav1.Duration{Duration: progressingDuration},
+		})
+		activities = append(activities, newUpgradePhase(UpgradePhaseOperatorUpdate, monitorapi.OperatorLocator(operator), from, changed))
+	}
+	sort.Slice(phases.Operators, func(i, j int) bool {
+		if !phases.Operators[i].VersionChanged.Equal(phases.Operators[j].VersionChanged) {
+			return phases.Operators[i].VersionChanged.Before(phases.Operators[j].VersionChanged)
+		}
+		return phases.Operators[i].Name < phases.Operators[j].Name
+	})
+
+	for name, node := range nodes {
+		phases.Nodes = append(phases.Nodes, *node)
+		// the individual phases are more precise than the whole update when they are known
+		var detailed []UpgradePhase
+		for _, phase := range nodePhases[name] {
+			if phase.Phase != UpgradePhaseNodeUpdate {
+				detailed = append(detailed, phase)
+			}
+		}
+		if len(detailed) == 0 {
+			detailed = nodePhases[name]
+		}
+		activities = append(activities, detailed...)
+	}
+	sort.Slice(phases.Nodes, func(i, j int) bool {
+		if !phases.Nodes[i].From.Equal(phases.Nodes[j].From) {
+			return phases.Nodes[i].From.Before(phases.Nodes[j].From)
+		}
+		return phases.Nodes[i].Name < phases.Nodes[j].Name
+	})
+
+	phases.CriticalPath = criticalPath(activities, hop.Started, hop.Ended)
+	return phases
+}
+
+func newUpgradePhase(phase, locator string, from, to time.Time) UpgradePhase {
+	return UpgradePhase{
+		Phase:    phase,
+		Locator:  locator,
+		From:     from,
+		To:       to,
+		Duration: metav1.Duration{Duration: to.Sub(from)},
+	}
+}
+
+// criticalPath walks back from end, each time picking the phase that finished last before
+// the current point and continuing from where that phase began, until no phase remains.
+func criticalPath(activities []UpgradePhase, start, end time.Time) []UpgradePhase {
+	path := []UpgradePhase{}
+	used := make([]bool, len(activities))
+	cursor := end
+	for {
+		best := -1
+		for i, activity := range activities {
+			if used[i] || activity.To.After(cursor) || !activity.From.Before(cursor) || activity.To.Before(start) {
+				continue
+			}
+			if best == -1 || activity.To.After(activities[best].To) || (activity.To.Equal(activities[best].To) && activity.From.Before(activities[best].From)) {
+				best = i
+			}
+		}
+		if best == -1 {
+			break
+		}
+		used[best] = true
+		path = append(path, activities[best])
+		cursor = activities[best].From
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+func writeUpgradePhases(filename string, phases []*UpgradePhases) error {
+	jsonContent, err := json.MarshalIndent(phases, "", "    ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, jsonContent, 0644)
+}
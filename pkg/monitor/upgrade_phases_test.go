@@ -0,0 +1,91 @@
+package monitor
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/intervalcreation"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func TestComputeUpgradePhases(t *testing.T) {
+	base := time.Date(2021, 8, 1, 10, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return base.Add(time.Duration(minutes) * time.Minute) }
+	event := func(locator, message string, from, to time.Time) monitorapi.EventInterval {
+		return monitorapi.EventInterval{
+			Condition: monitorapi.Condition{Level: monitorapi.Info, Locator: locator, Message: message},
+			From:      from,
+			To:        to,
+		}
+	}
+	cv := "ns/openshift-cluster-version clusterversion/cluster"
+	events := monitorapi.Intervals{
+		event(cv, "reason/UpgradeStarted version/4.8.2 image/registry/release:4.8.2", at(0), at(0)),
+		event(cv, "reason/UpgradeAcknowledged version/4.8.2 image/registry/release:4.8.2", at(1), at(1)),
+		event("clusteroperator/etcd", "condition/Progressing status/True reason/Upgrading", at(2), at(10)),
+		event("clusteroperator/etcd", "versions: operator 4.8.1 -> 4.8.2", at(10), at(10)),
+		event("clusteroperator/kube-apiserver", "condition/Progressing status/True reason/Upgrading", at(11), at(30)),
+		event("clusteroperator/kube-apiserver", "versions: operator 4.8.1 -> 4.8.2", at(30), at(30)),
+		event("clusteroperator/dns", "versions: operator 4.8.1 -> 4.8.2", at(20), at(20)),
+		event("machineconfigpool/master", "reason/MachineConfigPoolUpdating config/rendered-master-2 pool started updating", at(31), at(31)),
+		event("node/master-0", "reason/NodeUpdate phase/Drain roles/master drained node", at(32), at(35)),
+		event("node/master-0", "reason/NodeUpdate phase/OperatingSystemUpdate roles/master updated operating system", at(35), at(37)),
+		event("node/master-0", "reason/NodeUpdate phase/Reboot roles/master rebooted and kubelet started", at(37), at(45)),
+		event("node/master-0", "reason/NodeUpdate phase/Update config/rendered-master-2 roles/master reached desired config", at(32), at(46)),
+		event("node/worker-0", "reason/NodeUpdate phase/Update config/rendered-worker-2 roles/worker reached desired config", at(40), at(44)),
+		event("machineconfigpool/master", "reason/MachineConfigPoolUpdated config/rendered-master-2 pool finished updating", at(47), at(47)),
+		event(cv, "reason/UpgradeComplete version/4.8.2 image/registry/release:4.8.2", at(50), at(50)),
+	}
+	events = append(events, intervalcreation.IntervalsFromEvents_MachineConfigPoolUpdates(events, at(0), at(60))...)
+	sort.Sort(events)
+
+	all := ComputeUpgradePhases(events)
+	if len(all) != 1 {
+		t.Fatalf("expected phases for one upgrade, got %d", len(all))
+	}
+	phases := all[0]
+
+	var operators []string
+	for _, operator := range phases.Operators {
+		operators = append(operators, operator.Name)
+	}
+	if len(operators) != 3 || operators[0] != "etcd" || operators[1] != "dns" || operators[2] != "kube-apiserver" {
+		t.Errorf("expected operators in the order they reached the version, got %v", operators)
+	}
+	if etcd := phases.Operators[0]; etcd.TimeToVersion.Duration != 10*time.Minute || etcd.Progressing.Duration != 8*time.Minute {
+		t.Errorf("unexpected etcd phase: %#v", etcd)
+	}
+
+	if len(phases.MachineConfigPools) != 1 || phases.MachineConfigPools[0].Locator != "machineconfigpool/master" || phases.MachineConfigPools[0].Duration.Duration != 16*time.Minute {
+		t.Errorf("unexpected pools: %#v", phases.MachineConfigPools)
+	}
+
+	if len(phases.Nodes) != 2 {
+		t.Fatalf("unexpected nodes: %#v", phases.Nodes)
+	}
+	master := phases.Nodes[0]
+	if master.Name != "master-0" || master.Roles != "master" || master.Update.Duration != 14*time.Minute || master.Drain.Duration != 3*time.Minute || master.OperatingSystemUpdate.Duration != 2*time.Minute || master.Reboot.Duration != 8*time.Minute {
+		t.Errorf("unexpected master phase: %#v", master)
+	}
+
+	var path []string
+	for _, phase := range phases.CriticalPath {
+		path = append(path, phase.Phase+" "+phase.Locator)
+	}
+	expected := []string{
+		"Acknowledge clusterversion/cluster",
+		"OperatorUpdate clusteroperator/etcd",
+		"OperatorUpdate clusteroperator/kube-apiserver",
+		"MachineConfigPoolUpdate machineconfigpool/master",
+	}
+	if len(path) != len(expected) {
+		t.Fatalf("unexpected critical path: %v", path)
+	}
+	for i := range expected {
+		if path[i] != expected[i] {
+			t.Errorf("unexpected critical path: %v", path)
+			break
+		}
+	}
+}
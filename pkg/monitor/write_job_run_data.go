@@ -17,7 +17,8 @@ import (
 )
 
 // WriteRunDataToArtifactsDir attempts to write useful run data to the specified directory.
-func WriteRunDataToArtifactsDir(artifactDir string, monitor *Monitor, events monitorapi.Intervals, timeSuffix string) error {
+// Backends are the backends returned by StartDisruptionBackends.
+func WriteRunDataToArtifactsDir(artifactDir string, monitor *Monitor, events monitorapi.Intervals, backends map[string]string, timeSuffix string) error {
 	errors := []error{}
 	if err := monitorserialization.EventsToFile(filepath.Join(artifactDir, fmt.Sprintf("e2e-events%s.json", timeSuffix)), events); err != nil {
 		errors = append(errors, err)
@@ -46,11 +47,27 @@ func WriteRunDataToArtifactsDir(artifactDir string, monitor *Monitor, events mon
 		}
 	}
 
-	backendDisruption := computeDisruptionData(events)
+	backendDisruption := computeDisruptionData(events, backends)
 	if err := writeDisruptionData(filepath.Join(artifactDir, fmt.Sprintf("backend-disruption%s.json", timeSuffix)), backendDisruption); err != nil {
 		errors = append(errors, err)
 	}
 
+	if upgradeHops := ComputeUpgradeHops(events, backends); len(upgradeHops) > 0 {
+		if err := writeUpgradeHops(filepath.Join(artifactDir, fmt.Sprintf("upgrade-hops%s.json", timeSuffix)), upgradeHops); err != nil {
+			errors = append(errors, err)
+		}
+	}
+	if upgradePhases := ComputeUpgradePhases(events); len(upgradePhases) > 0 {
+		if err := writeUpgradePhases(filepath.Join(artifactDir, fmt.Sprintf("upgrade-phases%s.json", timeSuffix)), upgradePhases); err != nil {
+			errors = append(errors, err)
+		}
+	}
+
+	podLifecycleLatency := ComputePodLifecycleLatency(events)
+	if err := writePodLifecycleLatency(filepath.Join(artifactDir, fmt.Sprintf("pod-lifecycle-latency%s.json", timeSuffix)), podLifecycleLatency); err != nil {
+		errors = append(errors, err)
+	}
+
 	alertData := computeAlertData(events)
 	if err := writeAlertData(filepath.Join(artifactDir, fmt.Sprintf("alerts%s.json", timeSuffix)), alertData); err != nil {
 		errors = append(errors, err)
@@ -81,12 +98,14 @@ func writeDisruptionData(filename string, disruption *BackendDisruptionList) err
 	return ioutil.WriteFile(filename, jsonContent, 0644)
 }
 
-func computeDisruptionData(events monitorapi.Intervals) *BackendDisruptionList {
+// computeDisruptionData reports the disruption of the built-in backends and the configured
+// backends, keyed by locator.
+func computeDisruptionData(events monitorapi.Intervals, backends map[string]string) *BackendDisruptionList {
 	ret := &BackendDisruptionList{
 		BackendDisruptions: map[string]*BackendDisruption{},
 	}
 
-	for locator, name := range BackendDisruptionLocatorsToName {
+	for locator, name := range DisruptionBackendLocatorsToName(backends) {
 		disruptionDuration, disruptionMessages, connectionType := monitorapi.BackendDisruptionSeconds(locator, events)
 		ret.BackendDisruptions[name] = &BackendDisruption{
 			Name:               name,
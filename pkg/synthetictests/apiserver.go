@@ -2,40 +2,74 @@ package synthetictests
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/openshift/origin/pkg/monitor"
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
 
 	"github.com/openshift/origin/pkg/test/ginkgo"
 )
 
 const (
-	// Max. duration of API server unreachability, in fraction of total test duration.
+	// Max. duration of a backend's unreachability, in fraction of total test duration, unless
+	// the disruption budgets of the run select the backend.
 	tolerateDisruptionPercent = 0.01
 )
 
-func testServerAvailability(locator string, events monitorapi.Intervals, duration time.Duration) []*ginkgo.JUnitTestCase {
+func (c InvariantContext) testServerAvailability(suite, locator string, events monitorapi.Intervals, duration time.Duration) []*ginkgo.JUnitTestCase {
+	name := monitor.BackendDisruptionLocatorsToName[locator]
+	return c.testBackendAvailability(fmt.Sprintf("[sig-api-machinery] %s should be available", locator), suite, locator, name, events, duration)
+}
+
+// testConfiguredBackendAvailability checks the availability of the backends passed to the
+// monitor with --disruption-config, keyed by locator.
+func (c InvariantContext) testConfiguredBackendAvailability(suite string, events monitorapi.Intervals, duration time.Duration) []*ginkgo.JUnitTestCase {
+	var locators []string
+	for locator := range c.DisruptionBackends {
+		locators = append(locators, locator)
+	}
+	sort.Strings(locators)
+
+	var tests []*ginkgo.JUnitTestCase
+	for _, locator := range locators {
+		testName := fmt.Sprintf("[sig-arch] disruption backend %s should be available", locator)
+		tests = append(tests, c.testBackendAvailability(testName, suite, locator, c.DisruptionBackends[locator], events, duration)...)
+	}
+	return tests
+}
+
+// testBackendAvailability fails if the disruption of the backend exceeds its budget in the suite.
+// Exceeding a default budget is reported as a flake.
+func (c InvariantContext) testBackendAvailability(testName, suite, locator, name string, events monitorapi.Intervals, duration time.Duration) []*ginkgo.JUnitTestCase {
 	errDuration, errMessages, _ := monitorapi.BackendDisruptionSeconds(locator, events)
 
-	testName := fmt.Sprintf("[sig-api-machinery] %s should be available", locator)
 	successTest := &ginkgo.JUnitTestCase{
 		Name:     testName,
 		Duration: duration.Seconds(),
 	}
-	if percent := float64(errDuration) / float64(duration); percent > tolerateDisruptionPercent {
+	var percent float64
+	if duration > 0 {
+		percent = 100 * float64(errDuration) / float64(duration)
+	}
+	observed := fmt.Sprintf("%s was failing for %s seconds (%0.0f%% of the test duration)", locator, errDuration.Truncate(time.Second), percent)
+	budget, configured := c.disruptionBudgetFor(locator, name, suite)
+	if budget.exceeded(errDuration, duration) {
 		test := &ginkgo.JUnitTestCase{
 			Name:     testName,
 			Duration: duration.Seconds(),
 			FailureOutput: &ginkgo.FailureOutput{
-				Output: fmt.Sprintf("%s was failing for %s seconds (%0.0f%% of the test duration)", locator, errDuration.Truncate(time.Second), 100*percent),
+				Output: fmt.Sprintf("%s, exceeding the disruption budget of %s", observed, budget),
 			},
 			SystemOut: strings.Join(errMessages, "\n"),
 		}
+		if configured {
+			return []*ginkgo.JUnitTestCase{test}
+		}
 		// Return *two* tests results to pretend this is a flake not to fail whole testsuite.
 		return []*ginkgo.JUnitTestCase{test, successTest}
-	} else {
-		successTest.SystemOut = fmt.Sprintf("%s was failing for %s seconds (%0.0f%% of the test duration)", locator, errDuration.Truncate(time.Second), 100*percent)
-		return []*ginkgo.JUnitTestCase{successTest}
 	}
+	successTest.SystemOut = fmt.Sprintf("%s, within the disruption budget of %s", observed, budget)
+	return []*ginkgo.JUnitTestCase{successTest}
 }
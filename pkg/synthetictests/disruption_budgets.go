@@ -0,0 +1,192 @@
+package synthetictests
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift/origin/pkg/monitor"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+const (
+	// stableSuite and upgradeSuite select the budgets of the stable and upgrade invariants.
+	stableSuite  = "stable"
+	upgradeSuite = "upgrade"
+)
+
+// DisruptionBudgetList is the disruption tolerated for each backend before its
+// availability test fails, for example:
+//
+//	budgets:
+//	- maxPercent: 1
+//	- backend: kube-api-new-connections
+//	  suite: stable
+//	  platform: aws
+//	  maxDuration: 5s
+//	- backend: my-app-reused-connections
+//	  topology: SingleReplica
+//	  maxDuration: 30s
+//	  maxPercent: 2
+//
+// The budget that selects a backend with the most fields applies, and of those the last
+// one listed. Backends that no budget selects are allowed 1% of the run.
+type DisruptionBudgetList struct {
+	Budgets []DisruptionBudget `json:"budgets"`
+}
+
+// DisruptionBudget selects backends with its non-empty fields and limits their disruption
+// to MaxDuration and MaxPercent, if set.
+type DisruptionBudget struct {
+	// Backend is the name of the backend in backend-disruption.json or its locator.
+	Backend string `json:"backend,omitempty"`
+	// ConnectionType is new or reused.
+	ConnectionType monitor.BackendConnectionType `json:"connectionType,omitempty"`
+	// Suite is stable or upgrade.
+	Suite string `json:"suite,omitempty"`
+	// Platform is the cluster provider, e.g. aws, gce, azure or vsphere.
+	Platform string `json:"platform,omitempty"`
+	// Topology is the control plane topology, HighlyAvailable or SingleReplica.
+	Topology configv1.TopologyMode `json:"topology,omitempty"`
+
+	// MaxDuration is the longest the backend may be disrupted.
+	MaxDuration *metav1.Duration `json:"maxDuration,omitempty"`
+	// MaxPercent is the percentage of the run the backend may be disrupted.
+	MaxPercent *float64 `json:"maxPercent,omitempty"`
+}
+
+func percentBudget(percent float64) *float64 {
+	return &percent
+}
+
+// defaultDisruptionBudgets tolerate disruption of any backend for 1% of the run.
+var defaultDisruptionBudgets = &DisruptionBudgetList{
+	Budgets: []DisruptionBudget{
+		{MaxPercent: percentBudget(100 * tolerateDisruptionPercent)},
+	},
+}
+
+// LoadDisruptionBudgets reads a YAML list of disruption budgets.
+func LoadDisruptionBudgets(path string) (*DisruptionBudgetList, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	budgets := &DisruptionBudgetList{}
+	if err := yaml.UnmarshalStrict(data, budgets); err != nil {
+		return nil, fmt.Errorf("unable to parse disruption budgets from %s: %v", path, err)
+	}
+	for i, budget := range budgets.Budgets {
+		if budget.MaxDuration == nil && budget.MaxPercent == nil {
+			return nil, fmt.Errorf("disruption budget %d in %s must set maxDuration or maxPercent", i, path)
+		}
+		switch budget.Suite {
+		case "", stableSuite, upgradeSuite:
+		default:
+			return nil, fmt.Errorf("disruption budget %d in %s has unrecognized suite %q, must be %s or %s", i, path, budget.Suite, stableSuite, upgradeSuite)
+		}
+	}
+	return budgets, nil
+}
+
+// disruptionBudgetFor returns the budget of the backend with the provided locator and name in
+// a suite and whether it was configured for the run. The budgets of the run apply before the
+// default budgets, so backends they do not select keep the default budget.
+func (c InvariantContext) disruptionBudgetFor(locator, name, suite string) (*DisruptionBudget, bool) {
+	if budget := c.DisruptionBudgets.budgetFor(locator, name, suite, c.Platform, c.Topology); budget != nil {
+		return budget, true
+	}
+	return defaultDisruptionBudgets.budgetFor(locator, name, suite, c.Platform, c.Topology), false
+}
+
+// budgetFor returns the budget of the backend with the provided locator and name in a suite,
+// or nil if its disruption is unbounded.
+func (l *DisruptionBudgetList) budgetFor(locator, name, suite, platform string, topology configv1.TopologyMode) *DisruptionBudget {
+	if l == nil {
+		return nil
+	}
+	connectionType := backendConnectionType(locator)
+	var selected *DisruptionBudget
+	selectedFields := -1
+	for i := range l.Budgets {
+		budget := &l.Budgets[i]
+		fields := 0
+		for _, match := range []struct {
+			want  string
+			value []string
+		}{
+			{want: budget.Backend, value: []string{locator, name}},
+			{want: string(budget.ConnectionType), value: []string{string(connectionType)}},
+			{want: budget.Suite, value: []string{suite}},
+			{want: budget.Platform, value: []string{platform}},
+			{want: string(budget.Topology), value: []string{string(topology)}},
+		} {
+			if len(match.want) == 0 {
+				continue
+			}
+			if !containsString(match.value, match.want) {
+				fields = -1
+				break
+			}
+			fields++
+		}
+		if fields < 0 {
+			continue
+		}
+		if fields >= selectedFields {
+			selected, selectedFields = budget, fields
+		}
+	}
+	return selected
+}
+
+// exceeded returns true if the disruption is over any limit of the budget.
+func (b *DisruptionBudget) exceeded(disruption, duration time.Duration) bool {
+	if b.MaxDuration != nil && disruption > b.MaxDuration.Duration {
+		return true
+	}
+	if b.MaxPercent != nil && duration > 0 && 100*float64(disruption)/float64(duration) > *b.MaxPercent {
+		return true
+	}
+	return false
+}
+
+func (b *DisruptionBudget) String() string {
+	var limits []string
+	if b.MaxDuration != nil {
+		limits = append(limits, b.MaxDuration.Duration.String())
+	}
+	if b.MaxPercent != nil {
+		limits = append(limits, fmt.Sprintf("%g%% of the test duration", *b.MaxPercent))
+	}
+	return strings.Join(limits, " and ")
+}
+
+// backendConnectionType returns whether the backend with the provided locator is polled
+// over new or reused connections.
+func backendConnectionType(locator string) monitor.BackendConnectionType {
+	if connectionType, ok := monitorapi.LocatorParts(locator)["connection"]; ok {
+		return monitor.BackendConnectionType(connectionType)
+	}
+	switch {
+	case strings.HasSuffix(locator, "-new-connection"):
+		return monitor.NewConnectionType
+	case strings.HasSuffix(locator, "-reused-connection"):
+		return monitor.ReusedConnectionType
+	}
+	return ""
+}
+
+func containsString(values []string, s string) bool {
+	for _, value := range values {
+		if value == s {
+			return true
+		}
+	}
+	return false
+}
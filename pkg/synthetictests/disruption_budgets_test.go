@@ -0,0 +1,141 @@
+package synthetictests
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/origin/pkg/monitor"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func TestDisruptionBudgetFor(t *testing.T) {
+	budgets := &DisruptionBudgetList{
+		Budgets: []DisruptionBudget{
+			{MaxPercent: percentBudget(1)},
+			{Backend: "kube-api-new-connections", MaxDuration: &metav1.Duration{Duration: time.Second}},
+			{Backend: "kube-api-new-connections", Platform: "aws", MaxDuration: &metav1.Duration{Duration: 2 * time.Second}},
+			{ConnectionType: monitor.ReusedConnectionType, Suite: upgradeSuite, MaxDuration: &metav1.Duration{Duration: 3 * time.Second}},
+			{Topology: configv1.SingleReplicaTopologyMode, MaxDuration: &metav1.Duration{Duration: 4 * time.Second}},
+			{Backend: "my-app-new-connections", MaxDuration: &metav1.Duration{Duration: 5 * time.Second}},
+		},
+	}
+	tests := []struct {
+		name     string
+		locator  string
+		backend  string
+		suite    string
+		platform string
+		topology configv1.TopologyMode
+		want     string
+	}{
+		{name: "default", locator: monitor.LocatorOAuthAPIServerNewConnection, suite: stableSuite, want: "1% of the test duration"},
+		{name: "backend name", locator: monitor.LocatorKubeAPIServerNewConnection, suite: stableSuite, want: "1s"},
+		{name: "backend and platform", locator: monitor.LocatorKubeAPIServerNewConnection, suite: stableSuite, platform: "aws", want: "2s"},
+		{name: "connection type and suite", locator: monitor.LocatorOAuthAPIServerReusedConnection, suite: upgradeSuite, want: "3s"},
+		{name: "route connection type", locator: monitor.LocateRouteForDisruptionCheck("ns", "app", monitor.ReusedConnectionType), suite: upgradeSuite, want: "3s"},
+		{name: "topology", locator: monitor.LocatorOAuthAPIServerNewConnection, suite: stableSuite, topology: configv1.SingleReplicaTopologyMode, want: "4s"},
+		{name: "configured backend name", locator: monitor.LocateDisruptionCheck("my-app", monitor.NewConnectionType), backend: "my-app-new-connections", suite: stableSuite, want: "5s"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend := tt.backend
+			if len(backend) == 0 {
+				backend = monitor.BackendDisruptionLocatorsToName[tt.locator]
+			}
+			budget := budgets.budgetFor(tt.locator, backend, tt.suite, tt.platform, tt.topology)
+			if budget == nil || budget.String() != tt.want {
+				t.Fatalf("expected budget %s, got %v", tt.want, budget)
+			}
+		})
+	}
+
+	if budget := (&DisruptionBudgetList{}).budgetFor(monitor.LocatorKubeAPIServerNewConnection, "kube-api-new-connections", stableSuite, "", ""); budget != nil {
+		t.Errorf("expected no budget, got %v", budget)
+	}
+
+	awsOnly := &DisruptionBudgetList{
+		Budgets: []DisruptionBudget{
+			{Platform: "aws", MaxDuration: &metav1.Duration{Duration: time.Second}},
+		},
+	}
+	if budget := awsOnly.budgetFor(monitor.LocatorKubeAPIServerNewConnection, "kube-api-new-connections", stableSuite, "gcp", ""); budget != nil {
+		t.Errorf("expected no budget for a platform no budget selects, got %v", budget)
+	}
+}
+
+func TestServerAvailabilityBudget(t *testing.T) {
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	locator := monitor.LocatorKubeAPIServerNewConnection
+	events := monitorapi.Intervals{
+		{
+			Condition: monitorapi.Condition{
+				Level:   monitorapi.Error,
+				Locator: locator,
+				Message: monitor.DisruptionContinuingMessage(locator, monitor.NewConnectionType, nil),
+			},
+			From: start,
+			To:   start.Add(10 * time.Second),
+		},
+	}
+	awsBudgets := &DisruptionBudgetList{
+		Budgets: []DisruptionBudget{
+			{Platform: "aws", MaxDuration: &metav1.Duration{Duration: 15 * time.Second}},
+			{Platform: "aws", Topology: configv1.SingleReplicaTopologyMode, MaxDuration: &metav1.Duration{Duration: 5 * time.Second}},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		ctx     InvariantContext
+		results int
+		failed  bool
+		output  string
+	}{
+		{
+			// 10s of a 100s run is over the default budget of 1%
+			name:    "default budget",
+			results: 2,
+			failed:  true,
+			output:  "exceeding the disruption budget of 1% of the test duration",
+		},
+		{
+			name:    "within configured budget",
+			ctx:     InvariantContext{DisruptionBudgets: awsBudgets, Platform: "aws", Topology: configv1.HighlyAvailableTopologyMode},
+			results: 1,
+			output:  "within the disruption budget of 15s",
+		},
+		{
+			name:    "over configured budget",
+			ctx:     InvariantContext{DisruptionBudgets: awsBudgets, Platform: "aws", Topology: configv1.SingleReplicaTopologyMode},
+			results: 1,
+			failed:  true,
+			output:  "exceeding the disruption budget of 5s",
+		},
+		{
+			name:    "not selected by configured budgets",
+			ctx:     InvariantContext{DisruptionBudgets: awsBudgets, Platform: "gcp", Topology: configv1.HighlyAvailableTopologyMode},
+			results: 2,
+			failed:  true,
+			output:  "exceeding the disruption budget of 1% of the test duration",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := tt.ctx.testServerAvailability(stableSuite, locator, events, 100*time.Second)
+			if len(results) != tt.results || (results[0].FailureOutput != nil) != tt.failed {
+				t.Fatalf("expected %d results failing %t, got %#v", tt.results, tt.failed, results)
+			}
+			output := results[0].SystemOut
+			if results[0].FailureOutput != nil {
+				output = results[0].FailureOutput.Output
+			}
+			if !strings.Contains(output, tt.output) {
+				t.Errorf("expected %q in output: %s", tt.output, output)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package synthetictests
 import (
 	"time"
 
+	configv1 "github.com/openshift/api/config/v1"
 	"k8s.io/client-go/rest"
 
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
@@ -11,12 +12,63 @@ import (
 	"github.com/openshift/origin/pkg/test/ginkgo"
 )
 
-// StableSystemEventInvariants are invariants that should hold true when a cluster is in
-// steady state (not being changed externally). Use these with suites that assume the
-// cluster is under no adversarial change (config changes, induced disruption to nodes,
-// etcd, or apis).
-func StableSystemEventInvariants(events monitorapi.Intervals, duration time.Duration, kubeClientConfig *rest.Config) (tests []*ginkgo.JUnitTestCase) {
-	tests = SystemEventInvariants(events, duration, kubeClientConfig)
+// InvariantContext is the configuration of a run that the invariants are evaluated with. The
+// zero value evaluates them for a run without additional configuration.
+type InvariantContext struct {
+	// DisruptionBackends maps the locators of the additional backends the monitor polled to
+	// their names, as returned by monitor.StartDisruptionBackends.
+	DisruptionBackends map[string]string
+
+	// DisruptionBudgets are the budgets of the run, which apply before the default budgets.
+	DisruptionBudgets *DisruptionBudgetList
+	// Platform and Topology of the cluster select the disruption budgets.
+	Platform string
+	Topology configv1.TopologyMode
+
+	// PodLatencyThresholds are the pod lifecycle latency thresholds of the run, which apply
+	// before the default thresholds.
+	PodLatencyThresholds *PodLatencyThresholdList
+}
+
+// EventInvariants is a set of invariants that is evaluated against the events of a run with
+// the configuration of the run.
+type EventInvariants struct {
+	// Context is the configuration of the run.
+	Context InvariantContext
+
+	invariants func(InvariantContext, monitorapi.Intervals, time.Duration, *rest.Config) []*ginkgo.JUnitTestCase
+}
+
+func (i EventInvariants) JUnitsForEvents(events monitorapi.Intervals, duration time.Duration, kubeClientConfig *rest.Config) []*ginkgo.JUnitTestCase {
+	return i.invariants(i.Context, events, duration, kubeClientConfig)
+}
+
+// WithContext returns the invariants evaluated with the configuration of a run.
+func (i EventInvariants) WithContext(ctx InvariantContext) EventInvariants {
+	i.Context = ctx
+	return i
+}
+
+var (
+	// StableSystemEventInvariants are invariants that should hold true when a cluster is in
+	// steady state (not being changed externally). Use these with suites that assume the
+	// cluster is under no adversarial change (config changes, induced disruption to nodes,
+	// etcd, or apis).
+	StableSystemEventInvariants = EventInvariants{invariants: InvariantContext.stableSystemEventInvariants}
+
+	// SystemUpgradeEventInvariants are invariants tested against events that should hold true
+	// in a cluster that is being upgraded without induced disruption. The pod and node
+	// invariants ignore the objects a test disrupted on purpose while they were disrupted.
+	SystemUpgradeEventInvariants = EventInvariants{invariants: InvariantContext.systemUpgradeEventInvariants}
+
+	// SystemEventInvariants are invariants tested against events that should hold true in any
+	// cluster, even one undergoing disruption. These are usually focused on things that must
+	// be true on a single machine, even if the machine crashes.
+	SystemEventInvariants = EventInvariants{invariants: InvariantContext.systemEventInvariants}
+)
+
+func (c InvariantContext) stableSystemEventInvariants(events monitorapi.Intervals, duration time.Duration, kubeClientConfig *rest.Config) (tests []*ginkgo.JUnitTestCase) {
+	tests = c.systemEventInvariants(events, duration, kubeClientConfig)
 	tests = append(tests, testContainerFailures(events)...)
 	tests = append(tests, testDeleteGracePeriodZero(events)...)
 	tests = append(tests, testKubeApiserverProcessOverlap(events)...)
@@ -24,39 +76,40 @@ func StableSystemEventInvariants(events monitorapi.Intervals, duration time.Dura
 	tests = append(tests, testKubeletToAPIServerGracefulTermination(events)...)
 	tests = append(tests, testPodTransitions(events)...)
 	tests = append(tests, testPodSandboxCreation(events)...)
-	tests = append(tests, testServerAvailability(monitor.LocatorKubeAPIServerNewConnection, events, duration)...)
-	tests = append(tests, testServerAvailability(monitor.LocatorOpenshiftAPIServerNewConnection, events, duration)...)
-	tests = append(tests, testServerAvailability(monitor.LocatorOAuthAPIServerNewConnection, events, duration)...)
-	tests = append(tests, testServerAvailability(monitor.LocatorKubeAPIServerReusedConnection, events, duration)...)
-	tests = append(tests, testServerAvailability(monitor.LocatorOpenshiftAPIServerReusedConnection, events, duration)...)
-	tests = append(tests, testServerAvailability(monitor.LocatorOAuthAPIServerReusedConnection, events, duration)...)
+	tests = append(tests, c.testServerAvailability(stableSuite, monitor.LocatorKubeAPIServerNewConnection, events, duration)...)
+	tests = append(tests, c.testServerAvailability(stableSuite, monitor.LocatorOpenshiftAPIServerNewConnection, events, duration)...)
+	tests = append(tests, c.testServerAvailability(stableSuite, monitor.LocatorOAuthAPIServerNewConnection, events, duration)...)
+	tests = append(tests, c.testServerAvailability(stableSuite, monitor.LocatorKubeAPIServerReusedConnection, events, duration)...)
+	tests = append(tests, c.testServerAvailability(stableSuite, monitor.LocatorOpenshiftAPIServerReusedConnection, events, duration)...)
+	tests = append(tests, c.testServerAvailability(stableSuite, monitor.LocatorOAuthAPIServerReusedConnection, events, duration)...)
+	tests = append(tests, c.testConfiguredBackendAvailability(stableSuite, events, duration)...)
 	tests = append(tests, testStableSystemOperatorStateTransitions(events)...)
 	tests = append(tests, testDuplicatedEventForStableSystem(events, kubeClientConfig)...)
+	tests = append(tests, c.testPodLifecycleLatency(events)...)
 
 	return tests
 }
 
-// SystemUpgradeEventInvariants are invariants tested against events that should hold true in a cluster
-// that is being upgraded without induced disruption
-func SystemUpgradeEventInvariants(events monitorapi.Intervals, duration time.Duration, kubeClientConfig *rest.Config) (tests []*ginkgo.JUnitTestCase) {
-	tests = SystemEventInvariants(events, duration, kubeClientConfig)
-	tests = append(tests, testContainerFailures(events)...)
-	tests = append(tests, testDeleteGracePeriodZero(events)...)
+func (c InvariantContext) systemUpgradeEventInvariants(events monitorapi.Intervals, duration time.Duration, kubeClientConfig *rest.Config) (tests []*ginkgo.JUnitTestCase) {
+	undisrupted := monitor.WithoutInjectedDisruptionTargets(events)
+	tests = c.systemEventInvariants(events, duration, kubeClientConfig)
+	tests = append(tests, testContainerFailures(undisrupted)...)
+	tests = append(tests, testDeleteGracePeriodZero(undisrupted)...)
 	tests = append(tests, testKubeApiserverProcessOverlap(events)...)
 	tests = append(tests, testKubeAPIServerGracefulTermination(events)...)
 	tests = append(tests, testKubeletToAPIServerGracefulTermination(events)...)
-	tests = append(tests, testPodTransitions(events)...)
-	tests = append(tests, testPodSandboxCreation(events)...)
-	tests = append(tests, testNodeUpgradeTransitions(events)...)
+	tests = append(tests, testPodTransitions(undisrupted)...)
+	tests = append(tests, testPodSandboxCreation(undisrupted)...)
+	tests = append(tests, testNodeUpgradeTransitions(undisrupted)...)
+	tests = append(tests, c.testConfiguredBackendAvailability(upgradeSuite, events, duration)...)
 	tests = append(tests, testUpgradeOperatorStateTransitions(events)...)
 	tests = append(tests, testDuplicatedEventForUpgrade(events, kubeClientConfig)...)
+	tests = append(tests, testUpgradeHops(events, c.DisruptionBackends)...)
+	tests = append(tests, c.testPodLifecycleLatency(undisrupted)...)
 	return tests
 }
 
-// SystemEventInvariants are invariants tested against events that should hold true in any cluster,
-// even one undergoing disruption. These are usually focused on things that must be true on a single
-// machine, even if the machine crashes.
-func SystemEventInvariants(events monitorapi.Intervals, duration time.Duration, kubeClientConfig *rest.Config) (tests []*ginkgo.JUnitTestCase) {
+func (c InvariantContext) systemEventInvariants(events monitorapi.Intervals, duration time.Duration, kubeClientConfig *rest.Config) (tests []*ginkgo.JUnitTestCase) {
 	tests = append(tests, testSystemDTimeout(events)...)
 	return tests
 }
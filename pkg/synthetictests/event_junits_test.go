@@ -0,0 +1,38 @@
+package synthetictests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func TestEventInvariantsWithContext(t *testing.T) {
+	locator := monitor.LocateDisruptionCheck("my-app", monitor.NewConnectionType)
+	testName := "[sig-arch] disruption backend " + locator + " should be available"
+	ctx := InvariantContext{DisruptionBackends: map[string]string{locator: "my-app-new-connections"}}
+
+	for _, tt := range []struct {
+		name       string
+		invariants EventInvariants
+		want       bool
+	}{
+		{name: "default", invariants: StableSystemEventInvariants},
+		{name: "stable", invariants: StableSystemEventInvariants.WithContext(ctx), want: true},
+		{name: "upgrade", invariants: SystemUpgradeEventInvariants.WithContext(ctx), want: true},
+		{name: "system", invariants: SystemEventInvariants.WithContext(ctx)},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var found bool
+			for _, test := range tt.invariants.JUnitsForEvents(monitorapi.Intervals{}, time.Hour, nil) {
+				if test.Name == testName {
+					found = true
+				}
+			}
+			if found != tt.want {
+				t.Errorf("expected the configured backend to be evaluated %t, got %t", tt.want, found)
+			}
+		})
+	}
+}
@@ -77,20 +77,21 @@ func testContainerFailures(events monitorapi.Intervals) []*ginkgo.JUnitTestCase
 	containerExits := make(map[string][]string)
 	failures := []string{}
 	for _, event := range events {
-		if !strings.Contains(event.Locator, "ns/openshift-") {
+		if !strings.HasPrefix(event.TypedLocator().Keys[monitorapi.LocatorNamespaceKey], "openshift-") {
 			continue
 		}
+		message := event.TypedMessage()
 		switch {
 		// errors during container start should be highlighted because they are unexpected
-		case strings.Contains(event.Message, "reason/ContainerWait "):
+		case message.Reason == "ContainerWait":
 			// excluded https://bugzilla.redhat.com/show_bug.cgi?id=1933760
-			if strings.Contains(event.Message, "possible container status clear") || strings.Contains(event.Message, "cause/ContainerCreating ") {
+			if strings.Contains(message.HumanMessage, "possible container status clear") || message.Cause == "ContainerCreating" {
 				continue
 			}
 			failures = append(failures, fmt.Sprintf("%v - %v", event.Locator, event.Message))
 
 		// workload containers should never exit non-zero during normal operations
-		case strings.Contains(event.Message, "reason/ContainerExit") && !strings.Contains(event.Message, "code/0"):
+		case message.Reason == "ContainerExit" && message.Annotations[monitorapi.AnnotationCode] != "0":
 			containerExits[event.Locator] = append(containerExits[event.Locator], event.Message)
 		}
 	}
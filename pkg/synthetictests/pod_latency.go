@@ -0,0 +1,202 @@
+package synthetictests
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift/origin/pkg/monitor"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo"
+)
+
+// PodLatencyThresholdList is the pod lifecycle latency tolerated in platform namespaces
+// before the latency test flags them, for example:
+//
+//	thresholds:
+//	- latency: CreateToScheduled
+//	  p99: 30s
+//	- latency: ScheduledToRunning
+//	  namespace: openshift-etcd
+//	  p90: 1m
+//	  max: 5m
+//
+// The latencies are CreateToScheduled, ScheduledToRunning, RunningToReady and DeleteToGone.
+// The threshold that selects a latency and namespace with the most fields applies, and of
+// those the last one listed. Latencies and namespaces that no threshold selects keep the
+// default thresholds.
+type PodLatencyThresholdList struct {
+	Thresholds []PodLatencyThreshold `json:"thresholds"`
+}
+
+// PodLatencyThreshold selects latencies and namespaces with its non-empty fields and limits
+// the percentiles of their distribution, if set.
+type PodLatencyThreshold struct {
+	Latency   string `json:"latency,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+
+	P50 *metav1.Duration `json:"p50,omitempty"`
+	P90 *metav1.Duration `json:"p90,omitempty"`
+	P99 *metav1.Duration `json:"p99,omitempty"`
+	Max *metav1.Duration `json:"max,omitempty"`
+}
+
+func durationThreshold(d time.Duration) *metav1.Duration {
+	return &metav1.Duration{Duration: d}
+}
+
+// defaultPodLatencyThresholds flag latencies that are slow enough to delay an upgrade or the
+// recovery of a platform component.
+var defaultPodLatencyThresholds = &PodLatencyThresholdList{
+	Thresholds: []PodLatencyThreshold{
+		{Latency: monitorapi.PodCreateToScheduled, P99: durationThreshold(time.Minute)},
+		{Latency: monitorapi.PodScheduledToRunning, P99: durationThreshold(5 * time.Minute)},
+		{Latency: monitorapi.PodRunningToReady, P99: durationThreshold(5 * time.Minute)},
+		{Latency: monitorapi.PodDeleteToGone, P99: durationThreshold(5 * time.Minute)},
+	},
+}
+
+// LoadPodLatencyThresholds reads a YAML list of pod lifecycle latency thresholds.
+func LoadPodLatencyThresholds(path string) (*PodLatencyThresholdList, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	thresholds := &PodLatencyThresholdList{}
+	if err := yaml.UnmarshalStrict(data, thresholds); err != nil {
+		return nil, fmt.Errorf("unable to parse pod latency thresholds from %s: %v", path, err)
+	}
+	for i, threshold := range thresholds.Thresholds {
+		if threshold.P50 == nil && threshold.P90 == nil && threshold.P99 == nil && threshold.Max == nil {
+			return nil, fmt.Errorf("pod latency threshold %d in %s must set p50, p90, p99 or max", i, path)
+		}
+		if len(threshold.Latency) > 0 && !containsString(monitorapi.PodLifecycleLatencies, threshold.Latency) {
+			return nil, fmt.Errorf("pod latency threshold %d in %s has unrecognized latency %q, must be one of %s", i, path, threshold.Latency, strings.Join(monitorapi.PodLifecycleLatencies, ", "))
+		}
+	}
+	return thresholds, nil
+}
+
+// podLatencyThresholdFor returns the threshold of the latency in the namespace and whether it
+// was configured for the run. The thresholds of the run apply before the default thresholds.
+func (c InvariantContext) podLatencyThresholdFor(latency, namespace string) (*PodLatencyThreshold, bool) {
+	if threshold := c.PodLatencyThresholds.thresholdFor(latency, namespace); threshold != nil {
+		return threshold, true
+	}
+	return defaultPodLatencyThresholds.thresholdFor(latency, namespace), false
+}
+
+// thresholdFor returns the threshold of the latency in the namespace, or nil if it is
+// unbounded.
+func (l *PodLatencyThresholdList) thresholdFor(latency, namespace string) *PodLatencyThreshold {
+	if l == nil {
+		return nil
+	}
+	var selected *PodLatencyThreshold
+	selectedFields := -1
+	for i := range l.Thresholds {
+		threshold := &l.Thresholds[i]
+		fields := 0
+		for _, match := range []struct{ want, value string }{
+			{want: threshold.Latency, value: latency},
+			{want: threshold.Namespace, value: namespace},
+		} {
+			if len(match.want) == 0 {
+				continue
+			}
+			if match.want != match.value {
+				fields = -1
+				break
+			}
+			fields++
+		}
+		if fields < 0 {
+			continue
+		}
+		if fields >= selectedFields {
+			selected, selectedFields = threshold, fields
+		}
+	}
+	return selected
+}
+
+// exceeded describes each percentile of the distribution over the threshold.
+func (t *PodLatencyThreshold) exceeded(distribution *monitor.LatencyDistribution) []string {
+	var exceeded []string
+	for _, limit := range []struct {
+		name   string
+		actual time.Duration
+		max    *metav1.Duration
+	}{
+		{"p50", distribution.P50.Duration, t.P50},
+		{"p90", distribution.P90.Duration, t.P90},
+		{"p99", distribution.P99.Duration, t.P99},
+		{"max", distribution.Max.Duration, t.Max},
+	} {
+		if limit.max != nil && limit.actual > limit.max.Duration {
+			exceeded = append(exceeded, fmt.Sprintf("%s %s > %s", limit.name, limit.actual.Round(time.Second), limit.max.Duration))
+		}
+	}
+	return exceeded
+}
+
+func isPlatformNamespace(namespace string) bool {
+	return strings.HasPrefix(namespace, "openshift-") || strings.HasPrefix(namespace, "kube-") || namespace == "default"
+}
+
+// testPodLifecycleLatency flags the platform namespaces whose pods took longer than the
+// thresholds to be scheduled, start running, become ready or go away after deletion. The
+// test fails if a threshold configured for the run is exceeded and flakes if only the
+// default thresholds are, until they are tuned from the pod-lifecycle-latency artifacts.
+func (c InvariantContext) testPodLifecycleLatency(events monitorapi.Intervals) []*ginkgo.JUnitTestCase {
+	const testName = "[sig-node] pods in platform namespaces should progress through their lifecycle within the latency thresholds"
+
+	latencies := monitor.ComputePodLifecycleLatency(events)
+	var namespaces []string
+	for namespace := range latencies.Namespaces {
+		if isPlatformNamespace(namespace) {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	sort.Strings(namespaces)
+
+	var failures []string
+	failed := false
+	for _, namespace := range namespaces {
+		for _, latency := range monitorapi.PodLifecycleLatencies {
+			distribution, ok := latencies.Namespaces[namespace][latency]
+			if !ok {
+				continue
+			}
+			threshold, configured := c.podLatencyThresholdFor(latency, namespace)
+			if threshold == nil {
+				continue
+			}
+			if exceeded := threshold.exceeded(distribution); len(exceeded) > 0 {
+				failures = append(failures, fmt.Sprintf("ns/%s %s over %d pods: %s", namespace, latency, distribution.Count, strings.Join(exceeded, ", ")))
+				failed = failed || configured
+			}
+		}
+	}
+	if len(failures) == 0 {
+		return []*ginkgo.JUnitTestCase{{Name: testName}}
+	}
+	tests := []*ginkgo.JUnitTestCase{
+		{
+			Name:      testName,
+			SystemOut: strings.Join(failures, "\n"),
+			FailureOutput: &ginkgo.FailureOutput{
+				Output: fmt.Sprintf("%d pod lifecycle latencies in platform namespaces exceeded their thresholds:\n\n%s", len(failures), strings.Join(failures, "\n")),
+			},
+		},
+	}
+	if !failed {
+		tests = append(tests, &ginkgo.JUnitTestCase{Name: testName})
+	}
+	return tests
+}
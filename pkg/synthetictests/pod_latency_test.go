@@ -0,0 +1,134 @@
+package synthetictests
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func TestPodLatencyThresholdFor(t *testing.T) {
+	thresholds := &PodLatencyThresholdList{
+		Thresholds: []PodLatencyThreshold{
+			{P99: durationThreshold(time.Minute)},
+			{Latency: monitorapi.PodCreateToScheduled, P99: durationThreshold(time.Second)},
+			{Latency: monitorapi.PodCreateToScheduled, Namespace: "openshift-etcd", P99: durationThreshold(2 * time.Second)},
+			{Namespace: "openshift-etcd", P99: durationThreshold(3 * time.Second)},
+		},
+	}
+	tests := []struct {
+		name      string
+		latency   string
+		namespace string
+		want      time.Duration
+	}{
+		{name: "default", latency: monitorapi.PodRunningToReady, namespace: "openshift-dns", want: time.Minute},
+		{name: "latency", latency: monitorapi.PodCreateToScheduled, namespace: "openshift-dns", want: time.Second},
+		{name: "latency and namespace", latency: monitorapi.PodCreateToScheduled, namespace: "openshift-etcd", want: 2 * time.Second},
+		{name: "namespace", latency: monitorapi.PodRunningToReady, namespace: "openshift-etcd", want: 3 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			threshold := thresholds.thresholdFor(tt.latency, tt.namespace)
+			if threshold == nil || threshold.P99.Duration != tt.want {
+				t.Fatalf("expected p99 threshold %s, got %#v", tt.want, threshold)
+			}
+		})
+	}
+
+	if threshold := (&PodLatencyThresholdList{}).thresholdFor(monitorapi.PodRunningToReady, "openshift-dns"); threshold != nil {
+		t.Errorf("expected no threshold, got %#v", threshold)
+	}
+
+	etcdOnly := &PodLatencyThresholdList{
+		Thresholds: []PodLatencyThreshold{
+			{Namespace: "openshift-etcd", P99: durationThreshold(time.Second)},
+		},
+	}
+	if threshold := etcdOnly.thresholdFor(monitorapi.PodRunningToReady, "openshift-dns"); threshold != nil {
+		t.Errorf("expected no threshold for a namespace no threshold selects, got %#v", threshold)
+	}
+}
+
+func TestPodLifecycleLatency(t *testing.T) {
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	latency := func(namespace, phase string, d time.Duration) monitorapi.EventInterval {
+		return monitorapi.EventInterval{
+			Condition: monitorapi.Condition{
+				Level:   monitorapi.Info,
+				Locator: "ns/" + namespace + " pod/p node/n",
+				Message: "reason/PodLifecycleLatency phase/" + phase + " owner/ReplicaSet",
+			},
+			From: start,
+			To:   start.Add(d),
+		}
+	}
+	configured := &PodLatencyThresholdList{
+		Thresholds: []PodLatencyThreshold{
+			{Latency: monitorapi.PodCreateToScheduled, Namespace: "openshift-etcd", P99: durationThreshold(10 * time.Second), Max: &metav1.Duration{Duration: time.Minute}},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		ctx     InvariantContext
+		events  monitorapi.Intervals
+		results int
+		failed  bool
+		output  string
+	}{
+		{
+			name: "only test namespaces slow",
+			events: monitorapi.Intervals{
+				latency("openshift-etcd", monitorapi.PodCreateToScheduled, time.Second),
+				latency("e2e-test-1", monitorapi.PodCreateToScheduled, time.Hour),
+			},
+			results: 1,
+		},
+		{
+			name: "over default threshold",
+			events: monitorapi.Intervals{
+				latency("openshift-etcd", monitorapi.PodCreateToScheduled, time.Second),
+				latency("openshift-etcd", monitorapi.PodCreateToScheduled, 2*time.Minute),
+			},
+			results: 2,
+			failed:  true,
+			output:  "ns/openshift-etcd CreateToScheduled over 2 pods: p99 2m0s > 1m0s",
+		},
+		{
+			name: "over configured threshold",
+			ctx:  InvariantContext{PodLatencyThresholds: configured},
+			events: monitorapi.Intervals{
+				latency("openshift-etcd", monitorapi.PodCreateToScheduled, time.Second),
+				latency("openshift-etcd", monitorapi.PodCreateToScheduled, 30*time.Second),
+			},
+			results: 1,
+			failed:  true,
+			output:  "ns/openshift-etcd CreateToScheduled over 2 pods: p99 30s > 10s",
+		},
+		{
+			name: "default threshold of a latency not configured",
+			ctx:  InvariantContext{PodLatencyThresholds: configured},
+			events: monitorapi.Intervals{
+				latency("openshift-etcd", monitorapi.PodRunningToReady, 10*time.Minute),
+			},
+			results: 2,
+			failed:  true,
+			output:  "ns/openshift-etcd RunningToReady over 1 pods: p99 10m0s > 5m0s",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := tt.ctx.testPodLifecycleLatency(tt.events)
+			if len(results) != tt.results || (results[0].FailureOutput != nil) != tt.failed {
+				t.Fatalf("expected %d results failing %t, got %#v", tt.results, tt.failed, results)
+			}
+			if tt.failed && !strings.Contains(results[0].FailureOutput.Output, tt.output) {
+				t.Errorf("expected %q in failure output: %s", tt.output, results[0].FailureOutput.Output)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package synthetictests
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// testUpgradeHops reports each upgrade of a run that upgrades through several releases as its
+// own test, with the timing and disruption of that upgrade as the output. A run with a
+// single upgrade is already covered by the tests the upgrade records.
+func testUpgradeHops(events monitorapi.Intervals, disruptionBackends map[string]string) []*ginkgo.JUnitTestCase {
+	hops := monitor.ComputeUpgradeHops(events, disruptionBackends)
+	if len(hops) < 2 {
+		return nil
+	}
+
+	var tests []*ginkgo.JUnitTestCase
+	for _, hop := range hops {
+		testName := fmt.Sprintf("[sig-cluster-lifecycle] upgrade hop %d of %d completes", hop.Hop, len(hops))
+		test := &ginkgo.JUnitTestCase{
+			Name:      testName,
+			Duration:  hop.Ended.Sub(hop.Started).Seconds(),
+			SystemOut: describeUpgradeHop(hop),
+		}
+		switch hop.Result {
+		case monitor.UpgradeHopFailed:
+			test.FailureOutput = &ginkgo.FailureOutput{
+				Output: fmt.Sprintf("upgrade to %s failed: %s\n\n%s", upgradeHopTarget(hop), hop.Failure, test.SystemOut),
+			}
+		case monitor.UpgradeHopIncomplete:
+			test.FailureOutput = &ginkgo.FailureOutput{
+				Output: fmt.Sprintf("upgrade to %s did not complete before the run ended\n\n%s", upgradeHopTarget(hop), test.SystemOut),
+			}
+		}
+		tests = append(tests, test)
+	}
+	return tests
+}
+
+func upgradeHopTarget(hop *monitor.UpgradeHop) string {
+	switch {
+	case len(hop.Version) > 0 && hop.Version != "0.0.0" && len(hop.Image) > 0:
+		return fmt.Sprintf("%s (%s)", hop.Version, hop.Image)
+	case len(hop.Image) > 0:
+		return hop.Image
+	default:
+		return hop.Version
+	}
+}
+
+// describeUpgradeHop summarizes the timing and disruption of an upgrade.
+func describeUpgradeHop(hop *monitor.UpgradeHop) string {
+	var lines []string
+	lines = append(lines, fmt.Sprintf("upgrade to %s: %s after %s", upgradeHopTarget(hop), strings.ToLower(hop.Result), hop.Ended.Sub(hop.Started).Round(time.Second)))
+	for _, phase := range []struct {
+		name  string
+		since *metav1.Duration
+	}{
+		{"acknowledged by the cluster version operator", hop.TimeToAcknowledged},
+		{"operators at the new version", hop.TimeToVersionReached},
+		{"machine config pools updated", hop.TimeToPoolsUpdated},
+		{"operators settled", hop.TimeToComplete},
+	} {
+		if phase.since == nil {
+			lines = append(lines, fmt.Sprintf("  %s: not reached", phase.name))
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("  %s: after %s", phase.name, phase.since.Duration.Round(time.Second)))
+	}
+	if hop.BackendDisruption != nil {
+		var names []string
+		for name, disruption := range hop.BackendDisruption.BackendDisruptions {
+			if disruption.DisruptedDuration.Duration > 0 {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		if len(names) == 0 {
+			lines = append(lines, "no backend disruption")
+		} else {
+			lines = append(lines, "backend disruption:")
+		}
+		for _, name := range names {
+			lines = append(lines, fmt.Sprintf("  %s: %s", name, hop.BackendDisruption.BackendDisruptions[name].DisruptedDuration.Duration))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
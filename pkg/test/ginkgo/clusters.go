@@ -0,0 +1,158 @@
+package ginkgo
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// FailureCluster groups the tests that failed with the same failure signature, which
+// usually means they share a root cause.
+type FailureCluster struct {
+	// Signature is the message of the failure with volatile tokens such as namespaces,
+	// addresses, UIDs and times replaced by placeholders.
+	Signature string `json:"signature"`
+	// Example is the failure message of the first test in the cluster as it was reported.
+	Example string `json:"example"`
+	// Locations are the places in the tests the failure was reported from, since the same
+	// root cause is often asserted on in several places.
+	Locations []string `json:"locations,omitempty"`
+	// Count is the number of distinct tests that failed with the signature.
+	Count int `json:"count"`
+	// Attempts is the number of failures with the signature, which is more than Count when
+	// a test failed on a retry or in several runs of --count.
+	Attempts int      `json:"attempts"`
+	Tests    []string `json:"tests"`
+}
+
+// failureLinePattern matches the start of the failure TestOptions.Run prints when a test
+// fails. The message continues to the end of the output, as Gomega failures span several
+// lines and their first line is often only "Unexpected error:".
+var failureLinePattern = regexp.MustCompile(`(?m)^fail \[([^\]]+)\]: `)
+
+// failureMessageReplacements replace the tokens in a failure message that differ each
+// time the same failure occurs. They are applied in order.
+var failureMessageReplacements = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(?:\.\d+)?(?:Z|[+-]\d{2}:?\d{2})?(?: [+-]\d{4})?(?: [A-Z]{3,4})?(?: m=[+-][\d.]+)?`), "<time>"},
+	{regexp.MustCompile(`\b\d{2}:\d{2}:\d{2}(?:\.\d+)?\b`), "<time>"},
+	{regexp.MustCompile(`\b[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}\b`), "<uid>"},
+	{regexp.MustCompile(`\b\d{1,3}(?:\.\d{1,3}){3}(?::\d+)?\b`), "<ip>"},
+	{regexp.MustCompile(`\[[0-9a-fA-F]*:[0-9a-fA-F:]*:[0-9a-fA-F]*\](?::\d+)?`), "<ip>"},
+	{regexp.MustCompile(`\b(?:[0-9a-fA-F]{1,4}::?){2,7}[0-9a-fA-F]{1,4}\b`), "<ip>"},
+	{regexp.MustCompile(`\be2e-[a-z0-9-]+-[a-z0-9]{5}\b`), "<namespace>"},
+	{regexp.MustCompile(`\b([a-z][a-z0-9-]*)-\d{3,5}\b`), "$1-<n>"},
+	{regexp.MustCompile(`\b0x[0-9a-f]+\b`), "<addr>"},
+	{regexp.MustCompile(`\b\d+(?:\.\d+)?(?:ns|µs|ms|s|m|h)(?:\d+(?:\.\d+)?(?:ns|µs|ms|s|m))*\b`), "<duration>"},
+}
+
+// normalizeFailureMessage replaces the volatile tokens in a failure message with
+// placeholders so that the same failure in different tests has the same message.
+func normalizeFailureMessage(message string) string {
+	for _, r := range failureMessageReplacements {
+		message = r.pattern.ReplaceAllString(message, r.replacement)
+	}
+	return strings.Join(strings.Fields(message), " ")
+}
+
+// failureSignature returns the normalized signature of the failure in the output of a test,
+// the failure message as it was reported and the location it was reported from. When the
+// test did not report a failure, for instance because it timed out, the last line of its
+// output is used and the location is empty.
+func failureSignature(output []byte) (string, string, string) {
+	if matches := failureLinePattern.FindAllSubmatchIndex(output, -1); len(matches) > 0 {
+		last := matches[len(matches)-1]
+		location, message := string(output[last[2]:last[3]]), strings.TrimSpace(string(output[last[1]:]))
+		return normalizeFailureMessage(message), message, location
+	}
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	last := strings.TrimSpace(lines[len(lines)-1])
+	if len(last) == 0 {
+		return "<no output>", "", ""
+	}
+	return normalizeFailureMessage(last), last, ""
+}
+
+// clusterFailures groups the failed tests by the signature of their failure, largest
+// cluster first. A test that failed several times with the same signature is listed once.
+func clusterFailures(tests []*testCase) []*FailureCluster {
+	bySignature := make(map[string]*FailureCluster)
+	locations := make(map[*FailureCluster]sets.String)
+	names := make(map[*FailureCluster]sets.String)
+	var clusters []*FailureCluster
+	for _, test := range tests {
+		signature, example, location := failureSignature(test.out)
+		cluster, ok := bySignature[signature]
+		if !ok {
+			cluster = &FailureCluster{Signature: signature, Example: example}
+			bySignature[signature] = cluster
+			locations[cluster] = sets.NewString()
+			names[cluster] = sets.NewString()
+			clusters = append(clusters, cluster)
+		}
+		if len(location) > 0 {
+			locations[cluster].Insert(location)
+		}
+		names[cluster].Insert(test.name)
+		cluster.Attempts++
+	}
+	for _, cluster := range clusters {
+		if locations[cluster].Len() > 0 {
+			cluster.Locations = locations[cluster].List()
+		}
+		cluster.Tests = names[cluster].List()
+		cluster.Count = len(cluster.Tests)
+	}
+	sort.SliceStable(clusters, func(i, j int) bool {
+		if clusters[i].Count != clusters[j].Count {
+			return clusters[i].Count > clusters[j].Count
+		}
+		return clusters[i].Signature < clusters[j].Signature
+	})
+	return clusters
+}
+
+// printFailureClusters lists each failure signature with the locations it was reported from
+// and the tests that failed with it.
+func printFailureClusters(out io.Writer, clusters []*FailureCluster) {
+	fmt.Fprintf(out, "Failure clusters:\n\n")
+	for _, cluster := range clusters {
+		noun := "tests"
+		if cluster.Count == 1 {
+			noun = "test"
+		}
+		if cluster.Attempts > cluster.Count {
+			fmt.Fprintf(out, "%d %s (%d failures): %s\n", cluster.Count, noun, cluster.Attempts, cluster.Signature)
+		} else {
+			fmt.Fprintf(out, "%d %s: %s\n", cluster.Count, noun, cluster.Signature)
+		}
+		if len(cluster.Locations) > 0 {
+			fmt.Fprintf(out, "  at %s\n", strings.Join(cluster.Locations, ", "))
+		}
+		for _, name := range cluster.Tests {
+			fmt.Fprintf(out, "  %s\n", name)
+		}
+		fmt.Fprintln(out)
+	}
+}
+
+// writeFailureClusters writes the failure clusters to failure-clusters.json in dir.
+func writeFailureClusters(dir string, clusters []*FailureCluster) error {
+	if clusters == nil {
+		clusters = []*FailureCluster{}
+	}
+	data, err := json.MarshalIndent(clusters, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(dir, "failure-clusters.json"), data, 0644)
+}
@@ -0,0 +1,125 @@
+package ginkgo
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeFailureMessage(t *testing.T) {
+	for message, expected := range map[string]string{
+		`pods "web-1" in namespace "e2e-test-router-x7k2p" were not ready`:                `pods "web-1" in namespace "<namespace>" were not ready`,
+		`namespace kubectl-4821 was not deleted`:                                          `namespace kubectl-<n> was not deleted`,
+		`Get "https://172.30.0.1:443/api": dial tcp 172.30.0.1:443: i/o timeout`:          `Get "https://<ip>/api": dial tcp <ip>: i/o timeout`,
+		`dial tcp [fd02::1]:443: connect: connection refused`:                             `dial tcp <ip>: connect: connection refused`,
+		`dial tcp [fd02:0:0:1::1]:443: connection refused`:                                `dial tcp <ip>: connection refused`,
+		`pod 6b0f6c1e-8b8a-4f8a-9a2e-0c7f7f3b2d11 was evicted`:                            `pod <uid> was evicted`,
+		`timed out at 2021-08-04T10:15:42.123Z after 5m0.5s`:                              `timed out at <time> after <duration>`,
+		`I0804 10:15:42.123456 event   at 2021-08-04 10:15:42 +0000 UTC m=+12.5 was late`: `I0804 <time> event at <time> was late`,
+	} {
+		if actual := normalizeFailureMessage(message); actual != expected {
+			t.Errorf("expected %q to normalize to %q, got %q", message, expected, actual)
+		}
+	}
+}
+
+func TestClusterFailures(t *testing.T) {
+	tests := []*testCase{
+		{name: "b", out: []byte("STEP: creating\nfail [framework.go:123]: error creating namespace e2e-test-b-abcde: Post \"https://10.0.0.1:6443\": i/o timeout\n")},
+		{name: "a", out: []byte("fail [framework.go:123]: error creating namespace e2e-test-a-vwxyz: Post \"https://10.0.0.2:6443\": i/o timeout")},
+		{name: "c", out: []byte("fail [builds.go:55]: build failed")},
+		{name: "d", out: []byte("STEP: waiting\nProcess did not finish before 15m0s timeout\n")},
+		{name: "e", out: []byte("fail [other.go:1]: first\nfail [framework.go:123]: error creating namespace e2e-test-e-12345: Post \"https://10.0.0.3:6443\": i/o timeout")},
+		{name: "f", out: []byte("fail [builder.go:80]: build failed")},
+	}
+	clusters := clusterFailures(tests)
+	var signatures []string
+	for _, cluster := range clusters {
+		signatures = append(signatures, cluster.Signature)
+	}
+	expected := []string{
+		`error creating namespace <namespace>: Post "https://<ip>": i/o timeout`,
+		"build failed",
+		"Process did not finish before <duration> timeout",
+	}
+	if !reflect.DeepEqual(signatures, expected) {
+		t.Fatalf("unexpected signatures: %q", signatures)
+	}
+	if first := clusters[0]; first.Count != 3 || !reflect.DeepEqual(first.Tests, []string{"a", "b", "e"}) ||
+		!reflect.DeepEqual(first.Locations, []string{"framework.go:123"}) ||
+		first.Example != `error creating namespace e2e-test-b-abcde: Post "https://10.0.0.1:6443": i/o timeout` {
+		t.Fatalf("unexpected cluster: %#v", first)
+	}
+	// the same failure reported from different places is one cluster
+	if second := clusters[1]; second.Count != 2 || !reflect.DeepEqual(second.Locations, []string{"builder.go:80", "builds.go:55"}) {
+		t.Fatalf("unexpected cluster: %#v", second)
+	}
+	if third := clusters[2]; third.Count != 1 || third.Locations != nil {
+		t.Fatalf("unexpected cluster: %#v", third)
+	}
+
+	buf := &bytes.Buffer{}
+	printFailureClusters(buf, clusters)
+	if !strings.Contains(buf.String(), "Failure clusters:\n\n3 tests: error creating namespace") || !strings.Contains(buf.String(), "2 tests: build failed\n  at builder.go:80, builds.go:55\n  c\n  f\n") {
+		t.Fatalf("unexpected output:\n%s", buf.String())
+	}
+}
+
+func TestClusterFailuresRetried(t *testing.T) {
+	// a test that failed on its retry or in several runs of --count is counted once
+	tests := []*testCase{
+		{name: "a", out: []byte("fail [pods.go:42]: pod never became ready")},
+		{name: "a", out: []byte("fail [pods.go:42]: pod never became ready")},
+		{name: "b", out: []byte("fail [pods.go:42]: pod never became ready")},
+	}
+	clusters := clusterFailures(tests)
+	if len(clusters) != 1 {
+		t.Fatalf("expected one cluster, got %#v", clusters)
+	}
+	if cluster := clusters[0]; cluster.Count != 2 || cluster.Attempts != 3 || !reflect.DeepEqual(cluster.Tests, []string{"a", "b"}) {
+		t.Fatalf("unexpected cluster: %#v", cluster)
+	}
+
+	buf := &bytes.Buffer{}
+	printFailureClusters(buf, clusters)
+	if !strings.Contains(buf.String(), "2 tests (3 failures): pod never became ready\n  at pods.go:42\n  a\n  b\n") {
+		t.Fatalf("unexpected output:\n%s", buf.String())
+	}
+}
+
+func TestClusterFailuresMultiLine(t *testing.T) {
+	unexpectedError := func(namespace, address, ip string) string {
+		return fmt.Sprintf(`Unexpected error:
+    <*url.Error | %s>: {
+        Op: "Get",
+        URL: "https://%s:443/api/v1/namespaces/%s/pods",
+        Err: {Op: "dial", Net: "tcp", Source: nil, Addr: {IP: [%s], Port: 443, Zone: ""}},
+    }
+    Get "https://%s:443/api/v1/namespaces/%s/pods": dial tcp %s:443: i/o timeout
+occurred`, address, ip, namespace, ip, ip, namespace, ip)
+	}
+	tests := []*testCase{
+		{name: "a", out: []byte("STEP: listing pods\nfail [pods.go:42]: " + unexpectedError("e2e-test-a-abcde", "0xc000a1b2c0", "172.30.0.1") + "\n")},
+		{name: "b", out: []byte("fail [routes.go:17]: " + unexpectedError("e2e-test-b-vwxyz", "0xc000d4e5f0", "172.30.0.2"))},
+		{name: "c", out: []byte("fail [pods.go:42]: Unexpected error:\n    <*errors.errorString | 0xc000f00ba0>: {\n        s: \"pod never became ready\",\n    }\n    pod never became ready\noccurred")},
+	}
+	clusters := clusterFailures(tests)
+	if len(clusters) != 2 {
+		t.Fatalf("expected the two root causes to be separate clusters, got %#v", clusters)
+	}
+	first := clusters[0]
+	if first.Count != 2 || !reflect.DeepEqual(first.Tests, []string{"a", "b"}) || !reflect.DeepEqual(first.Locations, []string{"pods.go:42", "routes.go:17"}) {
+		t.Fatalf("unexpected cluster: %#v", first)
+	}
+	if !strings.Contains(first.Signature, `Get "https://<ip>/api/v1/namespaces/<namespace>/pods": dial tcp <ip>: i/o timeout occurred`) {
+		t.Errorf("expected the signature to hold the whole failure, got %q", first.Signature)
+	}
+	if !strings.HasPrefix(first.Example, "Unexpected error:\n") || !strings.HasSuffix(first.Example, "\noccurred") {
+		t.Errorf("expected the example to be reported as is, got %q", first.Example)
+	}
+	if second := clusters[1]; !strings.Contains(second.Signature, "pod never became ready") || !reflect.DeepEqual(second.Locations, []string{"pods.go:42"}) {
+		t.Fatalf("unexpected cluster: %#v", second)
+	}
+}
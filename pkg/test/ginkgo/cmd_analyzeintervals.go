@@ -0,0 +1,193 @@
+package ginkgo
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+	"github.com/openshift/origin/pkg/version"
+)
+
+// AnalyzeIntervalsOptions evaluates synthetic invariants against the intervals saved by
+// previous runs, without access to a cluster.
+type AnalyzeIntervalsOptions struct {
+	// Invariants are evaluated against the intervals of each run. They are passed a nil
+	// rest.Config and must not require a cluster.
+	Invariants JUnitsForEvents
+
+	JUnitDir    string
+	Out, ErrOut io.Writer
+}
+
+// Run evaluates the invariants against each of the provided e2e-events_*.json files (or
+// directories containing them) and writes a JUnit report per file to JUnitDir, if set.
+// An error is returned if any invariant failed without also passing.
+func (opt *AnalyzeIntervalsOptions) Run(paths []string) error {
+	if len(paths) == 0 {
+		return fmt.Errorf("specify one or more e2e-events_*.json files or directories containing them")
+	}
+	if opt.Invariants == nil {
+		return fmt.Errorf("no invariants were selected")
+	}
+
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		err = filepath.Walk(path, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if !info.IsDir() && strings.HasPrefix(info.Name(), "e2e-events") && strings.HasSuffix(info.Name(), ".json") {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no e2e-events_*.json files were found in %s", strings.Join(paths, ", "))
+	}
+
+	if len(opt.JUnitDir) > 0 {
+		if err := os.MkdirAll(opt.JUnitDir, 0755); err != nil {
+			return fmt.Errorf("could not create --junit-dir: %v", err)
+		}
+	}
+
+	// failedRuns counts the number of runs in which each invariant failed
+	failedRuns := make(map[string]int)
+	reportNames := make(map[string]int)
+	var runsWithFailures int
+	for _, file := range files {
+		events, err := monitorserialization.EventsFromFile(file)
+		if err != nil {
+			return fmt.Errorf("unable to read intervals from %s: %v", file, err)
+		}
+		duration := intervalsDuration(events)
+		tests := opt.Invariants.JUnitsForEvents(events, duration, nil)
+		failing, flaky := failingAndFlakyTestCases(tests)
+		for _, name := range failing {
+			failedRuns[name]++
+		}
+		if len(failing) > 0 {
+			runsWithFailures++
+		}
+		fmt.Fprintf(opt.Out, "%s: %d intervals, %d fail, %d flake, %d invariants\n", file, len(events), len(failing), len(flaky), len(uniqueTestCaseNames(tests)))
+		for _, name := range failing {
+			fmt.Fprintf(opt.Out, "  failed: %s\n", name)
+		}
+
+		if len(opt.JUnitDir) == 0 {
+			continue
+		}
+		name := strings.TrimSuffix(filepath.Base(file), ".json")
+		if n := reportNames[name]; n > 0 {
+			reportNames[name]++
+			name = fmt.Sprintf("%s-%d", name, n)
+		} else {
+			reportNames[name] = 1
+		}
+		if err := writeInvariantsReport(filepath.Join(opt.JUnitDir, fmt.Sprintf("junit_invariants_%s.xml", name)), tests, duration); err != nil {
+			return err
+		}
+	}
+
+	if len(files) > 1 && len(failedRuns) > 0 {
+		var names []string
+		for name := range failedRuns {
+			names = append(names, name)
+		}
+		sort.Slice(names, func(i, j int) bool {
+			if failedRuns[names[i]] != failedRuns[names[j]] {
+				return failedRuns[names[i]] > failedRuns[names[j]]
+			}
+			return names[i] < names[j]
+		})
+		fmt.Fprintf(opt.Out, "\nFailing invariants by number of runs:\n\n")
+		for _, name := range names {
+			fmt.Fprintf(opt.Out, "%4d/%d %s\n", failedRuns[name], len(files), name)
+		}
+		fmt.Fprintln(opt.Out)
+	}
+
+	summary := fmt.Sprintf("%d of %d runs failed one or more invariants", runsWithFailures, len(files))
+	if runsWithFailures > 0 {
+		return fmt.Errorf("%s", summary)
+	}
+	fmt.Fprintln(opt.Out, summary)
+	return nil
+}
+
+// intervalsDuration returns the time between the earliest start and latest end of the
+// provided intervals, which approximates the duration of the run that recorded them.
+func intervalsDuration(events monitorapi.Intervals) time.Duration {
+	var from, to time.Time
+	for _, event := range events {
+		if !event.From.IsZero() && (from.IsZero() || event.From.Before(from)) {
+			from = event.From
+		}
+		if event.To.After(to) {
+			to = event.To
+		}
+		if event.From.After(to) {
+			to = event.From
+		}
+	}
+	if from.IsZero() || !to.After(from) {
+		return 0
+	}
+	return to.Sub(from)
+}
+
+func uniqueTestCaseNames(tests []*JUnitTestCase) map[string]struct{} {
+	names := make(map[string]struct{})
+	for _, test := range tests {
+		names[test.Name] = struct{}{}
+	}
+	return names
+}
+
+func writeInvariantsReport(path string, tests []*JUnitTestCase, duration time.Duration) error {
+	s := &JUnitTestSuite{
+		Name:     "invariants",
+		Duration: duration.Seconds(),
+		Properties: []*TestSuiteProperty{
+			{
+				Name:  "TestVersion",
+				Value: version.Get().String(),
+			},
+		},
+	}
+	for _, test := range tests {
+		s.NumTests++
+		switch {
+		case test.FailureOutput != nil:
+			s.NumFailed++
+		case test.SkipMessage != nil:
+			s.NumSkipped++
+		}
+		s.TestCases = append(s.TestCases, test)
+	}
+	out, err := xml.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, out, 0640)
+}
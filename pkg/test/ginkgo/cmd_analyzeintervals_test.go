@@ -0,0 +1,64 @@
+package ginkgo
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+	"k8s.io/client-go/rest"
+)
+
+func TestAnalyzeIntervals(t *testing.T) {
+	dir := t.TempDir()
+	from := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	for name, message := range map[string]string{"e2e-events_1.json": "reason/Bad", "e2e-events_2.json": "reason/Good"} {
+		events := monitorapi.Intervals{{
+			Condition: monitorapi.Condition{Level: monitorapi.Info, Locator: "node/a", Message: message},
+			From:      from,
+			To:        from.Add(time.Hour),
+		}}
+		if err := monitorserialization.EventsToFile(filepath.Join(dir, name), events); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var durations []time.Duration
+	out := &bytes.Buffer{}
+	opt := &AnalyzeIntervalsOptions{
+		Invariants: JUnitForEventsFunc(func(events monitorapi.Intervals, duration time.Duration, kubeClientConfig *rest.Config) []*JUnitTestCase {
+			durations = append(durations, duration)
+			if kubeClientConfig != nil {
+				t.Errorf("expected a nil rest.Config")
+			}
+			test := &JUnitTestCase{Name: "no bad nodes"}
+			for _, event := range events {
+				if event.TypedMessage().Reason == "Bad" {
+					test.FailureOutput = &FailureOutput{Output: event.String()}
+				}
+			}
+			return []*JUnitTestCase{test}
+		}),
+		JUnitDir: filepath.Join(dir, "junit"),
+		Out:      out,
+		ErrOut:   ioutil.Discard,
+	}
+	err := opt.Run([]string{dir})
+	if err == nil || err.Error() != "1 of 2 runs failed one or more invariants" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(durations) != 2 || durations[0] != time.Hour {
+		t.Errorf("unexpected durations: %v", durations)
+	}
+	if !strings.Contains(out.String(), "   1/2 no bad nodes") {
+		t.Errorf("unexpected summary:\n%s", out.String())
+	}
+	reports, _ := filepath.Glob(filepath.Join(dir, "junit", "junit_invariants_*.xml"))
+	if len(reports) != 2 {
+		t.Errorf("expected 2 reports, got %v", reports)
+	}
+}
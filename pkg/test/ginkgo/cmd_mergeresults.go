@@ -0,0 +1,183 @@
+package ginkgo
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// MergeResultsOptions combines the JUnit reports written by the shards of a suite
+// into a single report.
+type MergeResultsOptions struct {
+	JUnitDir    string
+	Out, ErrOut io.Writer
+}
+
+// Run reads the JUnit reports at the provided paths (files, or directories containing
+// junit_e2e_*.xml reports) and writes a merged report to JUnitDir. A test that failed
+// and passed in the same report is reported as a flake.
+func (opt *MergeResultsOptions) Run(paths []string) error {
+	if len(paths) == 0 {
+		return fmt.Errorf("specify one or more JUnit reports or directories of reports to merge")
+	}
+	if len(opt.JUnitDir) == 0 {
+		return fmt.Errorf("--junit-dir is required")
+	}
+
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		matches, err := filepath.Glob(filepath.Join(path, "junit_e2e_*.xml"))
+		if err != nil {
+			return err
+		}
+		files = append(files, matches...)
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no JUnit reports were found in %s", strings.Join(paths, ", "))
+	}
+
+	var suites []*JUnitTestSuite
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		suite := &JUnitTestSuite{}
+		if err := xml.Unmarshal(data, suite); err != nil {
+			return fmt.Errorf("unable to read JUnit report %s: %v", file, err)
+		}
+		suites = append(suites, suite)
+	}
+
+	merged := mergeJUnitSuites(suites)
+
+	if err := os.MkdirAll(opt.JUnitDir, 0755); err != nil {
+		return fmt.Errorf("could not create --junit-dir: %v", err)
+	}
+	if err := writeJUnitSuite("junit_e2e", merged, opt.JUnitDir, opt.ErrOut); err != nil {
+		return err
+	}
+
+	failing, flaky := failingAndFlakyTestCases(merged.TestCases)
+	if len(flaky) > 0 {
+		fmt.Fprintf(opt.Out, "Flaky tests:\n\n%s\n\n", strings.Join(flaky, "\n"))
+	}
+	if len(failing) > 0 {
+		fmt.Fprintf(opt.Out, "Failing tests:\n\n%s\n\n", strings.Join(failing, "\n"))
+	}
+	pass := merged.NumTests - merged.NumFailed - merged.NumSkipped - uint(len(flaky))
+	summary := fmt.Sprintf("%d fail, %d flake, %d pass, %d skip (merged from %d reports)", len(failing), len(flaky), pass, merged.NumSkipped, len(suites))
+	if len(failing) > 0 {
+		return fmt.Errorf("%s", summary)
+	}
+	fmt.Fprintln(opt.Out, summary)
+	return nil
+}
+
+// mergeJUnitSuites combines the test cases of the provided suites. Every failure is
+// preserved, but repeated passes or skips of the same test (such as the invariants
+// each shard evaluates) are reported once. A test that failed and passed within one
+// suite is a flake, following the convention used by writeJUnitReport, but a test that
+// only failed in any suite is a failure even if it passed in another, since tests that
+// are not retried (invariants and monitor tests) are evaluated by every shard. The merged
+// duration is that of the longest suite since shards run concurrently.
+func mergeJUnitSuites(suites []*JUnitTestSuite) *JUnitTestSuite {
+	merged := &JUnitTestSuite{}
+	if len(suites) > 0 {
+		merged.Name = suites[0].Name
+		merged.Properties = suites[0].Properties
+	}
+
+	var names []string
+	failures := make(map[string][]*JUnitTestCase)
+	passes := make(map[string]*JUnitTestCase)
+	skips := make(map[string]*JUnitTestCase)
+	// failed are the tests that failed without passing in at least one suite
+	failed := make(map[string]bool)
+	for _, suite := range suites {
+		if suite.Duration > merged.Duration {
+			merged.Duration = suite.Duration
+		}
+		suiteFailing, _ := failingAndFlakyTestCases(suite.TestCases)
+		for _, name := range suiteFailing {
+			failed[name] = true
+		}
+		for _, test := range suite.TestCases {
+			if _, ok := failures[test.Name]; !ok {
+				if _, ok := passes[test.Name]; !ok {
+					if _, ok := skips[test.Name]; !ok {
+						names = append(names, test.Name)
+					}
+				}
+			}
+			switch {
+			case test.FailureOutput != nil:
+				failures[test.Name] = append(failures[test.Name], test)
+			case test.SkipMessage != nil:
+				if _, ok := skips[test.Name]; !ok {
+					skips[test.Name] = test
+				}
+			default:
+				if _, ok := passes[test.Name]; !ok {
+					passes[test.Name] = test
+				}
+			}
+		}
+	}
+
+	for _, name := range names {
+		for _, test := range failures[name] {
+			merged.NumTests++
+			merged.NumFailed++
+			merged.TestCases = append(merged.TestCases, test)
+		}
+		if test, ok := passes[name]; ok && !failed[name] {
+			merged.NumTests++
+			merged.TestCases = append(merged.TestCases, test)
+			continue
+		}
+		if test, ok := skips[name]; ok && len(failures[name]) == 0 {
+			merged.NumTests++
+			merged.NumSkipped++
+			merged.TestCases = append(merged.TestCases, test)
+		}
+	}
+	return merged
+}
+
+// failingAndFlakyTestCases returns the sorted names of tests that only failed and of
+// tests that both failed and passed.
+func failingAndFlakyTestCases(tests []*JUnitTestCase) (failing, flaky []string) {
+	failed, passed := make(map[string]bool), make(map[string]bool)
+	for _, test := range tests {
+		switch {
+		case test.FailureOutput != nil:
+			failed[test.Name] = true
+		case test.SkipMessage == nil:
+			passed[test.Name] = true
+		}
+	}
+	for name := range failed {
+		if passed[name] {
+			flaky = append(flaky, name)
+		} else {
+			failing = append(failing, name)
+		}
+	}
+	sort.Strings(failing)
+	sort.Strings(flaky)
+	return failing, flaky
+}
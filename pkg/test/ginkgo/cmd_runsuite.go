@@ -46,6 +46,8 @@ type Options struct {
 	Regex string
 	// MatchFn if set is also used to filter the suite contents
 	MatchFn func(name string) bool
+	// Select is a boolean expression over test tags that selects a subset of tests
+	Select string
 
 	// SyntheticEventTests allows the caller to translate events or outside
 	// context into a failure.
@@ -59,7 +61,46 @@ type Options struct {
 	PrintCommands bool
 	Out, ErrOut   io.Writer
 
+	// DryRunOutput prints the tests of a dry run with their metadata as json or yaml.
+	DryRunOutput string
+	// AvailableSuites are the suites reported as selecting each test in a structured dry run.
+	AvailableSuites []*TestSuite
+
 	StartTime time.Time
+
+	// Resume skips tests whose outcome was recorded in the journal of a previous,
+	// interrupted run in JUnitDir and reports them as part of this run.
+	Resume bool
+
+	// TimingsFile is a JSON file of historical test durations, or a JUnit report or
+	// directory of reports to harvest them from, used to start the longest tests first.
+	TimingsFile string
+	// UpdateTimings writes the durations observed in this run back to TimingsFile.
+	UpdateTimings bool
+
+	// Shard of the form N/M runs only the Nth of M deterministic, duration balanced
+	// partitions of the suite. [Early] and [Late] tests are only run by the first shard.
+	Shard string
+
+	// ListenAddress, if set, serves the intervals recorded by the monitor over HTTP while
+	// the suite runs.
+	ListenAddress string
+	// MetricsListenAddress, if set, serves Prometheus metrics derived from the intervals
+	// recorded by the monitor while the suite runs.
+	MetricsListenAddress string
+	// DisruptionConfig, if set, is a YAML file of additional backends the monitor polls for
+	// disruption while the suite runs.
+	DisruptionConfig string
+
+	// RetryPolicy, if set, is a comma delimited list of settings that override the retry
+	// policy of the suite. See ParseRetryPolicy.
+	RetryPolicy string
+	// QuarantineFile, if set, is a YAML list of known flaky tests that run but never fail
+	// the suite. See QuarantineList.
+	QuarantineFile string
+	// ReportFormats are additional reports written as FORMAT or FORMAT=PATH, where FORMAT
+	// is junit, jsonl, tap or markdown. The JUnit report is always written to JUnitDir.
+	ReportFormats []string
 }
 
 func (opt *Options) AsEnv() []string {
@@ -112,6 +153,17 @@ func (opt *Options) SelectSuite(suites []*TestSuite, args []string) (*TestSuite,
 }
 
 func (opt *Options) Run(suite *TestSuite) error {
+	if len(opt.DryRunOutput) > 0 {
+		if !opt.DryRun {
+			return fmt.Errorf("--output requires --dry-run")
+		}
+		if err := validateDryRunOutput(opt.DryRunOutput); err != nil {
+			return err
+		}
+	}
+	// capture suite membership before the selected suite is narrowed by this run
+	suiteMatchers := newSuiteMatchers(opt.AvailableSuites)
+
 	if len(opt.Regex) > 0 {
 		if err := filterWithRegex(suite, opt.Regex); err != nil {
 			return err
@@ -123,6 +175,13 @@ func (opt *Options) Run(suite *TestSuite) error {
 			return original(name) && opt.MatchFn(name)
 		}
 	}
+	var selector *TagSelector
+	if len(opt.Select) > 0 {
+		var err error
+		if selector, err = ParseTagSelector(opt.Select); err != nil {
+			return fmt.Errorf("--select is invalid: %v", err)
+		}
+	}
 
 	syntheticEventTests := JUnitsForAllEvents{
 		opt.SyntheticEventTests,
@@ -150,6 +209,16 @@ func (opt *Options) Run(suite *TestSuite) error {
 		return false
 	})
 
+	if selector != nil {
+		if unknown := selector.UnknownTags(testNames(tests)); len(unknown) > 0 {
+			return fmt.Errorf("--select refers to tags that no test has: %s", strings.Join(unknown, ", "))
+		}
+		original := suite.Matches
+		suite.Matches = func(name string) bool {
+			return original(name) && selector.Matches(name)
+		}
+	}
+
 	tests = suite.Filter(tests)
 	if len(tests) == 0 {
 		return fmt.Errorf("suite %q does not contain any tests", suite.Name)
@@ -160,23 +229,76 @@ func (opt *Options) Run(suite *TestSuite) error {
 		count = suite.Count
 	}
 
+	var timings *TestTimings
+	if len(opt.TimingsFile) > 0 {
+		if opt.UpdateTimings && strings.HasSuffix(opt.TimingsFile, ".xml") {
+			return fmt.Errorf("--update-timings requires --timings-file to be a JSON file")
+		}
+		timings, err = LoadTestTimings(opt.TimingsFile)
+		switch {
+		case os.IsNotExist(err) && opt.UpdateTimings:
+			// the file will be created at the end of the run
+			timings = &TestTimings{}
+		case err != nil:
+			return fmt.Errorf("could not load --timings-file: %v", err)
+		}
+	} else if opt.UpdateTimings {
+		return fmt.Errorf("--update-timings requires --timings-file")
+	}
+
+	if len(opt.Shard) > 0 {
+		shard, err := ParseShard(opt.Shard)
+		if err != nil {
+			return fmt.Errorf("--shard is invalid: %v", err)
+		}
+		tests = shard.Select(tests, timings)
+		if len(tests) == 0 {
+			return fmt.Errorf("shard %s of suite %q does not contain any tests", shard, suite.Name)
+		}
+	}
+
 	start := time.Now()
 	if opt.StartTime.IsZero() {
 		opt.StartTime = start
 	}
 
+	if len(opt.QuarantineFile) > 0 {
+		quarantine, err := LoadQuarantineList(opt.QuarantineFile)
+		if err != nil {
+			return fmt.Errorf("could not load --quarantine-file: %v", err)
+		}
+		for _, q := range quarantine.Expired(start) {
+			fmt.Fprintf(opt.ErrOut, "warning: The quarantine of %q expired on %s and no longer applies (%s)\n", q, q.Expires, q.Bug)
+		}
+		for _, test := range tests {
+			test.quarantine = quarantine.Match(test.name, start)
+		}
+	}
+
 	if opt.PrintCommands {
 		status := newTestStatus(opt.Out, true, len(tests), time.Minute, &monitor.Monitor{}, monitor.NewNoOpMonitor(), opt.AsEnv())
-		newParallelTestQueue().Execute(context.Background(), tests, 1, status.OutputCommand)
+		newParallelTestQueue(nil).Execute(context.Background(), tests, 1, status.OutputCommand)
 		return nil
 	}
 	if opt.DryRun {
+		if len(opt.DryRunOutput) > 0 {
+			return writeDryRun(opt.Out, opt.DryRunOutput, newDryRunTests(sortedTests(tests), suiteMatchers))
+		}
 		for _, test := range sortedTests(tests) {
 			fmt.Fprintf(opt.Out, "%q\n", test.name)
 		}
 		return nil
 	}
 
+	if opt.Resume {
+		if len(opt.JUnitDir) == 0 {
+			return fmt.Errorf("--resume requires --junit-dir to locate the test journal")
+		}
+		if count == -1 {
+			return fmt.Errorf("--resume may not be combined with --count=-1")
+		}
+	}
+
 	if len(opt.JUnitDir) > 0 {
 		if _, err := os.Stat(opt.JUnitDir); err != nil {
 			if !os.IsNotExist(err) {
@@ -188,6 +310,39 @@ func (opt *Options) Run(suite *TestSuite) error {
 		}
 	}
 
+	// record each test outcome as it completes so an interrupted run can be resumed
+	var completed completedTests
+	var journal *testJournal
+	suiteStart := start
+	if len(opt.JUnitDir) > 0 {
+		if opt.Resume {
+			entries, err := readTestJournal(opt.JUnitDir)
+			if err != nil {
+				return err
+			}
+			completed = newCompletedTests(entries)
+			if earliest := completed.earliestStart(); !earliest.IsZero() && earliest.Before(suiteStart) {
+				suiteStart = earliest
+				opt.StartTime = earliest
+			}
+		}
+		journal, err = openTestJournal(opt.JUnitDir, opt.Resume)
+		if err != nil {
+			return fmt.Errorf("could not open the test journal: %v", err)
+		}
+		defer journal.Close()
+	}
+
+	junitPrefix, shardSuffix := "junit_e2e", ""
+	if len(opt.Shard) > 0 {
+		shardSuffix = fmt.Sprintf("_shard-%s", strings.Replace(opt.Shard, "/", "-of-", 1))
+		junitPrefix += shardSuffix
+	}
+	reporters, err := newTestReporters(opt.ReportFormats, opt.JUnitDir, junitPrefix, shardSuffix, opt.ErrOut)
+	if err != nil {
+		return err
+	}
+
 	parallelism := opt.Parallelism
 	if parallelism == 0 {
 		parallelism = suite.Parallelism
@@ -203,6 +358,25 @@ func (opt *Options) Run(suite *TestSuite) error {
 		timeout = 15 * time.Minute
 	}
 
+	retryPolicy := suite.RetryPolicy
+	if retryPolicy == nil {
+		retryPolicy = defaultRetryPolicy(suite)
+	}
+	if len(opt.RetryPolicy) > 0 {
+		retryPolicy, err = ParseRetryPolicy(retryPolicy, opt.RetryPolicy)
+		if err != nil {
+			return err
+		}
+	}
+
+	var backends *monitor.DisruptionBackendConfig
+	if len(opt.DisruptionConfig) > 0 {
+		backends, err = monitor.LoadDisruptionBackendConfig(opt.DisruptionConfig)
+		if err != nil {
+			return err
+		}
+	}
+
 	ctx, cancelFn := context.WithCancel(context.Background())
 	defer cancelFn()
 	abortCh := make(chan os.Signal)
@@ -229,6 +403,20 @@ func (opt *Options) Run(suite *TestSuite) error {
 	if err != nil {
 		return err
 	}
+	disruptionBackends, err := monitor.StartDisruptionBackends(ctx, m, restConfig, backends)
+	if err != nil {
+		return err
+	}
+	if len(opt.ListenAddress) > 0 {
+		if err := monitor.StartServer(ctx, m, opt.ListenAddress, opt.ErrOut); err != nil {
+			return err
+		}
+	}
+	if len(opt.MetricsListenAddress) > 0 {
+		if err := monitor.StartMetricsServer(ctx, m, opt.MetricsListenAddress, disruptionBackends, opt.ErrOut); err != nil {
+			return err
+		}
+	}
 
 	pc, err := SetupNewPodCollector(ctx)
 	if err != nil {
@@ -257,7 +445,6 @@ func (opt *Options) Run(suite *TestSuite) error {
 	})
 
 	// If user specifies a count, duplicate the kube and openshift tests that many times.
-	expectedTestCount := len(early) + len(late)
 	if count != -1 {
 		originalKube := kubeTests
 		originalOpenshift := openshiftTests
@@ -267,9 +454,28 @@ func (opt *Options) Run(suite *TestSuite) error {
 			openshiftTests = append(openshiftTests, copyTests(originalOpenshift)...)
 		}
 	}
-	expectedTestCount += len(openshiftTests) + len(kubeTests)
+
+	// tests completed by a previous run are reported with their recorded outcome
+	var resumed []*testCase
+	if len(completed) > 0 {
+		var restored []*testCase
+		restored, early = completed.restore(early)
+		resumed = append(resumed, restored...)
+		restored, kubeTests = completed.restore(kubeTests)
+		resumed = append(resumed, restored...)
+		restored, openshiftTests = completed.restore(openshiftTests)
+		resumed = append(resumed, restored...)
+		restored, late = completed.restore(late)
+		resumed = append(resumed, restored...)
+		fmt.Fprintf(opt.Out, "Resuming suite, %d tests were completed by a previous run\n\n", len(resumed))
+	}
+	expectedTestCount := len(early) + len(late) + len(openshiftTests) + len(kubeTests)
 
 	status := newTestStatus(opt.Out, includeSuccess, expectedTestCount, timeout, m, m, opt.AsEnv())
+	status.ReportTo(reporters)
+	if journal != nil {
+		status.RecordTo(journal)
+	}
 	testCtx := ctx
 	if opt.FailFast {
 		var cancelFn context.CancelFunc
@@ -281,10 +487,10 @@ func (opt *Options) Run(suite *TestSuite) error {
 		})
 	}
 
-	tests = nil
+	tests = resumed
 
 	// run our Early tests
-	q := newParallelTestQueue()
+	q := newParallelTestQueue(timings)
 	q.Execute(testCtx, early, parallelism, status.Run)
 	tests = append(tests, early...)
 
@@ -331,13 +537,17 @@ func (opt *Options) Run(suite *TestSuite) error {
 	tests, _ = splitTests(tests, func(t *testCase) bool { return t.success || t.failed || t.skipped })
 
 	end := time.Now()
-	duration := end.Sub(start).Round(time.Second / 10)
+	duration := end.Sub(suiteStart).Round(time.Second / 10)
 	if duration > time.Minute {
 		duration = duration.Round(time.Second)
 	}
 
 	pass, fail, skip, failing := summarizeTests(tests)
 
+	// quarantined tests are reported but never retried or allowed to fail the suite
+	failing, quarantined := splitTests(failing, func(t *testCase) bool { return t.quarantine == nil })
+	fail -= len(quarantined)
+
 	// monitor the cluster while the tests are running and report any detected anomalies
 	var syntheticTestResults []*JUnitTestCase
 	var syntheticFailure bool
@@ -377,7 +587,7 @@ func (opt *Options) Run(suite *TestSuite) error {
 	events.Clamp(start, end)
 
 	if len(opt.JUnitDir) > 0 {
-		if err := monitor.WriteRunDataToArtifactsDir(opt.JUnitDir, m, events, timeSuffix); err != nil {
+		if err := monitor.WriteRunDataToArtifactsDir(opt.JUnitDir, m, events, disruptionBackends, timeSuffix); err != nil {
 			fmt.Fprintf(opt.ErrOut, "error: Failed to write run-data: %v\n", err)
 		}
 	}
@@ -418,54 +628,101 @@ func (opt *Options) Run(suite *TestSuite) error {
 		opt.Out.Write(buf.Bytes())
 	}
 
-	// attempt to retry failures to do flake detection
-	if fail > 0 && fail <= suite.MaximumAllowedFlakes {
-		var retries []*testCase
-		for _, test := range failing {
-			retry := test.Retry()
-			retries = append(retries, retry)
-			tests = append(tests, retry)
-			if len(retries) > suite.MaximumAllowedFlakes {
+	// attempt to retry failures to do flake detection until the retry budget runs out
+	if fail > 0 {
+		budget := retryPolicy.MaxRetries
+		var flaky []string
+		var notRetried []*testCase
+		candidates := failing
+		for attempt := 2; len(candidates) > 0 && testCtx.Err() == nil; attempt++ {
+			var retries, skipped []*testCase
+			retries, skipped, budget = retryPolicy.selectRetries(candidates, budget)
+			notRetried = append(notRetried, skipped...)
+			tests = append(tests, retries...)
+			candidates = nil
+			if len(retries) == 0 {
 				break
 			}
-		}
-
-		q := newParallelTestQueue()
-		status := newTestStatus(ioutil.Discard, opt.IncludeSuccessOutput, len(retries), timeout, m, m, opt.AsEnv())
-		q.Execute(testCtx, retries, parallelism, status.Run)
-		var flaky []string
-		var repeatFailures []*testCase
-		for _, test := range retries {
-			if test.success {
-				flaky = append(flaky, test.name)
-			} else {
-				repeatFailures = append(repeatFailures, test)
+			fmt.Fprintf(opt.Out, "Retrying %d failed tests (attempt %d, %d retries remaining)\n\n", len(retries), attempt, budget)
+
+			// the output of each attempt is kept in the JUnit results rather than printed
+			q := newParallelTestQueue(timings)
+			status := newTestStatus(ioutil.Discard, opt.IncludeSuccessOutput, len(retries), timeout, m, m, opt.AsEnv())
+			status.ReportTo(reporters)
+			q.Execute(testCtx, retries, parallelism, status.Run)
+			for _, test := range retries {
+				if test.success {
+					flaky = append(flaky, test.name)
+				} else {
+					candidates = append(candidates, test)
+				}
 			}
 		}
+		failing = append(notRetried, candidates...)
 		if len(flaky) > 0 {
-			failing = repeatFailures
 			sort.Strings(flaky)
 			fmt.Fprintf(opt.Out, "Flaky tests:\n\n%s\n\n", strings.Join(flaky, "\n"))
 		}
 	}
 
 	// report the outcome of the test
-	if len(failing) > 0 {
-		names := sets.NewString(testNames(failing)...).List()
-		fmt.Fprintf(opt.Out, "Failing tests:\n\n%s\n\n", strings.Join(names, "\n"))
+	failingNames := sets.NewString(testNames(failing)...)
+	if failingNames.Len() > 0 {
+		fmt.Fprintf(opt.Out, "Failing tests:\n\n%s\n\n", strings.Join(failingNames.List(), "\n"))
+	}
+	// group failures with the same signature so that a shared root cause stands out
+	failureClusters := clusterFailures(failing)
+	if failingNames.Len() > 1 {
+		printFailureClusters(opt.Out, failureClusters)
+	}
+	if len(quarantined) > 0 {
+		var lines []string
+		for _, test := range quarantined {
+			lines = append(lines, fmt.Sprintf("%s (%s)", test.name, test.quarantine.Bug))
+		}
+		lines = sets.NewString(lines...).List()
+		fmt.Fprintf(opt.Out, "Quarantined tests that failed:\n\n%s\n\n", strings.Join(lines, "\n"))
+	}
+
+	if opt.UpdateTimings {
+		timings.Update(tests)
+		if err := timings.Write(opt.TimingsFile); err != nil {
+			fmt.Fprintf(opt.ErrOut, "error: Unable to write test timings: %v\n", err)
+		}
 	}
 
 	if len(opt.JUnitDir) > 0 {
-		if err := writeJUnitReport("junit_e2e", "openshift-tests", tests, opt.JUnitDir, duration, opt.ErrOut, syntheticTestResults...); err != nil {
-			fmt.Fprintf(opt.Out, "error: Unable to write e2e JUnit results: %v", err)
+		// analyze each test, including retries, against everything the monitor recorded
+		intervals := append(m.Intervals(time.Time{}, time.Time{}), alertEventIntervals...)
+		sort.Sort(intervals)
+
+		// attribute the cluster impact of each test using the namespaces it created
+		impacts := attributeTestImpact(tests, intervals)
+		if err := writeTestImpact(opt.JUnitDir, impacts); err != nil {
+			fmt.Fprintf(opt.ErrOut, "error: Unable to write test impact: %v\n", err)
+		}
+		// list the disruptions that overlapped each failure so they are reported with it
+		correlations := correlateFailures(tests, intervals)
+		if err := writeFailureCorrelation(opt.JUnitDir, correlations); err != nil {
+			fmt.Fprintf(opt.ErrOut, "error: Unable to write failure correlation: %v\n", err)
+		}
+		if err := writeFailureClusters(opt.JUnitDir, failureClusters); err != nil {
+			fmt.Fprintf(opt.ErrOut, "error: Unable to write failure clusters: %v\n", err)
+		}
+	}
+
+	report := &suiteReport{name: suite.Name, duration: duration, tests: tests, syntheticResults: syntheticTestResults}
+	for _, reporter := range reporters {
+		if err := reporter.SuiteFinished(report); err != nil {
+			fmt.Fprintf(opt.Out, "error: Unable to write e2e results: %v\n", err)
 		}
 	}
 
 	if fail > 0 {
-		if len(failing) > 0 || suite.MaximumAllowedFlakes == 0 {
+		if len(failing) > 0 {
 			return fmt.Errorf("%d fail, %d pass, %d skip (%s)", fail, pass, skip, duration)
 		}
-		fmt.Fprintf(opt.Out, "%d flakes detected, suite allows passing with only flakes\n\n", fail)
+		fmt.Fprintf(opt.Out, "%d flakes detected, the retry policy allows passing with only flakes\n\n", fail)
 	}
 
 	if syntheticFailure {
@@ -0,0 +1,103 @@
+package ginkgo
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+// FailureCorrelation lists the cluster disruptions that overlapped the run of a failed test.
+type FailureCorrelation struct {
+	Name    string    `json:"name"`
+	ID      string    `json:"id"`
+	Attempt int       `json:"attempt"`
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+
+	Disruptions []ConcurrentDisruption `json:"disruptions"`
+}
+
+// ConcurrentDisruption is an interval recorded by the monitor that may explain a failure.
+type ConcurrentDisruption struct {
+	Kind    string    `json:"kind"`
+	Level   string    `json:"level"`
+	Locator string    `json:"locator"`
+	Message string    `json:"message"`
+	From    time.Time `json:"from"`
+	To      time.Time `json:"to"`
+}
+
+func (d ConcurrentDisruption) String() string {
+	if d.To.After(d.From) {
+		return fmt.Sprintf("%s for %s: %s %s", d.Kind, d.To.Sub(d.From).Round(time.Second), d.Locator, d.Message)
+	}
+	return fmt.Sprintf("%s at %s: %s %s", d.Kind, d.From.UTC().Format("15:04:05"), d.Locator, d.Message)
+}
+
+// correlateFailures finds the disruptions that overlapped each failed or flaky test, records
+// them on the test so they are reported with its failure, and returns them.
+func correlateFailures(tests []*testCase, events monitorapi.Intervals) []*FailureCorrelation {
+	correlations := []*FailureCorrelation{}
+	var disruptions *monitor.DisruptionIndex
+	for _, test := range tests {
+		if !test.failed && !test.flake {
+			continue
+		}
+		if test.start.IsZero() || test.end.IsZero() {
+			continue
+		}
+		if disruptions == nil {
+			disruptions = monitor.NewDisruptionIndex(events)
+		}
+		correlation := &FailureCorrelation{
+			Name:        test.name,
+			ID:          TestID(test.name),
+			Attempt:     test.attempt(),
+			Start:       test.start,
+			End:         test.end,
+			Disruptions: []ConcurrentDisruption{},
+		}
+		for _, interval := range disruptions.Overlapping(test.start, test.end) {
+			correlation.Disruptions = append(correlation.Disruptions, ConcurrentDisruption{
+				Kind:    monitor.DisruptionKind(interval),
+				Level:   interval.Level.String(),
+				Locator: interval.Locator,
+				Message: interval.Message,
+				From:    interval.From,
+				To:      interval.To,
+			})
+		}
+		test.disruptions = correlation.Disruptions
+		correlations = append(correlations, correlation)
+	}
+	return correlations
+}
+
+// concurrentDisruptionSummary describes the disruptions that overlapped a test, to be
+// appended to its failure output.
+func concurrentDisruptionSummary(test *testCase) string {
+	if len(test.disruptions) == 0 {
+		return ""
+	}
+	lines := make([]string, 0, len(test.disruptions))
+	for _, disruption := range test.disruptions {
+		lines = append(lines, disruption.String())
+	}
+	return fmt.Sprintf("\n\nThe cluster was disrupted while this test ran:\n\n%s", strings.Join(lines, "\n"))
+}
+
+// writeFailureCorrelation writes the disruptions that overlapped each failed test to
+// failure-correlation.json in dir.
+func writeFailureCorrelation(dir string, correlations []*FailureCorrelation) error {
+	data, err := json.MarshalIndent(correlations, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(dir, "failure-correlation.json"), data, 0644)
+}
@@ -0,0 +1,36 @@
+package ginkgo
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func TestCorrelateFailures(t *testing.T) {
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	failed := &testCase{name: "failed", failed: true, start: start, end: start.Add(time.Minute)}
+	passed := &testCase{name: "passed", success: true, start: start, end: start.Add(time.Minute)}
+	locator := monitor.LocatorKubeAPIServerNewConnection
+	events := monitorapi.Intervals{
+		{
+			Condition: monitorapi.Condition{Level: monitorapi.Error, Locator: locator, Message: monitor.DisruptionContinuingMessage(locator, monitor.NewConnectionType, nil)},
+			From:      start.Add(10 * time.Second),
+			To:        start.Add(22 * time.Second),
+		},
+	}
+
+	correlations := correlateFailures([]*testCase{failed, passed}, events)
+	if len(correlations) != 1 || correlations[0].Name != "failed" || len(correlations[0].Disruptions) != 1 {
+		t.Fatalf("expected one disruption of the failed test, got %#v", correlations)
+	}
+	summary := concurrentDisruptionSummary(failed)
+	if !strings.Contains(summary, "BackendDisruption for 12s: "+locator) {
+		t.Errorf("unexpected summary: %s", summary)
+	}
+	if len(concurrentDisruptionSummary(passed)) > 0 {
+		t.Errorf("expected no summary for the passing test")
+	}
+}
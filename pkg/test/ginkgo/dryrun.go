@@ -0,0 +1,115 @@
+package ginkgo
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+
+	"sigs.k8s.io/yaml"
+)
+
+// DryRunTest describes a test the suite would run, for tools that consume the output of
+// --dry-run --output.
+type DryRunTest struct {
+	Name string `json:"name"`
+	ID   string `json:"id"`
+	// Tags are the stable tags in the name of the test, such as [sig-network] or [Serial].
+	Tags []string `json:"tags"`
+	// Location is the file and line the test is defined on.
+	Location string `json:"location,omitempty"`
+	// TestExclusion is set when the test may not run in parallel with other tests in the same
+	// group, and names the group.
+	TestExclusion string `json:"testExclusion,omitempty"`
+	// Suites are the suites that would select the test.
+	Suites []string `json:"suites"`
+}
+
+// dryRunTagPattern matches the tags in a test name that tools may select tests by.
+var dryRunTagPattern = regexp.MustCompile(`\[(?:sig-[^\]]+|Serial(?::[^\]]*)?|Slow|Early|Late|Feature:[^\]]+|Suite:[^\]]+|Disabled:[^\]]+)\]`)
+
+// dryRunTags returns the stable tags in the test name in the order they appear.
+func dryRunTags(name string) []string {
+	tags := []string{}
+	seen := make(map[string]bool)
+	for _, tag := range dryRunTagPattern.FindAllString(name, -1) {
+		if seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+// suiteMatcher records whether a suite selects a test before the suite is narrowed by the
+// options of the current run.
+type suiteMatcher struct {
+	name    string
+	matches func(name string) bool
+}
+
+func newSuiteMatchers(suites []*TestSuite) []suiteMatcher {
+	matchers := make([]suiteMatcher, 0, len(suites))
+	for _, suite := range suites {
+		if suite.Matches == nil {
+			continue
+		}
+		matchers = append(matchers, suiteMatcher{name: suite.Name, matches: suite.Matches})
+	}
+	return matchers
+}
+
+// newDryRunTests describes each test and the suites that would select it.
+func newDryRunTests(tests []*testCase, matchers []suiteMatcher) []DryRunTest {
+	described := make([]DryRunTest, 0, len(tests))
+	for _, test := range tests {
+		t := DryRunTest{
+			Name:          test.name,
+			ID:            TestID(test.name),
+			Tags:          dryRunTags(test.name),
+			TestExclusion: test.testExclusion,
+			Suites:        []string{},
+		}
+		if len(test.location.FileName) > 0 {
+			t.Location = test.location.String()
+		}
+		for _, matcher := range matchers {
+			if matcher.matches(test.name) {
+				t.Suites = append(t.Suites, matcher.name)
+			}
+		}
+		described = append(described, t)
+	}
+	return described
+}
+
+// validateDryRunOutput returns an error if the format is not supported by writeDryRun.
+func validateDryRunOutput(format string) error {
+	switch format {
+	case "json", "yaml":
+		return nil
+	default:
+		return fmt.Errorf("--output must be one of json or yaml")
+	}
+}
+
+// writeDryRun writes the description of each test to out in the given format.
+func writeDryRun(out io.Writer, format string, tests []DryRunTest) error {
+	var data []byte
+	var err error
+	switch format {
+	case "json":
+		data, err = json.MarshalIndent(tests, "", "  ")
+		data = append(data, '\n')
+	case "yaml":
+		data, err = yaml.Marshal(tests)
+	default:
+		return validateDryRunOutput(format)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(data)
+	return err
+}
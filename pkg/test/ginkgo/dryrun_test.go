@@ -0,0 +1,81 @@
+package ginkgo
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/onsi/ginkgo/types"
+)
+
+func TestDryRunTags(t *testing.T) {
+	name := "[sig-network][Feature:Router] routes [Serial] [Slow] should work [Conformance] [Disabled:Broken] [Suite:openshift/conformance/serial] [sig-network]"
+	expected := []string{"[sig-network]", "[Feature:Router]", "[Serial]", "[Slow]", "[Disabled:Broken]", "[Suite:openshift/conformance/serial]"}
+	if tags := dryRunTags(name); !reflect.DeepEqual(tags, expected) {
+		t.Fatalf("unexpected tags: %v", tags)
+	}
+	if tags := dryRunTags("untagged"); len(tags) != 0 {
+		t.Fatalf("unexpected tags: %v", tags)
+	}
+}
+
+func TestWriteDryRun(t *testing.T) {
+	tests := []*testCase{
+		{
+			name:          "[sig-apps] disruption [Early] [Suite:openshift/conformance/parallel]",
+			location:      types.CodeLocation{FileName: "test/e2e/apps/disruption.go", LineNumber: 42},
+			testExclusion: "test/e2e/apps/disruption.go",
+		},
+		{name: "[sig-cli] oc [Serial] [Suite:openshift/conformance/serial]"},
+	}
+	suites := []*TestSuite{
+		{Name: "parallel", Matches: func(name string) bool { return strings.Contains(name, "/parallel]") }},
+		{Name: "all", Matches: func(name string) bool { return true }},
+		{Name: "none"},
+	}
+	matchers := newSuiteMatchers(suites)
+	// narrowing a suite after its membership was captured does not affect the output
+	suites[1].Matches = func(name string) bool { return false }
+
+	buf := &bytes.Buffer{}
+	if err := writeDryRun(buf, "json", newDryRunTests(tests, matchers)); err != nil {
+		t.Fatal(err)
+	}
+	var out []DryRunTest
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("invalid json: %v\n%s", err, buf.String())
+	}
+	expected := []DryRunTest{
+		{
+			Name:          tests[0].name,
+			ID:            TestID(tests[0].name),
+			Tags:          []string{"[sig-apps]", "[Early]", "[Suite:openshift/conformance/parallel]"},
+			Location:      "test/e2e/apps/disruption.go:42",
+			TestExclusion: "test/e2e/apps/disruption.go",
+			Suites:        []string{"parallel", "all"},
+		},
+		{
+			Name:   tests[1].name,
+			ID:     TestID(tests[1].name),
+			Tags:   []string{"[sig-cli]", "[Serial]", "[Suite:openshift/conformance/serial]"},
+			Suites: []string{"all"},
+		},
+	}
+	if !reflect.DeepEqual(out, expected) {
+		t.Fatalf("unexpected output:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := writeDryRun(buf, "yaml", newDryRunTests(tests[1:], matchers)); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "- id: "+TestID(tests[1].name)) || !strings.Contains(buf.String(), "  - all\n") {
+		t.Fatalf("unexpected yaml:\n%s", buf.String())
+	}
+
+	if err := writeDryRun(buf, "xml", nil); err == nil {
+		t.Fatal("expected an error for an unknown format")
+	}
+}
@@ -0,0 +1,158 @@
+package ginkgo
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"syscall"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+// TestImpact records the resources consumed by the process running a test and the effect
+// the test had on the cluster while it ran.
+type TestImpact struct {
+	Name    string    `json:"name"`
+	ID      string    `json:"id"`
+	Attempt int       `json:"attempt"`
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+
+	// CPUSeconds is the user and system CPU time of the run-test process.
+	CPUSeconds float64 `json:"cpuSeconds"`
+	// MaxRSSKilobytes is the peak resident set size of the run-test process.
+	MaxRSSKilobytes int64 `json:"maxRSSKilobytes"`
+	// Signal is set if the run-test process was terminated by a signal.
+	Signal string `json:"signal,omitempty"`
+
+	// Namespaces are the namespaces the test created, according to its output.
+	Namespaces []string `json:"namespaces,omitempty"`
+	// PodsCreated, ContainerRestarts and ErrorEvents count the intervals recorded by the
+	// monitor in the namespaces of the test while it ran.
+	PodsCreated       int `json:"podsCreated"`
+	ContainerRestarts int `json:"containerRestarts"`
+	ErrorEvents       int `json:"errorEvents"`
+}
+
+// testNamespacePattern matches the lines the e2e frameworks log when they create or
+// destroy a test namespace.
+var testNamespacePattern = regexp.MustCompile(`(?:Creating (?:namespace|project)|Destroying namespace) "([^"]+)"`)
+
+// recordProcessUsage sets the impact of the test from the state of its exited process.
+func recordProcessUsage(test *testCase, state *os.ProcessState) {
+	if state == nil {
+		return
+	}
+	impact := &TestImpact{
+		CPUSeconds: (state.UserTime() + state.SystemTime()).Seconds(),
+	}
+	if usage, ok := state.SysUsage().(*syscall.Rusage); ok {
+		impact.MaxRSSKilobytes = int64(usage.Maxrss)
+	}
+	if status, ok := state.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		impact.Signal = status.Signal().String()
+	}
+	test.impact = impact
+}
+
+// attributeTestImpact completes the impact of each test that ran in this process with the
+// intervals recorded in the namespaces named in its output during its run, and returns them.
+func attributeTestImpact(tests []*testCase, events monitorapi.Intervals) []*TestImpact {
+	byNamespace := make(map[string]monitorapi.Intervals)
+	for _, event := range events {
+		if namespace := monitorapi.NamespaceFrom(monitorapi.LocatorParts(event.Locator)); len(namespace) > 0 {
+			byNamespace[namespace] = append(byNamespace[namespace], event)
+		}
+	}
+
+	var impacts []*TestImpact
+	for _, test := range tests {
+		impact := test.impact
+		if impact == nil {
+			continue
+		}
+		impact.Name = test.name
+		impact.ID = TestID(test.name)
+		impact.Attempt = test.attempt()
+		impact.Start = test.start
+		impact.End = test.end
+
+		namespaces := sets.NewString()
+		for _, match := range testNamespacePattern.FindAllStringSubmatch(string(test.out), -1) {
+			namespaces.Insert(match[1])
+		}
+		impact.Namespaces = namespaces.List()
+		impact.PodsCreated, impact.ContainerRestarts, impact.ErrorEvents = 0, 0, 0
+		for _, namespace := range impact.Namespaces {
+			for _, event := range byNamespace[namespace] {
+				if event.From.Before(test.start) || event.From.After(test.end) {
+					continue
+				}
+				if event.Level == monitorapi.Error {
+					impact.ErrorEvents++
+				}
+				locator, message := event.TypedLocator(), event.TypedMessage()
+				switch {
+				case locator.Type == monitorapi.LocatorTypePod && message.Reason == "Created":
+					impact.PodsCreated++
+				case locator.Type == monitorapi.LocatorTypeContainer && message.Reason == "Restarted":
+					impact.ContainerRestarts++
+				}
+			}
+		}
+		impacts = append(impacts, impact)
+	}
+	return impacts
+}
+
+// impactProperties reports the impact of a test as JUnit properties.
+func impactProperties(test *testCase) []*TestSuiteProperty {
+	impact := test.impact
+	if impact == nil {
+		return nil
+	}
+	properties := []*TestSuiteProperty{
+		{Name: "cpu-seconds", Value: fmt.Sprintf("%.2f", impact.CPUSeconds)},
+		{Name: "max-rss-kilobytes", Value: fmt.Sprintf("%d", impact.MaxRSSKilobytes)},
+	}
+	if len(impact.Signal) > 0 {
+		properties = append(properties, &TestSuiteProperty{Name: "signal", Value: impact.Signal})
+	}
+	if len(impact.Namespaces) > 0 {
+		properties = append(properties,
+			&TestSuiteProperty{Name: "pods-created", Value: fmt.Sprintf("%d", impact.PodsCreated)},
+			&TestSuiteProperty{Name: "container-restarts", Value: fmt.Sprintf("%d", impact.ContainerRestarts)},
+			&TestSuiteProperty{Name: "error-events", Value: fmt.Sprintf("%d", impact.ErrorEvents)},
+		)
+	}
+	return properties
+}
+
+// writeTestImpact writes the impact of each test to test-impact.json in dir, ordered by the
+// disruption the test caused so the noisiest tests are listed first.
+func writeTestImpact(dir string, impacts []*TestImpact) error {
+	sorted := make([]*TestImpact, len(impacts))
+	copy(sorted, impacts)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.ContainerRestarts+a.ErrorEvents != b.ContainerRestarts+b.ErrorEvents {
+			return a.ContainerRestarts+a.ErrorEvents > b.ContainerRestarts+b.ErrorEvents
+		}
+		if a.PodsCreated != b.PodsCreated {
+			return a.PodsCreated > b.PodsCreated
+		}
+		return a.CPUSeconds > b.CPUSeconds
+	})
+	data, err := json.MarshalIndent(sorted, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(dir, "test-impact.json"), data, 0644)
+}
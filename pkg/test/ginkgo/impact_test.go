@@ -0,0 +1,59 @@
+package ginkgo
+
+import (
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func TestRecordProcessUsage(t *testing.T) {
+	c := exec.Command("/bin/sh", "-c", "kill -TERM $$")
+	if err := c.Run(); err == nil {
+		t.Fatal("expected the process to be terminated")
+	}
+	test := &testCase{name: "signaled"}
+	recordProcessUsage(test, c.ProcessState)
+	if test.impact == nil || test.impact.Signal != "terminated" || test.impact.MaxRSSKilobytes == 0 {
+		t.Fatalf("unexpected impact: %#v", test.impact)
+	}
+}
+
+func TestAttributeTestImpact(t *testing.T) {
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	test := &testCase{
+		name:   "[sig-apps] should thrash",
+		start:  start,
+		end:    start.Add(time.Minute),
+		out:    []byte("Creating namespace \"e2e-test-a\"\nDestroying namespace \"e2e-test-a\" for this suite.\n"),
+		impact: &TestImpact{CPUSeconds: 1},
+	}
+	other := &testCase{name: "resumed from the journal", start: start, end: start.Add(time.Minute)}
+	at := start.Add(10 * time.Second)
+	events := monitorapi.Intervals{
+		{Condition: monitorapi.Condition{Level: monitorapi.Info, Locator: "ns/e2e-test-a pod/a node/", Message: "reason/Created"}, From: at, To: at},
+		{Condition: monitorapi.Condition{Level: monitorapi.Info, Locator: "ns/e2e-test-a pod/b node/", Message: "reason/Created"}, From: at, To: at},
+		{Condition: monitorapi.Condition{Level: monitorapi.Warning, Locator: "ns/e2e-test-a pod/a node/n container/c", Message: "reason/Restarted"}, From: at, To: at},
+		{Condition: monitorapi.Condition{Level: monitorapi.Error, Locator: "ns/e2e-test-a pod/a node/n container/c", Message: "reason/ContainerExit code/1"}, From: at, To: at},
+		// outside the run of the test
+		{Condition: monitorapi.Condition{Level: monitorapi.Error, Locator: "ns/e2e-test-a pod/c node/", Message: "reason/Created"}, From: start.Add(2 * time.Minute), To: start.Add(2 * time.Minute)},
+		// another namespace
+		{Condition: monitorapi.Condition{Level: monitorapi.Error, Locator: "ns/e2e-test-b pod/a node/", Message: "reason/Created"}, From: at, To: at},
+	}
+
+	impacts := attributeTestImpact([]*testCase{test, other}, events)
+	if len(impacts) != 1 {
+		t.Fatalf("expected only the test run by this process to have an impact, got %d", len(impacts))
+	}
+	impact := impacts[0]
+	if impact.Name != test.name || impact.Attempt != 1 || len(impact.Namespaces) != 1 || impact.Namespaces[0] != "e2e-test-a" {
+		t.Errorf("unexpected impact: %#v", impact)
+	}
+	if impact.PodsCreated != 2 || impact.ContainerRestarts != 1 || impact.ErrorEvents != 1 {
+		t.Errorf("expected 2 pods created, 1 restart and 1 error event, got %d, %d and %d", impact.PodsCreated, impact.ContainerRestarts, impact.ErrorEvents)
+	}
+	if properties := impactProperties(test); len(properties) != 5 {
+		t.Errorf("unexpected properties: %v", properties)
+	}
+}
@@ -0,0 +1,209 @@
+package ginkgo
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// journalFileName is the name of the file in the JUnit directory that records the outcome
+// of every test as it completes.
+const journalFileName = "test-journal.jsonl"
+
+// journalEntry is a single finalized test result, serialized as one JSON object per line.
+type journalEntry struct {
+	Name     string        `json:"name"`
+	Start    time.Time     `json:"start"`
+	End      time.Time     `json:"end"`
+	Duration time.Duration `json:"duration"`
+	Result   TestResult    `json:"result"`
+	Flake    bool          `json:"flake,omitempty"`
+	TimedOut bool          `json:"timedOut,omitempty"`
+	Output   string        `json:"output,omitempty"`
+}
+
+// testJournal appends finalized test results to a file so that an interrupted suite
+// can be resumed without running completed tests again.
+type testJournal struct {
+	lock sync.Mutex
+	f    *os.File
+	enc  *json.Encoder
+}
+
+// openTestJournal opens the journal in dir. Unless resume is true any existing journal
+// is truncated, since its contents belong to a different run. When resuming, a partially
+// written final entry is removed so that new entries are not appended onto it.
+func openTestJournal(dir string, resume bool) (*testJournal, error) {
+	flags := os.O_CREATE | os.O_RDWR | os.O_APPEND
+	if !resume {
+		flags |= os.O_TRUNC
+	}
+	f, err := os.OpenFile(filepath.Join(dir, journalFileName), flags, 0640)
+	if err != nil {
+		return nil, err
+	}
+	if resume {
+		if err := truncateIncompleteEntry(f); err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+	return &testJournal{f: f, enc: json.NewEncoder(f)}, nil
+}
+
+// truncateIncompleteEntry shortens the journal to the end of its last complete entry.
+func truncateIncompleteEntry(f *os.File) error {
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	_, length, err := decodeTestJournal(f)
+	if err != nil {
+		return err
+	}
+	if err := f.Truncate(length); err != nil {
+		return err
+	}
+	if length > 0 {
+		// the decoded length stops before the newline that ended the last entry
+		if _, err := f.WriteString("\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Record appends the outcome of test to the journal. Tests without an outcome or that
+// were interrupted are not recorded so they will be run again on resume.
+func (j *testJournal) Record(test *testCase) error {
+	entry := journalEntry{
+		Name:     test.name,
+		Start:    test.start,
+		End:      test.end,
+		Duration: test.duration,
+		Flake:    test.flake,
+		TimedOut: test.timedOut,
+		Output:   string(test.out),
+	}
+	switch {
+	case test.interrupted:
+		return nil
+	case test.success:
+		entry.Result = TestResultPass
+	case test.failed:
+		entry.Result = TestResultFail
+	case test.skipped:
+		entry.Result = TestResultSkip
+	default:
+		return nil
+	}
+
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	if err := j.enc.Encode(entry); err != nil {
+		return err
+	}
+	return j.f.Sync()
+}
+
+func (j *testJournal) Close() error {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	return j.f.Close()
+}
+
+// readTestJournal loads all entries recorded in the journal in dir. A missing journal
+// returns no entries, and a partially written final entry (the process was killed while
+// writing) is ignored.
+func readTestJournal(dir string) ([]journalEntry, error) {
+	f, err := os.Open(filepath.Join(dir, journalFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	entries, _, err := decodeTestJournal(f)
+	return entries, err
+}
+
+// decodeTestJournal reads the entries from r and returns them with the length in bytes
+// of the complete entries. A partially written final entry is ignored.
+func decodeTestJournal(r io.Reader) ([]journalEntry, int64, error) {
+	var entries []journalEntry
+	var length int64
+	dec := json.NewDecoder(r)
+	for {
+		var entry journalEntry
+		if err := dec.Decode(&entry); err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				break
+			}
+			return nil, 0, fmt.Errorf("unable to read entry %d of the test journal: %v", len(entries)+1, err)
+		}
+		entries = append(entries, entry)
+		length = dec.InputOffset()
+	}
+	return entries, length, nil
+}
+
+// completedTests indexes journal entries by test name, preserving the order in which
+// they completed, so that tests run more than once (--count) are each matched once.
+type completedTests map[string][]journalEntry
+
+func newCompletedTests(entries []journalEntry) completedTests {
+	completed := make(completedTests)
+	for _, entry := range entries {
+		completed[entry.Name] = append(completed[entry.Name], entry)
+	}
+	return completed
+}
+
+// earliestStart returns the start time of the first test in the journal.
+func (c completedTests) earliestStart() time.Time {
+	var earliest time.Time
+	for _, entries := range c {
+		for _, entry := range entries {
+			if earliest.IsZero() || entry.Start.Before(earliest) {
+				earliest = entry.Start
+			}
+		}
+	}
+	return earliest
+}
+
+// restore copies the recorded outcome onto each test that has a matching journal entry
+// and returns the restored tests and the tests that still need to be run.
+func (c completedTests) restore(tests []*testCase) (restored, remaining []*testCase) {
+	for _, test := range tests {
+		entries := c[test.name]
+		if len(entries) == 0 {
+			remaining = append(remaining, test)
+			continue
+		}
+		entry := entries[0]
+		c[test.name] = entries[1:]
+
+		test.start = entry.Start
+		test.end = entry.End
+		test.duration = entry.Duration
+		test.out = []byte(entry.Output)
+		test.flake = entry.Flake
+		test.timedOut = entry.TimedOut
+		switch entry.Result {
+		case TestResultPass:
+			test.success = true
+		case TestResultFail:
+			test.failed = true
+		case TestResultSkip:
+			test.skipped = true
+		}
+		restored = append(restored, test)
+	}
+	return restored, remaining
+}
@@ -0,0 +1,148 @@
+package ginkgo
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJournalResume(t *testing.T) {
+	dir, err := ioutil.TempDir("", "journal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	start := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+	journal, err := openTestJournal(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []*testCase{
+		{name: "a", start: start.Add(time.Minute), success: true, out: []byte("a passed")},
+		{name: "b", start: start, failed: true, timedOut: true},
+		{name: "a", start: start.Add(2 * time.Minute), success: true, flake: true},
+		{name: "c", start: start, skipped: true, interrupted: true},
+		{name: "d", start: start},
+	} {
+		if err := journal.Record(test); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := journal.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// simulate the process being killed while writing an entry
+	f, err := os.OpenFile(filepath.Join(dir, journalFileName), os.O_WRONLY|os.O_APPEND, 0640)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString(`{"name":"c","res`); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	entries, err := readTestJournal(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %#v", entries)
+	}
+
+	completed := newCompletedTests(entries)
+	if earliest := completed.earliestStart(); !earliest.Equal(start) {
+		t.Errorf("unexpected earliest start %s", earliest)
+	}
+
+	tests := []*testCase{{name: "a"}, {name: "b"}, {name: "a"}, {name: "a"}, {name: "c"}}
+	restored, remaining := completed.restore(tests)
+	if len(restored) != 3 || len(remaining) != 2 {
+		t.Fatalf("unexpected restored %v and remaining %v", testNames(restored), testNames(remaining))
+	}
+	if !tests[0].success || string(tests[0].out) != "a passed" || tests[0].flake {
+		t.Errorf("unexpected first run of a: %#v", tests[0])
+	}
+	if !tests[1].failed || !tests[1].timedOut {
+		t.Errorf("unexpected b: %#v", tests[1])
+	}
+	if !tests[2].success || !tests[2].flake {
+		t.Errorf("unexpected second run of a: %#v", tests[2])
+	}
+	if names := testNames(remaining); names[0] != "a" || names[1] != "c" {
+		t.Errorf("unexpected remaining tests: %v", names)
+	}
+}
+
+func TestJournalResumeTwiceAfterPartialEntry(t *testing.T) {
+	dir, err := ioutil.TempDir("", "journal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	start := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+	journal, err := openTestJournal(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := journal.Record(&testCase{name: "a", start: start, success: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := journal.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// simulate the process being killed while writing an entry
+	f, err := os.OpenFile(filepath.Join(dir, journalFileName), os.O_WRONLY|os.O_APPEND, 0640)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString(`{"name":"b","res`); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	for i, name := range []string{"b", "c"} {
+		entries, err := readTestJournal(dir)
+		if err != nil {
+			t.Fatalf("resume %d: %v", i+1, err)
+		}
+		if len(entries) != i+1 {
+			t.Fatalf("resume %d: expected %d entries, got %#v", i+1, i+1, entries)
+		}
+		journal, err := openTestJournal(dir, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := journal.Record(&testCase{name: name, start: start, failed: true}); err != nil {
+			t.Fatal(err)
+		}
+		if err := journal.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, err := readTestJournal(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name)
+	}
+	if len(names) != 3 || names[0] != "a" || names[1] != "b" || names[2] != "c" {
+		t.Errorf("unexpected entries: %v", names)
+	}
+	data, err := ioutil.ReadFile(filepath.Join(dir, journalFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n"); len(lines) != 3 {
+		t.Errorf("expected one entry per line, got:\n%s", data)
+	}
+}
@@ -87,6 +87,9 @@ type JUnitTestCase struct {
 
 	// SystemErr is output written to stderr during the execution of this test case
 	SystemErr string `xml:"system-err,omitempty"`
+
+	// Properties holds other properties of the test case, such as its quarantine
+	Properties []*TestSuiteProperty `xml:"properties>property,omitempty"`
 }
 
 // SkipMessage holds a message explaining why a test was skipped
@@ -129,7 +132,17 @@ func writeJUnitReport(filePrefix, name string, tests []*testCase, dir string, du
 		},
 	}
 	for _, test := range tests {
+		properties := append([]*TestSuiteProperty{{Name: "test-id", Value: TestID(test.name)}}, impactProperties(test)...)
 		switch {
+		case test.quarantine != nil && test.failed:
+			// a quarantined failure is recorded without a failure so it does not fail the suite
+			s.NumTests++
+			s.TestCases = append(s.TestCases, &JUnitTestCase{
+				Name:       test.name,
+				SystemOut:  string(test.out),
+				Duration:   test.duration.Seconds(),
+				Properties: append(quarantineProperties(test, TestResultFail), properties...),
+			})
 		case test.skipped:
 			s.NumTests++
 			s.NumSkipped++
@@ -140,6 +153,7 @@ func writeJUnitReport(filePrefix, name string, tests []*testCase, dir string, du
 				SkipMessage: &SkipMessage{
 					Message: lastLinesUntil(string(test.out), 100, "skip ["),
 				},
+				Properties: properties,
 			})
 		case test.failed:
 			s.NumTests++
@@ -149,8 +163,9 @@ func writeJUnitReport(filePrefix, name string, tests []*testCase, dir string, du
 				SystemOut: string(test.out),
 				Duration:  test.duration.Seconds(),
 				FailureOutput: &FailureOutput{
-					Output: lastLinesUntil(string(test.out), 100, "fail ["),
+					Output: lastLinesUntil(string(test.out), 100, "fail [") + concurrentDisruptionSummary(test),
 				},
+				Properties: properties,
 			})
 		case test.success:
 			if test.flake {
@@ -161,15 +176,25 @@ func writeJUnitReport(filePrefix, name string, tests []*testCase, dir string, du
 					SystemOut: string(test.out),
 					Duration:  test.duration.Seconds(),
 					FailureOutput: &FailureOutput{
-						Output: lastLinesUntil(string(test.out), 100, "flake:"),
+						Output: lastLinesUntil(string(test.out), 100, "flake:") + concurrentDisruptionSummary(test),
 					},
+					Properties: properties,
 				})
 			}
 			s.NumTests++
-			s.TestCases = append(s.TestCases, &JUnitTestCase{
-				Name:     test.name,
-				Duration: test.duration.Seconds(),
-			})
+			testCase := &JUnitTestCase{
+				Name:       test.name,
+				Duration:   test.duration.Seconds(),
+				Properties: properties,
+			}
+			// keep the output of a retry that passed so it can be compared to the attempts that failed
+			if test.previous != nil {
+				testCase.SystemOut = string(test.out)
+			}
+			if test.quarantine != nil {
+				testCase.Properties = append(quarantineProperties(test, TestResultPass), testCase.Properties...)
+			}
+			s.TestCases = append(s.TestCases, testCase)
 		}
 	}
 	for _, result := range additionalResults {
@@ -182,6 +207,19 @@ func writeJUnitReport(filePrefix, name string, tests []*testCase, dir string, du
 		s.NumTests++
 		s.TestCases = append(s.TestCases, result)
 	}
+	return writeJUnitSuite(filePrefix, s, dir, errOut)
+}
+
+// quarantineProperties records the outcome of a quarantined test and the bug tracking it.
+func quarantineProperties(test *testCase, result TestResult) []*TestSuiteProperty {
+	return []*TestSuiteProperty{
+		{Name: "quarantined", Value: test.quarantine.Bug},
+		{Name: "quarantined-result", Value: string(result)},
+	}
+}
+
+// writeJUnitSuite writes the suite to a timestamped file in dir with the provided prefix.
+func writeJUnitSuite(filePrefix string, s *JUnitTestSuite, dir string, errOut io.Writer) error {
 	out, err := xml.Marshal(s)
 	if err != nil {
 		return err
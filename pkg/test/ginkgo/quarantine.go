@@ -0,0 +1,144 @@
+package ginkgo
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"regexp"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+// quarantineDateFormat is the format of the expiry date of a quarantine entry.
+const quarantineDateFormat = "2006-01-02"
+
+// QuarantineList is the set of known flaky tests that run as usual but never fail the
+// suite, for example:
+//
+//	tests:
+//	- name: "[sig-network] Services should serve endpoints on same port and different protocols"
+//	  bug: https://bugzilla.redhat.com/show_bug.cgi?id=1234567
+//	  expires: "2021-09-01"
+//	- regex: '\[sig-storage\] .* should provision storage with pvc data source'
+//	  bug: https://bugzilla.redhat.com/show_bug.cgi?id=7654321
+//	  expires: "2021-10-15"
+//
+// An entry stops applying once its expiry date has passed, so that a test is not
+// quarantined forever without its bug being revisited.
+type QuarantineList struct {
+	Tests []QuarantinedTest `json:"tests"`
+}
+
+// QuarantinedTest selects tests by exact name or by regular expression.
+type QuarantinedTest struct {
+	// Name is the exact name of a quarantined test.
+	Name string `json:"name,omitempty"`
+	// Regex matches the names of quarantined tests.
+	Regex string `json:"regex,omitempty"`
+	// Bug is the URL of the bug tracking the flake.
+	Bug string `json:"bug"`
+	// Expires is the last day, in YYYY-MM-DD form, the entry applies.
+	Expires string `json:"expires"`
+
+	re      *regexp.Regexp
+	expires time.Time
+}
+
+// LoadQuarantineList reads and validates a YAML quarantine list.
+func LoadQuarantineList(path string) (*QuarantineList, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	list := &QuarantineList{}
+	if err := yaml.UnmarshalStrict(data, list); err != nil {
+		return nil, fmt.Errorf("unable to parse quarantine list from %s: %v", path, err)
+	}
+	for i := range list.Tests {
+		if err := list.Tests[i].complete(); err != nil {
+			return nil, fmt.Errorf("quarantine entry %d in %s %v", i, path, err)
+		}
+	}
+	return list, nil
+}
+
+func (q *QuarantinedTest) complete() error {
+	if (len(q.Name) == 0) == (len(q.Regex) == 0) {
+		return fmt.Errorf("must set exactly one of name or regex")
+	}
+	if len(q.Regex) > 0 {
+		re, err := regexp.Compile(q.Regex)
+		if err != nil {
+			return fmt.Errorf("has an invalid regex: %v", err)
+		}
+		q.re = re
+	}
+	if u, err := url.Parse(q.Bug); err != nil || len(q.Bug) == 0 || len(u.Host) == 0 {
+		return fmt.Errorf("must link the bug tracking the flake with a URL: %q", q.Bug)
+	}
+	expires, err := time.Parse(quarantineDateFormat, q.Expires)
+	if err != nil {
+		return fmt.Errorf("must set expires to a date of the form YYYY-MM-DD: %q", q.Expires)
+	}
+	// the entry applies until the end of the day it expires
+	q.expires = expires.AddDate(0, 0, 1)
+	return nil
+}
+
+// String identifies the entry by the name or regex it matches.
+func (q *QuarantinedTest) String() string {
+	if len(q.Name) > 0 {
+		return q.Name
+	}
+	return q.Regex
+}
+
+// Expired returns true if the entry no longer applies at the provided time.
+func (q *QuarantinedTest) Expired(now time.Time) bool {
+	return !now.Before(q.expires)
+}
+
+// Expired returns the entries that no longer apply at the provided time.
+func (l *QuarantineList) Expired(now time.Time) []*QuarantinedTest {
+	var expired []*QuarantinedTest
+	for i := range l.Tests {
+		if l.Tests[i].Expired(now) {
+			expired = append(expired, &l.Tests[i])
+		}
+	}
+	return expired
+}
+
+// Match returns the first entry that quarantines the named test at the provided time, or
+// nil.
+func (l *QuarantineList) Match(name string, now time.Time) *QuarantinedTest {
+	for i := range l.Tests {
+		q := &l.Tests[i]
+		if q.Expired(now) {
+			continue
+		}
+		if q.Name == name || (q.re != nil && q.re.MatchString(name)) {
+			return q
+		}
+	}
+	return nil
+}
+
+// VerifyQuarantineList returns an error if the list at path is invalid or has entries that
+// have expired.
+func VerifyQuarantineList(path string, now time.Time) error {
+	list, err := LoadQuarantineList(path)
+	if err != nil {
+		return err
+	}
+	expired := list.Expired(now)
+	if len(expired) == 0 {
+		return nil
+	}
+	msg := fmt.Sprintf("%d quarantine entries in %s have expired, fix the flakes or extend the entries:\n", len(expired), path)
+	for _, q := range expired {
+		msg += fmt.Sprintf("\n%s (expired %s, %s)", q, q.Expires, q.Bug)
+	}
+	return fmt.Errorf("%s", msg)
+}
@@ -0,0 +1,187 @@
+package ginkgo
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadQuarantineList(t *testing.T) {
+	dir, err := ioutil.TempDir("", "quarantine")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		name    string
+		list    string
+		wantErr string
+	}{
+		{
+			name: "name and regex",
+			list: `
+tests:
+- name: "[sig-network] should work"
+  bug: https://bugzilla.redhat.com/show_bug.cgi?id=1
+  expires: "2021-09-01"
+- regex: '\[sig-storage\] .* should mount'
+  bug: https://bugzilla.redhat.com/show_bug.cgi?id=2
+  expires: 2021-09-01
+`,
+		},
+		{
+			name: "name and regex in one entry",
+			list: `
+tests:
+- name: a
+  regex: b
+  bug: https://bugzilla.redhat.com/show_bug.cgi?id=1
+  expires: "2021-09-01"
+`,
+			wantErr: "must set exactly one of name or regex",
+		},
+		{
+			name: "missing bug",
+			list: `
+tests:
+- name: a
+  expires: "2021-09-01"
+`,
+			wantErr: "must link the bug",
+		},
+		{
+			name: "missing expiry",
+			list: `
+tests:
+- name: a
+  bug: https://bugzilla.redhat.com/show_bug.cgi?id=1
+`,
+			wantErr: "must set expires",
+		},
+		{
+			name: "invalid regex",
+			list: `
+tests:
+- regex: "["
+  bug: https://bugzilla.redhat.com/show_bug.cgi?id=1
+  expires: "2021-09-01"
+`,
+			wantErr: "invalid regex",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, "quarantine.yaml")
+			if err := ioutil.WriteFile(path, []byte(tt.list), 0644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := LoadQuarantineList(path)
+			if len(tt.wantErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestQuarantineListMatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "quarantine")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "quarantine.yaml")
+	if err := ioutil.WriteFile(path, []byte(`
+tests:
+- name: "[sig-network] should work"
+  bug: https://bugzilla.redhat.com/show_bug.cgi?id=1
+  expires: "2021-09-01"
+- regex: '\[sig-storage\] .* should mount'
+  bug: https://bugzilla.redhat.com/show_bug.cgi?id=2
+  expires: "2021-10-01"
+`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	list, err := LoadQuarantineList(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lastDay := time.Date(2021, 9, 1, 23, 0, 0, 0, time.UTC)
+	if q := list.Match("[sig-network] should work", lastDay); q == nil || q.Bug != "https://bugzilla.redhat.com/show_bug.cgi?id=1" {
+		t.Errorf("expected the test to be quarantined on its last day, got %v", q)
+	}
+	if q := list.Match("[sig-storage] volumes should mount", lastDay); q == nil || q.Regex == "" {
+		t.Errorf("expected the regex to quarantine the test, got %v", q)
+	}
+	if q := list.Match("[sig-network] should work too", lastDay); q != nil {
+		t.Errorf("expected only the exact name to be quarantined, got %v", q)
+	}
+	if q := list.Match("[sig-network] should work", lastDay.Add(time.Hour)); q != nil {
+		t.Errorf("expected the expired entry not to apply, got %v", q)
+	}
+
+	if err := VerifyQuarantineList(path, lastDay); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := VerifyQuarantineList(path, lastDay.Add(time.Hour)); err == nil || !strings.Contains(err.Error(), "1 quarantine entries") {
+		t.Errorf("expected an expired entry, got %v", err)
+	}
+}
+
+func TestQuarantineFile(t *testing.T) {
+	path := filepath.Join("..", "..", "..", "test", "extended", "util", "annotate", "quarantine.yaml")
+	list, err := LoadQuarantineList(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, q := range list.Expired(time.Now()) {
+		t.Errorf("quarantine entry %s expired %s, fix the flake (%s) or extend the entry in %s", q, q.Expires, q.Bug, path)
+	}
+}
+
+func TestQuarantinedJUnit(t *testing.T) {
+	dir, err := ioutil.TempDir("", "quarantine")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	q := &QuarantinedTest{Name: "flaky", Bug: "https://bugzilla.redhat.com/show_bug.cgi?id=1"}
+	tests := []*testCase{
+		{name: "flaky", failed: true, out: []byte("fail [flaky]"), quarantine: q},
+		{name: "other", failed: true, out: []byte("fail [other]")},
+	}
+	if err := writeJUnitReport("junit_e2e", "openshift-tests", tests, dir, time.Minute, ioutil.Discard); err != nil {
+		t.Fatal(err)
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "junit_e2e_*.xml"))
+	if err != nil || len(files) != 1 {
+		t.Fatalf("expected one report, got %v: %v", files, err)
+	}
+	data, err := ioutil.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	suite := &JUnitTestSuite{}
+	if err := xml.Unmarshal(data, suite); err != nil {
+		t.Fatal(err)
+	}
+	if suite.NumTests != 2 || suite.NumFailed != 1 {
+		t.Fatalf("expected only the unquarantined test to fail, got %d of %d", suite.NumFailed, suite.NumTests)
+	}
+	flaky := suite.TestCases[0]
+	if flaky.FailureOutput != nil || len(flaky.Properties) != 3 || flaky.Properties[0].Value != q.Bug || flaky.Properties[1].Value != string(TestResultFail) {
+		t.Errorf("unexpected quarantined test case: %#v", flaky)
+	}
+}
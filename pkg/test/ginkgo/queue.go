@@ -10,12 +10,14 @@ import (
 // parallelByFileTestQueue runs tests in parallel unless they have
 // the `[Serial]` tag on their name or if another test with the
 // testExclusion field is currently running. Serial tests are
-// defered until all other tests are completed.
+// defered until all other tests are completed. If timings are
+// provided the longest parallel tests are started first.
 type parallelByFileTestQueue struct {
-	cond   *sync.Cond
-	lock   sync.Mutex
-	queue  *ring.Ring
-	active map[string]struct{}
+	cond    *sync.Cond
+	lock    sync.Mutex
+	queue   *ring.Ring
+	active  map[string]struct{}
+	timings *TestTimings
 }
 
 type nopLock struct{}
@@ -25,10 +27,11 @@ func (nopLock) Unlock() {}
 
 type TestFunc func(ctx context.Context, test *testCase)
 
-func newParallelTestQueue() *parallelByFileTestQueue {
+func newParallelTestQueue(timings *TestTimings) *parallelByFileTestQueue {
 	return &parallelByFileTestQueue{
-		cond:   sync.NewCond(nopLock{}),
-		active: make(map[string]struct{}),
+		cond:    sync.NewCond(nopLock{}),
+		active:  make(map[string]struct{}),
+		timings: timings,
 	}
 }
 
@@ -53,8 +56,11 @@ func (q *parallelByFileTestQueue) pop() (*testCase, bool) {
 		if l == 1 {
 			q.queue = nil
 		} else {
-			q.queue = r.Prev()
-			q.queue.Unlink(1)
+			// keep taking tests in the order they were queued, longest first
+			if r == q.queue {
+				q.queue = r.Next()
+			}
+			r.Prev().Unlink(1)
 		}
 		return t, true
 	}
@@ -100,6 +106,7 @@ func (q *parallelByFileTestQueue) Execute(ctx context.Context, tests []*testCase
 	}
 
 	serial, parallel := splitTests(tests, func(t *testCase) bool { return strings.Contains(t.name, "[Serial]") })
+	q.timings.sortLongestFirst(parallel)
 
 	r := ring.New(len(parallel))
 	for _, test := range parallel {
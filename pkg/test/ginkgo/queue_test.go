@@ -0,0 +1,33 @@
+package ginkgo
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestParallelTestQueueExecuteLongestFirst(t *testing.T) {
+	timings := &TestTimings{Tests: map[string]float64{
+		"1s":   1,
+		"10s":  10,
+		"30s":  30,
+		"300s": 300,
+		"600s": 600,
+	}}
+	tests := []*testCase{
+		{name: "30s"},
+		{name: "1s"},
+		{name: "600s"},
+		{name: "a [Serial]"},
+		{name: "300s"},
+		{name: "10s"},
+	}
+	var started []string
+	newParallelTestQueue(timings).Execute(context.Background(), tests, 1, func(ctx context.Context, test *testCase) {
+		started = append(started, test.name)
+	})
+	expected := []string{"600s", "300s", "30s", "10s", "1s", "a [Serial]"}
+	if !reflect.DeepEqual(expected, started) {
+		t.Errorf("unexpected order: %v", started)
+	}
+}
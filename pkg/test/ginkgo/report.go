@@ -0,0 +1,397 @@
+package ginkgo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// testReporter writes the outcome of a suite in one format. TestStarted and TestFinished are
+// invoked as each test runs and SuiteFinished once when the suite completes.
+type testReporter interface {
+	TestStarted(test *testCase)
+	TestFinished(test *testCase)
+	SuiteFinished(report *suiteReport) error
+}
+
+// suiteReport is the outcome of a suite, including retries and the synthetic tests derived
+// from the monitor.
+type suiteReport struct {
+	name             string
+	duration         time.Duration
+	tests            []*testCase
+	syntheticResults []*JUnitTestCase
+}
+
+// reportFormat describes a reporter that may be selected with --report-format.
+type reportFormat struct {
+	// file is the name of the report in the JUnit directory if no path is provided.
+	file string
+	new  func(path string, errOut io.Writer) (testReporter, error)
+}
+
+// reportFormats are the formats that may be selected with --report-format.
+var reportFormats = map[string]reportFormat{
+	"jsonl": {
+		file: "e2e-tests%s.jsonl",
+		new: func(path string, _ io.Writer) (testReporter, error) {
+			return newJSONLinesReporter(path)
+		},
+	},
+	"tap": {
+		file: "e2e-tests%s.tap",
+		new: func(path string, _ io.Writer) (testReporter, error) {
+			return &tapReporter{path: path}, nil
+		},
+	},
+	"markdown": {
+		file: "e2e-summary%s.md",
+		new: func(path string, _ io.Writer) (testReporter, error) {
+			return &markdownReporter{path: path}, nil
+		},
+	},
+}
+
+// newTestReporters returns the reporters for a run. The JUnit report is always written to
+// junitDir, if set. Each format is FORMAT or FORMAT=PATH, and is written to a file in
+// junitDir named with the shard suffix if no path is provided.
+func newTestReporters(formats []string, junitDir, junitPrefix, shardSuffix string, errOut io.Writer) ([]testReporter, error) {
+	var reporters []testReporter
+	if len(junitDir) > 0 {
+		reporters = append(reporters, &junitReporter{dir: junitDir, filePrefix: junitPrefix, errOut: errOut})
+	}
+	seen := sets.NewString()
+	for _, spec := range formats {
+		parts := strings.SplitN(spec, "=", 2)
+		name := parts[0]
+		if seen.Has(name) {
+			return nil, fmt.Errorf("--report-format %s may only be specified once", name)
+		}
+		seen.Insert(name)
+		if name == "junit" {
+			if len(parts) == 2 {
+				reporters = append(reporters, &junitReporter{dir: parts[1], filePrefix: junitPrefix, errOut: errOut})
+			} else if len(junitDir) == 0 {
+				return nil, fmt.Errorf("--report-format junit requires a directory or --junit-dir")
+			}
+			continue
+		}
+		format, ok := reportFormats[name]
+		if !ok {
+			return nil, fmt.Errorf("unrecognized --report-format %q, must be one of junit, %s", name, strings.Join(sets.StringKeySet(reportFormats).List(), ", "))
+		}
+		var path string
+		switch {
+		case len(parts) == 2:
+			path = parts[1]
+		case len(junitDir) > 0:
+			path = filepath.Join(junitDir, fmt.Sprintf(format.file, shardSuffix))
+		default:
+			return nil, fmt.Errorf("--report-format %s requires a path or --junit-dir", name)
+		}
+		reporter, err := format.new(path, errOut)
+		if err != nil {
+			return nil, fmt.Errorf("could not create the %s report: %v", name, err)
+		}
+		reporters = append(reporters, reporter)
+	}
+	return reporters, nil
+}
+
+// testResultOf returns the outcome of a test, or an empty string if it did not complete.
+func testResultOf(test *testCase) string {
+	switch {
+	case test.interrupted:
+		return ""
+	case test.flake:
+		return "flake"
+	case test.success:
+		return string(TestResultPass)
+	case test.failed:
+		return string(TestResultFail)
+	case test.skipped:
+		return string(TestResultSkip)
+	}
+	return ""
+}
+
+// junitReporter writes the JUnit XML report consumed by CI.
+type junitReporter struct {
+	dir        string
+	filePrefix string
+	errOut     io.Writer
+}
+
+func (r *junitReporter) TestStarted(*testCase)  {}
+func (r *junitReporter) TestFinished(*testCase) {}
+
+func (r *junitReporter) SuiteFinished(report *suiteReport) error {
+	return writeJUnitReport(r.filePrefix, "openshift-tests", report.tests, r.dir, report.duration, r.errOut, report.syntheticResults...)
+}
+
+// jsonLinesEvent is a single line of the JSON lines report.
+type jsonLinesEvent struct {
+	Event           string    `json:"event"`
+	Time            time.Time `json:"time"`
+	Name            string    `json:"name,omitempty"`
+	ID              string    `json:"id,omitempty"`
+	Attempt         int       `json:"attempt,omitempty"`
+	Result          string    `json:"result,omitempty"`
+	DurationSeconds float64   `json:"durationSeconds,omitempty"`
+	TimedOut        bool      `json:"timedOut,omitempty"`
+	Quarantined     bool      `json:"quarantined,omitempty"`
+
+	Pass int `json:"pass,omitempty"`
+	Fail int `json:"fail,omitempty"`
+	Skip int `json:"skip,omitempty"`
+}
+
+// jsonLinesReporter streams a JSON object for every test that starts or finishes, and one
+// when the suite finishes, so that dashboards can follow a run live.
+type jsonLinesReporter struct {
+	lock sync.Mutex
+	f    *os.File
+	enc  *json.Encoder
+}
+
+func newJSONLinesReporter(path string) (*jsonLinesReporter, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &jsonLinesReporter{f: f, enc: json.NewEncoder(f)}, nil
+}
+
+func (r *jsonLinesReporter) write(event jsonLinesEvent) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.f == nil {
+		return nil
+	}
+	return r.enc.Encode(event)
+}
+
+func (r *jsonLinesReporter) TestStarted(test *testCase) {
+	r.write(jsonLinesEvent{Event: "started", Time: time.Now().UTC(), Name: test.name, ID: TestID(test.name), Attempt: test.attempt()})
+}
+
+func (r *jsonLinesReporter) TestFinished(test *testCase) {
+	result := testResultOf(test)
+	if len(result) == 0 {
+		return
+	}
+	r.write(jsonLinesEvent{
+		Event:           "finished",
+		Time:            test.end.UTC(),
+		Name:            test.name,
+		ID:              TestID(test.name),
+		Attempt:         test.attempt(),
+		Result:          result,
+		DurationSeconds: test.duration.Seconds(),
+		TimedOut:        test.timedOut,
+		Quarantined:     test.quarantine != nil,
+	})
+}
+
+func (r *jsonLinesReporter) SuiteFinished(report *suiteReport) error {
+	summary := summarizeReport(report)
+	err := r.write(jsonLinesEvent{
+		Event:           "suiteFinished",
+		Time:            time.Now().UTC(),
+		Name:            report.name,
+		DurationSeconds: report.duration.Seconds(),
+		Pass:            summary.passed.Len(),
+		Fail:            summary.failed.Len(),
+		Skip:            summary.skipped.Len(),
+	})
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.f == nil {
+		return err
+	}
+	if closeErr := r.f.Close(); err == nil {
+		err = closeErr
+	}
+	r.f = nil
+	return err
+}
+
+// tapReporter writes a Test Anything Protocol report with a test point for every attempt.
+// Quarantined failures are marked TODO so that they do not fail the report.
+type tapReporter struct {
+	path string
+}
+
+func (r *tapReporter) TestStarted(*testCase)  {}
+func (r *tapReporter) TestFinished(*testCase) {}
+
+func (r *tapReporter) SuiteFinished(report *suiteReport) error {
+	var tests []*testCase
+	for _, test := range report.tests {
+		if len(testResultOf(test)) > 0 {
+			tests = append(tests, test)
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "TAP version 13\n1..%d\n", len(tests))
+	for i, test := range tests {
+		description := strings.NewReplacer("#", `\#`, "\n", " ").Replace(test.name)
+		switch {
+		case test.skipped:
+			fmt.Fprintf(buf, "ok %d - %s # SKIP\n", i+1, description)
+		case test.failed && test.quarantine != nil:
+			fmt.Fprintf(buf, "not ok %d - %s # TODO quarantined %s\n", i+1, description, test.quarantine.Bug)
+		case test.failed:
+			fmt.Fprintf(buf, "not ok %d - %s\n", i+1, description)
+			fmt.Fprintf(buf, "  ---\n  duration_ms: %d\n  timed_out: %t\n  message: |\n", test.duration.Milliseconds(), test.timedOut)
+			for _, line := range strings.Split(lastLinesUntil(string(test.out), 20, "fail ["), "\n") {
+				fmt.Fprintf(buf, "    %s\n", line)
+			}
+			fmt.Fprintf(buf, "  ...\n")
+		case test.flake:
+			fmt.Fprintf(buf, "ok %d - %s # flaked\n", i+1, description)
+		default:
+			fmt.Fprintf(buf, "ok %d - %s\n", i+1, description)
+		}
+	}
+	return ioutil.WriteFile(r.path, buf.Bytes(), 0644)
+}
+
+// markdownReporter writes a summary of the suite suitable for posting to a pull request.
+type markdownReporter struct {
+	path string
+}
+
+func (r *markdownReporter) TestStarted(*testCase)  {}
+func (r *markdownReporter) TestFinished(*testCase) {}
+
+// markdownSlowestTests is the number of tests listed in the slowest tests section.
+const markdownSlowestTests = 10
+
+func (r *markdownReporter) SuiteFinished(report *suiteReport) error {
+	summary := summarizeReport(report)
+
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "## %s\n\n", report.name)
+	fmt.Fprintf(buf, "| Passed | Failed | Flaky | Skipped | Quarantined failures | Duration |\n")
+	fmt.Fprintf(buf, "|---|---|---|---|---|---|\n")
+	fmt.Fprintf(buf, "| %d | %d | %d | %d | %d | %s |\n\n", summary.passed.Len(), summary.failed.Len(), summary.flaky.Len(), summary.skipped.Len(), summary.quarantined.Len(), report.duration)
+
+	if summary.failed.Len() > 0 {
+		fmt.Fprintf(buf, "### Failed tests\n\n")
+		for _, name := range summary.failed.List() {
+			test := summary.lastFailure[name]
+			fmt.Fprintf(buf, "<details><summary><code>%s</code></summary>\n\n```\n%s\n```\n</details>\n\n", markdownEscaper.Replace(name), lastLinesUntil(string(test.out), 20, "fail ["))
+		}
+	}
+	if failing := summary.failingInvariants(); len(failing) > 0 {
+		fmt.Fprintf(buf, "### Failing invariants\n\n")
+		for _, name := range failing {
+			fmt.Fprintf(buf, "- `%s`\n", name)
+		}
+		fmt.Fprintln(buf)
+	}
+	if summary.flaky.Len() > 0 {
+		fmt.Fprintf(buf, "### Flaky tests\n\n")
+		for _, name := range summary.flaky.List() {
+			fmt.Fprintf(buf, "- `%s`\n", name)
+		}
+		fmt.Fprintln(buf)
+	}
+	if summary.quarantined.Len() > 0 {
+		fmt.Fprintf(buf, "### Quarantined failures\n\n")
+		for _, name := range summary.quarantined.List() {
+			fmt.Fprintf(buf, "- `%s` (%s)\n", name, summary.lastFailure[name].quarantine.Bug)
+		}
+		fmt.Fprintln(buf)
+	}
+
+	slowest := sortedTests(report.tests)
+	sort.SliceStable(slowest, func(i, j int) bool { return slowest[i].duration > slowest[j].duration })
+	if len(slowest) > markdownSlowestTests {
+		slowest = slowest[:markdownSlowestTests]
+	}
+	if len(slowest) > 0 {
+		fmt.Fprintf(buf, "### Slowest tests\n\n| Test | Duration | Result |\n|---|---|---|\n")
+		for _, test := range slowest {
+			fmt.Fprintf(buf, "| `%s` | %s | %s |\n", strings.ReplaceAll(test.name, "|", `\|`), test.duration, testResultOf(test))
+		}
+	}
+	return ioutil.WriteFile(r.path, buf.Bytes(), 0644)
+}
+
+// markdownEscaper escapes test names rendered inside HTML in the markdown summary.
+var markdownEscaper = strings.NewReplacer("<", "&lt;", ">", "&gt;", "&", "&amp;")
+
+// reportSummary groups the tests of a suite by their final outcome. A test that failed and
+// then passed on retry is flaky, and a failed quarantined test is only listed as quarantined.
+type reportSummary struct {
+	passed, failed, flaky, skipped, quarantined sets.String
+	lastFailure                                 map[string]*testCase
+	syntheticResults                            []*JUnitTestCase
+}
+
+func summarizeReport(report *suiteReport) *reportSummary {
+	summary := &reportSummary{
+		passed:           sets.NewString(),
+		failed:           sets.NewString(),
+		flaky:            sets.NewString(),
+		skipped:          sets.NewString(),
+		quarantined:      sets.NewString(),
+		lastFailure:      make(map[string]*testCase),
+		syntheticResults: report.syntheticResults,
+	}
+	succeeded, failed := sets.NewString(), sets.NewString()
+	for _, test := range report.tests {
+		switch {
+		case test.interrupted:
+		case test.failed:
+			failed.Insert(test.name)
+			summary.lastFailure[test.name] = test
+		case test.success:
+			succeeded.Insert(test.name)
+			if test.flake {
+				summary.flaky.Insert(test.name)
+			}
+		case test.skipped:
+			summary.skipped.Insert(test.name)
+		}
+	}
+	for _, name := range failed.List() {
+		switch {
+		case summary.lastFailure[name].quarantine != nil:
+			summary.quarantined.Insert(name)
+		case succeeded.Has(name):
+			summary.flaky.Insert(name)
+		default:
+			summary.failed.Insert(name)
+		}
+	}
+	summary.passed = succeeded.Difference(summary.flaky).Difference(summary.quarantined)
+	return summary
+}
+
+// failingInvariants returns the synthetic tests that failed without also passing.
+func (s *reportSummary) failingInvariants() []string {
+	failing, passing := sets.NewString(), sets.NewString()
+	for _, result := range s.syntheticResults {
+		if result.FailureOutput != nil {
+			failing.Insert(result.Name)
+		} else if result.SkipMessage == nil {
+			passing.Insert(result.Name)
+		}
+	}
+	return failing.Difference(passing).List()
+}
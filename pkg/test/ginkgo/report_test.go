@@ -0,0 +1,117 @@
+package ginkgo
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor"
+)
+
+func TestTestReporters(t *testing.T) {
+	dir, err := ioutil.TempDir("", "report")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if _, err := newTestReporters([]string{"html"}, dir, "junit_e2e", "", ioutil.Discard); err == nil || !strings.Contains(err.Error(), "unrecognized --report-format") {
+		t.Errorf("expected an unrecognized format, got %v", err)
+	}
+	if _, err := newTestReporters([]string{"tap"}, "", "junit_e2e", "", ioutil.Discard); err == nil || !strings.Contains(err.Error(), "requires a path or --junit-dir") {
+		t.Errorf("expected a path to be required, got %v", err)
+	}
+
+	summaryPath := filepath.Join(dir, "summary.md")
+	reporters, err := newTestReporters([]string{"jsonl", "tap", "markdown=" + summaryPath}, dir, "junit_e2e", "_shard-1-of-2", ioutil.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reporters) != 4 {
+		t.Fatalf("expected the JUnit report and three others, got %d", len(reporters))
+	}
+
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	failed := &testCase{name: "fails # always", failed: true, out: []byte("output\nfail [test.go:1]: it broke"), duration: time.Minute, end: start}
+	flaked := &testCase{name: "flakes", failed: true, out: []byte("fail [test.go:2]: once"), duration: 2 * time.Second, end: start}
+	retried := flaked.Retry()
+	retried.success = true
+	tests := []*testCase{
+		failed,
+		{name: "passes", success: true, duration: time.Second, end: start},
+		{name: "skips", skipped: true, end: start},
+		flaked,
+		retried,
+	}
+	status := &testStatus{reporters: reporters, monitorRecorder: monitor.NewNoOpMonitor(), out: ioutil.Discard}
+	for _, test := range tests {
+		status.testStarted(test)
+		status.finalizeTest(test)
+	}
+	report := &suiteReport{
+		name:     "openshift/conformance",
+		duration: time.Hour,
+		tests:    tests,
+		syntheticResults: []*JUnitTestCase{
+			{Name: "[sig-arch] invariant fails", FailureOutput: &FailureOutput{}},
+			{Name: "[sig-arch] invariant flakes", FailureOutput: &FailureOutput{}},
+			{Name: "[sig-arch] invariant flakes"},
+		},
+	}
+	for _, reporter := range reporters {
+		if err := reporter.SuiteFinished(report); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	f, err := os.Open(filepath.Join(dir, "e2e-tests_shard-1-of-2.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var events []jsonLinesEvent
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var event jsonLinesEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatal(err)
+		}
+		events = append(events, event)
+	}
+	if len(events) != 11 || events[1].Event != "finished" || events[1].Result != "fail" || events[9].Attempt != 2 {
+		t.Errorf("unexpected events: %#v", events)
+	}
+	if last := events[len(events)-1]; last.Event != "suiteFinished" || last.Pass != 1 || last.Fail != 1 || last.Skip != 1 {
+		t.Errorf("unexpected summary: %#v", last)
+	}
+
+	tap, err := ioutil.ReadFile(filepath.Join(dir, "e2e-tests_shard-1-of-2.tap"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"1..5\n", `not ok 1 - fails \# always`, "    fail [test.go:1]: it broke\n", "ok 3 - skips # SKIP\n", "ok 5 - flakes\n"} {
+		if !strings.Contains(string(tap), want) {
+			t.Errorf("expected TAP report to contain %q:\n%s", want, tap)
+		}
+	}
+
+	markdown, err := ioutil.ReadFile(summaryPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"| 1 | 1 | 1 | 1 | 0 | 1h0m0s |", "### Failed tests", "fail [test.go:1]: it broke", "### Failing invariants\n\n- `[sig-arch] invariant fails`\n\n", "### Flaky tests\n\n- `flakes`", "| `fails # always` | 1m0s | fail |"} {
+		if !strings.Contains(string(markdown), want) {
+			t.Errorf("expected markdown summary to contain %q:\n%s", want, markdown)
+		}
+	}
+
+	junit, err := filepath.Glob(filepath.Join(dir, "junit_e2e_*.xml"))
+	if err != nil || len(junit) != 1 {
+		t.Errorf("expected a JUnit report, got %v: %v", junit, err)
+	}
+}
@@ -0,0 +1,148 @@
+package ginkgo
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// RetryPolicy controls how failed tests are retried to detect flakes.
+type RetryPolicy struct {
+	// MaxAttempts is the number of times a test may run, including the first attempt.
+	MaxAttempts int
+	// MaxRetries is the number of retries allowed across the whole suite.
+	MaxRetries int
+	// RetryOnFailure retries tests that failed before their timeout.
+	RetryOnFailure bool
+	// RetryOnTimeout retries tests that were aborted by their timeout.
+	RetryOnTimeout bool
+	// Allow, if set, limits retries to the tests matching any of the expressions.
+	Allow []*regexp.Regexp
+	// Deny lists the tests that are never retried.
+	Deny []*regexp.Regexp
+}
+
+// defaultRetryPolicy retries each failure once, until the suite has used as many retries as
+// it allows flakes. A suite that allows no flakes is not retried, so any failure fails it.
+func defaultRetryPolicy(suite *TestSuite) *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:    2,
+		MaxRetries:     suite.MaximumAllowedFlakes,
+		RetryOnFailure: true,
+		RetryOnTimeout: true,
+	}
+}
+
+// ParseRetryPolicy applies a comma delimited list of key=value settings to a copy of base:
+//
+//	attempts=N     the number of times a test may run, including the first attempt
+//	budget=N       the number of retries allowed across the suite
+//	on=WHEN        retry tests that failed, timed out or either (failure, timeout or any)
+//	allow=REGEXP   only retry tests matching the expression, may be repeated
+//	deny=REGEXP    never retry tests matching the expression, may be repeated
+func ParseRetryPolicy(base *RetryPolicy, spec string) (*RetryPolicy, error) {
+	policy := &RetryPolicy{}
+	if base != nil {
+		*policy = *base
+	}
+	for _, setting := range strings.Split(spec, ",") {
+		setting = strings.TrimSpace(setting)
+		if len(setting) == 0 {
+			continue
+		}
+		parts := strings.SplitN(setting, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("retry policy setting %q must be of the form key=value", setting)
+		}
+		key, value := parts[0], parts[1]
+		switch key {
+		case "attempts", "budget":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 || (key == "attempts" && n == 0) {
+				return nil, fmt.Errorf("retry policy %s must be a positive number: %q", key, value)
+			}
+			if key == "attempts" {
+				policy.MaxAttempts = n
+			} else {
+				policy.MaxRetries = n
+			}
+		case "on":
+			switch value {
+			case "failure":
+				policy.RetryOnFailure, policy.RetryOnTimeout = true, false
+			case "timeout":
+				policy.RetryOnFailure, policy.RetryOnTimeout = false, true
+			case "any":
+				policy.RetryOnFailure, policy.RetryOnTimeout = true, true
+			default:
+				return nil, fmt.Errorf("retry policy on must be failure, timeout or any: %q", value)
+			}
+		case "allow", "deny":
+			re, err := regexp.Compile(value)
+			if err != nil {
+				return nil, fmt.Errorf("retry policy %s is not a valid regular expression: %v", key, err)
+			}
+			if key == "allow" {
+				policy.Allow = append(append([]*regexp.Regexp(nil), policy.Allow...), re)
+			} else {
+				policy.Deny = append(append([]*regexp.Regexp(nil), policy.Deny...), re)
+			}
+		default:
+			return nil, fmt.Errorf("unrecognized retry policy setting %q, must be one of attempts, budget, on, allow or deny", key)
+		}
+	}
+	return policy, nil
+}
+
+// CanRetry returns true if the policy allows the failed test to run again. The suite wide
+// budget is not considered.
+func (p *RetryPolicy) CanRetry(test *testCase) bool {
+	if test.attempt() >= p.MaxAttempts {
+		return false
+	}
+	if test.timedOut && !p.RetryOnTimeout {
+		return false
+	}
+	if !test.timedOut && !p.RetryOnFailure {
+		return false
+	}
+	for _, re := range p.Deny {
+		if re.MatchString(test.name) {
+			return false
+		}
+	}
+	if len(p.Allow) == 0 {
+		return true
+	}
+	for _, re := range p.Allow {
+		if re.MatchString(test.name) {
+			return true
+		}
+	}
+	return false
+}
+
+// selectRetries returns a retry of each candidate the policy allows to run again while budget
+// retries remain, the candidates that are not retried and the remaining budget.
+func (p *RetryPolicy) selectRetries(candidates []*testCase, budget int) ([]*testCase, []*testCase, int) {
+	var retries, notRetried []*testCase
+	for _, test := range candidates {
+		if budget == 0 || !p.CanRetry(test) {
+			notRetried = append(notRetried, test)
+			continue
+		}
+		retries = append(retries, test.Retry())
+		budget--
+	}
+	return retries, notRetried, budget
+}
+
+// attempt returns the number of times the test has run, counting this run.
+func (t *testCase) attempt() int {
+	n := 1
+	for previous := t.previous; previous != nil; previous = previous.previous {
+		n++
+	}
+	return n
+}
@@ -0,0 +1,92 @@
+package ginkgo
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestParseRetryPolicy(t *testing.T) {
+	base := defaultRetryPolicy(&TestSuite{MaximumAllowedFlakes: 15})
+	policy, err := ParseRetryPolicy(base, "attempts=3,budget=5,on=timeout,deny=\\[Serial\\],allow=sig-network")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if policy.MaxAttempts != 3 || policy.MaxRetries != 5 || policy.RetryOnFailure || !policy.RetryOnTimeout || len(policy.Deny) != 1 || len(policy.Allow) != 1 {
+		t.Errorf("unexpected policy: %#v", policy)
+	}
+	if base.MaxAttempts != 2 || base.MaxRetries != 15 || len(base.Deny) != 0 {
+		t.Errorf("base policy was modified: %#v", base)
+	}
+
+	for spec, wantErr := range map[string]string{
+		"attempts=0":  "must be a positive number",
+		"budget=-1":   "must be a positive number",
+		"on=sometime": "must be failure, timeout or any",
+		"deny=[":      "not a valid regular expression",
+		"retries=1":   "unrecognized retry policy setting",
+		"attempts":    "must be of the form key=value",
+	} {
+		if _, err := ParseRetryPolicy(base, spec); err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Errorf("%s: expected error containing %q, got %v", spec, wantErr, err)
+		}
+	}
+}
+
+func TestRetryPolicyCanRetry(t *testing.T) {
+	policy, err := ParseRetryPolicy(&RetryPolicy{MaxAttempts: 3, RetryOnFailure: true, RetryOnTimeout: true}, "allow=\\[sig-network\\],deny=\\[Serial\\]")
+	if err != nil {
+		t.Fatal(err)
+	}
+	failed := &testCase{name: "[sig-network] should work", failed: true}
+	timedOut := &testCase{name: "[sig-network] should work", failed: true, timedOut: true}
+	tests := []struct {
+		name   string
+		policy *RetryPolicy
+		test   *testCase
+		want   bool
+	}{
+		{name: "allowed", policy: policy, test: failed, want: true},
+		{name: "second attempt", policy: policy, test: failed.Retry(), want: true},
+		{name: "out of attempts", policy: policy, test: failed.Retry().Retry(), want: false},
+		{name: "denied", policy: policy, test: &testCase{name: "[sig-network] should work [Serial]", failed: true}, want: false},
+		{name: "not allowed", policy: policy, test: &testCase{name: "[sig-storage] should work", failed: true}, want: false},
+		{name: "failure not retried", policy: &RetryPolicy{MaxAttempts: 2, RetryOnTimeout: true}, test: failed, want: false},
+		{name: "timeout retried", policy: &RetryPolicy{MaxAttempts: 2, RetryOnTimeout: true}, test: timedOut, want: true},
+		{name: "timeout not retried", policy: &RetryPolicy{MaxAttempts: 2, RetryOnFailure: true}, test: timedOut, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.CanRetry(tt.test); got != tt.want {
+				t.Errorf("CanRetry() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryPolicySelectRetries(t *testing.T) {
+	policy := &RetryPolicy{MaxAttempts: 2, RetryOnFailure: true, Deny: []*regexp.Regexp{regexp.MustCompile(`\[Serial\]`)}}
+	var candidates []*testCase
+	for _, name := range []string{"a", "b [Serial]", "c", "d", "e"} {
+		candidates = append(candidates, &testCase{name: name, failed: true})
+	}
+
+	// more tests failed than the budget covers, so the budget is spent on the first ones
+	retries, notRetried, budget := policy.selectRetries(candidates, 2)
+	if names := testNames(retries); !reflect.DeepEqual(names, []string{"a", "c"}) {
+		t.Errorf("unexpected retries: %v", names)
+	}
+	if names := testNames(notRetried); !reflect.DeepEqual(names, []string{"b [Serial]", "d", "e"}) {
+		t.Errorf("unexpected tests not retried: %v", names)
+	}
+	if budget != 0 {
+		t.Errorf("expected the budget to be spent, %d remaining", budget)
+	}
+
+	// tests that are out of attempts do not use the budget
+	retries, notRetried, budget = policy.selectRetries(retries, 5)
+	if len(retries) != 0 || len(notRetried) != 2 || budget != 5 {
+		t.Errorf("unexpected second attempt: %d retries, %d not retried, %d remaining", len(retries), len(notRetried), budget)
+	}
+}
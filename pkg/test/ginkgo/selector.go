@@ -0,0 +1,266 @@
+package ginkgo
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// testTagPattern matches the bracketed tags in a test name.
+var testTagPattern = regexp.MustCompile(`\[([^\[\]]+)\]`)
+
+// testTags returns the tags in the name of a test, without brackets.
+func testTags(name string) sets.String {
+	tags := sets.NewString()
+	for _, match := range testTagPattern.FindAllStringSubmatch(name, -1) {
+		tags.Insert(match[1])
+	}
+	return tags
+}
+
+// TagSelector selects tests with a boolean expression over the bracketed tags in their
+// names, for example:
+//
+//	sig-network && !Serial && (Feature:SCTP || Conformance)
+//
+// A tag is true when the test name contains it in brackets, so Serial matches [Serial] but
+// not [Serial:Self]. Tags containing spaces or operators may be written in brackets, as in
+// [Feature:Pod Security]. ! binds tighter than &&, which binds tighter than ||.
+type TagSelector struct {
+	expression string
+	root       tagExpr
+}
+
+type tagExpr interface {
+	matches(tags sets.String) bool
+	collect(into sets.String)
+}
+
+type tagLiteral string
+
+func (e tagLiteral) matches(tags sets.String) bool { return tags.Has(string(e)) }
+func (e tagLiteral) collect(into sets.String)      { into.Insert(string(e)) }
+
+type tagNot struct{ expr tagExpr }
+
+func (e tagNot) matches(tags sets.String) bool { return !e.expr.matches(tags) }
+func (e tagNot) collect(into sets.String)      { e.expr.collect(into) }
+
+type tagAnd []tagExpr
+
+func (e tagAnd) matches(tags sets.String) bool {
+	for _, expr := range e {
+		if !expr.matches(tags) {
+			return false
+		}
+	}
+	return true
+}
+
+func (e tagAnd) collect(into sets.String) {
+	for _, expr := range e {
+		expr.collect(into)
+	}
+}
+
+type tagOr []tagExpr
+
+func (e tagOr) matches(tags sets.String) bool {
+	for _, expr := range e {
+		if expr.matches(tags) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e tagOr) collect(into sets.String) {
+	for _, expr := range e {
+		expr.collect(into)
+	}
+}
+
+// ParseTagSelector parses a tag selector expression.
+func ParseTagSelector(expression string) (*TagSelector, error) {
+	p := &tagParser{input: expression}
+	if err := p.tokenize(); err != nil {
+		return nil, err
+	}
+	if len(p.tokens) == 0 {
+		return nil, fmt.Errorf("the expression is empty")
+	}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, p.unexpected()
+	}
+	return &TagSelector{expression: expression, root: root}, nil
+}
+
+func (s *TagSelector) String() string {
+	return s.expression
+}
+
+// Matches returns true if the tags in the test name satisfy the expression.
+func (s *TagSelector) Matches(name string) bool {
+	return s.root.matches(testTags(name))
+}
+
+// Tags returns the tags the expression refers to.
+func (s *TagSelector) Tags() []string {
+	tags := sets.NewString()
+	s.root.collect(tags)
+	return tags.List()
+}
+
+// UnknownTags returns the tags the expression refers to that are not on any of the named
+// tests, which usually means the tag is misspelled.
+func (s *TagSelector) UnknownTags(names []string) []string {
+	known := sets.NewString()
+	for _, name := range names {
+		known = known.Union(testTags(name))
+	}
+	var unknown []string
+	for _, tag := range s.Tags() {
+		if !known.Has(tag) {
+			unknown = append(unknown, tag)
+		}
+	}
+	return unknown
+}
+
+type tagToken struct {
+	value string
+	// offset is the position of the token in the expression
+	offset int
+	tag    bool
+}
+
+type tagParser struct {
+	input  string
+	tokens []tagToken
+	pos    int
+}
+
+func (p *tagParser) tokenize() error {
+	input := p.input
+	for i := 0; i < len(input); {
+		switch c := input[i]; {
+		case c == ' ' || c == '\t':
+			i++
+		case strings.HasPrefix(input[i:], "&&") || strings.HasPrefix(input[i:], "||"):
+			p.tokens = append(p.tokens, tagToken{value: input[i : i+2], offset: i})
+			i += 2
+		case c == '!' || c == '(' || c == ')':
+			p.tokens = append(p.tokens, tagToken{value: input[i : i+1], offset: i})
+			i++
+		case c == '&' || c == '|':
+			return fmt.Errorf("expected %c%c at position %d", c, c, i+1)
+		case c == '[':
+			end := strings.IndexByte(input[i:], ']')
+			if end == -1 {
+				return fmt.Errorf("unterminated tag starting at position %d", i+1)
+			}
+			tag := strings.TrimSpace(input[i+1 : i+end])
+			if len(tag) == 0 || strings.ContainsAny(tag, "[") {
+				return fmt.Errorf("invalid tag at position %d", i+1)
+			}
+			p.tokens = append(p.tokens, tagToken{value: tag, offset: i, tag: true})
+			i += end + 1
+		case c == ']':
+			return fmt.Errorf("unexpected ] at position %d", i+1)
+		default:
+			end := strings.IndexAny(input[i:], " \t&|!()[]")
+			if end == -1 {
+				end = len(input) - i
+			}
+			p.tokens = append(p.tokens, tagToken{value: input[i : i+end], offset: i, tag: true})
+			i += end
+		}
+	}
+	return nil
+}
+
+func (p *tagParser) peek(value string) bool {
+	return p.pos < len(p.tokens) && !p.tokens[p.pos].tag && p.tokens[p.pos].value == value
+}
+
+func (p *tagParser) unexpected() error {
+	if p.pos >= len(p.tokens) {
+		return fmt.Errorf("unexpected end of expression")
+	}
+	token := p.tokens[p.pos]
+	return fmt.Errorf("unexpected %q at position %d", token.value, token.offset+1)
+}
+
+func (p *tagParser) parseOr() (tagExpr, error) {
+	expr, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	or := tagOr{expr}
+	for p.peek("||") {
+		p.pos++
+		expr, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		or = append(or, expr)
+	}
+	if len(or) == 1 {
+		return or[0], nil
+	}
+	return or, nil
+}
+
+func (p *tagParser) parseAnd() (tagExpr, error) {
+	expr, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	and := tagAnd{expr}
+	for p.peek("&&") {
+		p.pos++
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		and = append(and, expr)
+	}
+	if len(and) == 1 {
+		return and[0], nil
+	}
+	return and, nil
+}
+
+func (p *tagParser) parseUnary() (tagExpr, error) {
+	switch {
+	case p.peek("!"):
+		p.pos++
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return tagNot{expr}, nil
+	case p.peek("("):
+		p.pos++
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.peek(")") {
+			return nil, p.unexpected()
+		}
+		p.pos++
+		return expr, nil
+	case p.pos < len(p.tokens) && p.tokens[p.pos].tag:
+		p.pos++
+		return tagLiteral(p.tokens[p.pos-1].value), nil
+	default:
+		return nil, p.unexpected()
+	}
+}
@@ -0,0 +1,76 @@
+package ginkgo
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTagSelector(t *testing.T) {
+	sctp := "[sig-network] SCTP [Feature:SCTP] should create a pod [Suite:openshift/conformance/parallel]"
+	serial := "[sig-network] Services [Serial] should work [Conformance] [Suite:openshift/conformance/serial]"
+	conformance := "[sig-network] DNS should resolve [Conformance] [Suite:openshift/conformance/parallel]"
+	self := "[sig-network] Services [Serial:Self] should work [Suite:openshift/conformance/parallel]"
+	apps := "[sig-apps] Deployments [Feature:Pod Security] should roll out [Suite:openshift/conformance/parallel]"
+
+	for expression, expected := range map[string][]string{
+		"sig-network && !Serial && (Feature:SCTP || Conformance)": {sctp, conformance},
+		"sig-network && !Serial && Feature:SCTP || Conformance":   {sctp, serial, conformance},
+		"Serial": {serial},
+		"[Serial:Self] || [Feature:Pod Security]": {self, apps},
+		"!!sig-apps":                 {apps},
+		"!(sig-network || sig-apps)": nil,
+		" ( ( Conformance ) ) ":      {serial, conformance},
+	} {
+		selector, err := ParseTagSelector(expression)
+		if err != nil {
+			t.Errorf("%q: %v", expression, err)
+			continue
+		}
+		var matched []string
+		for _, name := range []string{sctp, serial, conformance, self, apps} {
+			if selector.Matches(name) {
+				matched = append(matched, name)
+			}
+		}
+		if !reflect.DeepEqual(matched, expected) {
+			t.Errorf("%q matched %v", expression, matched)
+		}
+	}
+}
+
+func TestParseTagSelectorInvalid(t *testing.T) {
+	for expression, expected := range map[string]string{
+		"":                     "empty",
+		"sig-network &&":       "unexpected end of expression",
+		"sig-network & Serial": "expected && at position 13",
+		"(Serial":              "unexpected end of expression",
+		"Serial)":              `unexpected ")" at position 7`,
+		"Serial Slow":          `unexpected "Slow" at position 8`,
+		"[Serial":              "unterminated tag",
+		"Serial]":              "unexpected ] at position 7",
+		"|| Serial":            `unexpected "||" at position 1`,
+	} {
+		_, err := ParseTagSelector(expression)
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected error containing %q for %q, got %v", expected, expression, err)
+		}
+	}
+}
+
+func TestTagSelectorUnknownTags(t *testing.T) {
+	selector, err := ParseTagSelector("sig-network && !Serial && (Feature:SCPT || Conformance)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tags := selector.Tags(); !reflect.DeepEqual(tags, []string{"Conformance", "Feature:SCPT", "Serial", "sig-network"}) {
+		t.Fatalf("unexpected tags: %v", tags)
+	}
+	unknown := selector.UnknownTags([]string{
+		"[sig-network] SCTP [Feature:SCTP] should create a pod",
+		"[sig-network] Services [Serial] should work [Conformance]",
+	})
+	if !reflect.DeepEqual(unknown, []string{"Feature:SCPT"}) {
+		t.Fatalf("unexpected unknown tags: %v", unknown)
+	}
+}
@@ -0,0 +1,85 @@
+package ginkgo
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Shard identifies one of Count independent runner processes that a suite is split
+// across. Index is 1-based.
+type Shard struct {
+	Index int
+	Count int
+}
+
+// ParseShard parses a shard of the form N/M, where 1 <= N <= M.
+func ParseShard(value string) (*Shard, error) {
+	parts := strings.SplitN(value, "/", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("shard must be of the form N/M, got %q", value)
+	}
+	index, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("shard index is not a number: %q", parts[0])
+	}
+	count, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("shard count is not a number: %q", parts[1])
+	}
+	if count < 1 || index < 1 || index > count {
+		return nil, fmt.Errorf("shard must satisfy 1 <= N <= M, got %q", value)
+	}
+	return &Shard{Index: index, Count: count}, nil
+}
+
+func (s *Shard) String() string {
+	return fmt.Sprintf("%d/%d", s.Index, s.Count)
+}
+
+// isDesignated returns true for the shard that runs the [Early] and [Late] tests, which
+// check the state of the cluster before and after the rest of the suite and must
+// therefore run in a single process.
+func (s *Shard) isDesignated() bool {
+	return s.Index == 1
+}
+
+// Select returns the subset of tests assigned to this shard. Every shard must be given
+// the same tests and timings so that the assignment is identical across processes.
+// Tests are assigned longest first to the shard with the least estimated work, using
+// timings when available, so that shards finish at roughly the same time.
+func (s *Shard) Select(tests []*testCase, timings *TestTimings) []*testCase {
+	if s.Count == 1 {
+		return tests
+	}
+
+	var selected []*testCase
+	pinned, others := splitTests(tests, func(t *testCase) bool {
+		return strings.Contains(t.name, "[Early]") || strings.Contains(t.name, "[Late]")
+	})
+	if s.isDesignated() {
+		selected = append(selected, pinned...)
+	}
+
+	// order by name first so that the result does not depend on suite iteration order
+	others = sortedTests(others)
+	estimate := timings.estimator(others)
+	sort.SliceStable(others, func(i, j int) bool { return estimate(others[i]) > estimate(others[j]) })
+
+	load := make([]time.Duration, s.Count)
+	for _, test := range others {
+		shard := 0
+		for i := range load {
+			if load[i] < load[shard] {
+				shard = i
+			}
+		}
+		load[shard] += estimate(test)
+		if shard == s.Index-1 {
+			selected = append(selected, test)
+		}
+	}
+	return selected
+}
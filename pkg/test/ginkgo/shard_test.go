@@ -0,0 +1,78 @@
+package ginkgo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestShardSelect(t *testing.T) {
+	timings := &TestTimings{Tests: map[string]float64{
+		"a": 100,
+		"b": 60,
+		"c": 50,
+		"d": 10,
+	}}
+	tests := func() []*testCase {
+		return []*testCase{
+			{name: "d"}, {name: "[Early] e"}, {name: "c"}, {name: "b"}, {name: "a"}, {name: "[Late] l"},
+		}
+	}
+
+	var all []string
+	for i, expected := range [][]string{
+		{"[Early] e", "[Late] l", "a", "d"},
+		{"b", "c"},
+	} {
+		shard := &Shard{Index: i + 1, Count: 2}
+		names := testNames(shard.Select(tests(), timings))
+		if !reflect.DeepEqual(expected, names) {
+			t.Errorf("shard %s: unexpected tests %v", shard, names)
+		}
+		all = append(all, names...)
+	}
+	if len(all) != len(tests()) {
+		t.Errorf("every test should be assigned to exactly one shard: %v", all)
+	}
+}
+
+func TestParseShard(t *testing.T) {
+	for value, valid := range map[string]bool{
+		"1/1": true,
+		"2/3": true,
+		"0/3": false,
+		"4/3": false,
+		"1":   false,
+		"a/b": false,
+	} {
+		if _, err := ParseShard(value); (err == nil) != valid {
+			t.Errorf("%s: unexpected error %v", value, err)
+		}
+	}
+}
+
+func TestMergeJUnitSuites(t *testing.T) {
+	merged := mergeJUnitSuites([]*JUnitTestSuite{
+		{Duration: 10, TestCases: []*JUnitTestCase{
+			{Name: "passed"},
+			{Name: "invariant", FailureOutput: &FailureOutput{}},
+			{Name: "failed", FailureOutput: &FailureOutput{}},
+			{Name: "retried", FailureOutput: &FailureOutput{}},
+			{Name: "retried"},
+			{Name: "flaky invariant", FailureOutput: &FailureOutput{}},
+			{Name: "flaky invariant"},
+		}},
+		{Duration: 20, TestCases: []*JUnitTestCase{
+			{Name: "invariant"},
+			{Name: "skipped", SkipMessage: &SkipMessage{}},
+			{Name: "passed"},
+			{Name: "flaky invariant"},
+		}},
+	})
+	if merged.Duration != 20 || merged.NumTests != 8 || merged.NumFailed != 4 || merged.NumSkipped != 1 {
+		t.Errorf("unexpected merged suite: %#v", merged)
+	}
+	failing, flaky := failingAndFlakyTestCases(merged.TestCases)
+	if !reflect.DeepEqual([]string{"failed", "invariant"}, failing) || !reflect.DeepEqual([]string{"flaky invariant", "retried"}, flaky) {
+		t.Errorf("unexpected failing %v and flaky %v", failing, flaky)
+	}
+}
@@ -26,6 +26,8 @@ type testStatus struct {
 	env             []string
 
 	afterTestFn func(t *testCase)
+	journal     *testJournal
+	reporters   []testReporter
 
 	includeSuccessfulOutput bool
 
@@ -53,6 +55,25 @@ func (s *testStatus) AfterTest(fn func(t *testCase)) {
 	s.afterTestFn = fn
 }
 
+// RecordTo appends the outcome of each test to the provided journal as it completes.
+func (s *testStatus) RecordTo(journal *testJournal) {
+	s.journal = journal
+}
+
+// ReportTo notifies the provided reporters as each test starts and completes.
+func (s *testStatus) ReportTo(reporters []testReporter) {
+	s.reporters = reporters
+}
+
+// testStarted notifies the reporters that the test has started.
+func (s *testStatus) testStarted(test *testCase) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for _, reporter := range s.reporters {
+		reporter.TestStarted(test)
+	}
+}
+
 // fprintf formats the provided string with the status of the test with arguments failures, index, and total
 func (s *testStatus) fprintf(format string) {
 	s.lock.Lock()
@@ -67,7 +88,7 @@ func (s *testStatus) fprintf(format string) {
 }
 
 // finalizeTest outputs the result of the test to s.out, increments s.failures if necessary,
-// and invokes afterTestFn if registered.
+// records the result to the journal, and invokes afterTestFn if registered.
 func (s *testStatus) finalizeTest(test *testCase) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
@@ -122,10 +143,20 @@ func (s *testStatus) finalizeTest(test *testCase) {
 	}
 
 	s.monitorRecorder.Record(monitorapi.Condition{
-		Level:   eventLevel,
-		Locator: monitorapi.E2ETestLocator(test.name),
-		Message: eventMessage,
+		Level:             eventLevel,
+		Locator:           monitorapi.E2ETestLocator(test.name),
+		Message:           eventMessage,
+		StructuredLocator: monitorapi.LocateE2ETestWithID(test.name, TestID(test.name)),
 	})
+
+	if s.journal != nil {
+		if err := s.journal.Record(test); err != nil {
+			fmt.Fprintf(s.out, "error: Unable to record %q to the test journal: %v\n\n", test.name, err)
+		}
+	}
+	for _, reporter := range s.reporters {
+		reporter.TestFinished(test)
+	}
 }
 
 // OutputCommand prints to stdout what would have been executed.
@@ -141,9 +172,10 @@ func (s *testStatus) OutputCommand(ctx context.Context, test *testCase) {
 
 func (s *testStatus) Run(ctx context.Context, test *testCase) {
 	s.monitorRecorder.Record(monitorapi.Condition{
-		Level:   monitorapi.Info,
-		Locator: monitorapi.E2ETestLocator(test.name),
-		Message: "started",
+		Level:             monitorapi.Info,
+		Locator:           monitorapi.E2ETestLocator(test.name),
+		Message:           "started",
+		StructuredLocator: monitorapi.LocateE2ETestWithID(test.name, TestID(test.name)),
 	})
 	defer s.finalizeTest(test)
 
@@ -151,8 +183,10 @@ func (s *testStatus) Run(ctx context.Context, test *testCase) {
 	c := exec.Command(os.Args[0], "run-test", test.name)
 	c.Env = append(os.Environ(), s.env...)
 	s.fprintf(fmt.Sprintf("started: (%s) %q\n\n", "%d/%d/%d", test.name))
+	s.testStarted(test)
 	out, err := runWithTimeout(ctx, c, s.timeout)
 	test.end = time.Now()
+	recordProcessUsage(test, c.ProcessState)
 
 	duration := test.end.Sub(test.start).Round(time.Second / 10)
 	if duration > time.Minute {
@@ -166,6 +200,7 @@ func (s *testStatus) Run(ctx context.Context, test *testCase) {
 	}
 
 	if ctx.Err() != nil {
+		test.interrupted = true
 		test.skipped = true
 		test.flake = false
 		test.failed = false
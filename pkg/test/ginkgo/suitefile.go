@@ -0,0 +1,180 @@
+package ginkgo
+
+import (
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+// SuiteFile declares test suites without compiling them into the binary, for example:
+//
+//	suites:
+//	- name: network-edge/conformance
+//	  description: Router and ingress tests owned by the network edge team.
+//	  include:
+//	  - '\[sig-network-edge\]'
+//	  - '\[Feature:Router\]'
+//	  exclude:
+//	  - 'should be able to run a router with a very long name'
+//	  tags:
+//	  - '[Suite:openshift/conformance/parallel]'
+//	  parallelism: 10
+//	  timeout: 20m
+//	  maxFlakes: 2
+//	  invariants: stable
+//
+// A test is selected when it matches at least one include regex (or include is empty),
+// contains every tag, and matches no exclude regex. Tests disabled by the annotator are
+// never selected, as in the built-in suites.
+type SuiteFile struct {
+	Suites []SuiteDefinition `json:"suites"`
+}
+
+// SuiteDefinition is a single suite in a suite file.
+type SuiteDefinition struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+
+	// Include are regular expressions of which a test name must match at least one.
+	Include []string `json:"include,omitempty"`
+	// Exclude are regular expressions of which a test name must match none.
+	Exclude []string `json:"exclude,omitempty"`
+	// Tags must all appear in the test name, for example [sig-network] or [Serial].
+	Tags []string `json:"tags,omitempty"`
+
+	// Parallelism is the maximum number of tests to run at once.
+	Parallelism int `json:"parallelism,omitempty"`
+	// Count is the number of times to run each test.
+	Count int `json:"count,omitempty"`
+	// Timeout is the maximum duration of each test, such as 20m.
+	Timeout string `json:"timeout,omitempty"`
+	// MaxFlakes is the number of flakes that may occur before they fail the suite.
+	MaxFlakes int `json:"maxFlakes,omitempty"`
+	// Invariants names the set of synthetic tests checked against the events of the run.
+	Invariants string `json:"invariants,omitempty"`
+}
+
+// LoadSuiteFile reads and validates the suites declared in a YAML suite file. invariants
+// maps the names that may be given as the invariants of a suite to the synthetic tests
+// they apply.
+func LoadSuiteFile(path string, invariants map[string]JUnitsForEvents) ([]*TestSuite, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	file := &SuiteFile{}
+	if err := yaml.UnmarshalStrict(data, file); err != nil {
+		return nil, fmt.Errorf("unable to parse suites from %s: %v", path, err)
+	}
+	if len(file.Suites) == 0 {
+		return nil, fmt.Errorf("%s does not declare any suites", path)
+	}
+	names := make(map[string]bool)
+	suites := make([]*TestSuite, 0, len(file.Suites))
+	for i, definition := range file.Suites {
+		suite, err := definition.toTestSuite(invariants)
+		if err != nil {
+			return nil, fmt.Errorf("suite %d in %s: %v", i+1, path, err)
+		}
+		if names[suite.Name] {
+			return nil, fmt.Errorf("suite %q is declared more than once in %s", suite.Name, path)
+		}
+		names[suite.Name] = true
+		suites = append(suites, suite)
+	}
+	return suites, nil
+}
+
+func (d SuiteDefinition) toTestSuite(invariants map[string]JUnitsForEvents) (*TestSuite, error) {
+	if len(d.Name) == 0 {
+		return nil, fmt.Errorf("must have a name")
+	}
+	include, err := compileSuiteRegexes(d.Include)
+	if err != nil {
+		return nil, fmt.Errorf("%q has an invalid include regex: %v", d.Name, err)
+	}
+	exclude, err := compileSuiteRegexes(d.Exclude)
+	if err != nil {
+		return nil, fmt.Errorf("%q has an invalid exclude regex: %v", d.Name, err)
+	}
+	for _, tag := range d.Tags {
+		if !strings.HasPrefix(tag, "[") || !strings.HasSuffix(tag, "]") {
+			return nil, fmt.Errorf("%q has a tag that is not in brackets: %q", d.Name, tag)
+		}
+	}
+	if d.Parallelism < 0 || d.Count < 0 || d.MaxFlakes < 0 {
+		return nil, fmt.Errorf("%q must not have a negative parallelism, count or maxFlakes", d.Name)
+	}
+	var timeout time.Duration
+	if len(d.Timeout) > 0 {
+		timeout, err = time.ParseDuration(d.Timeout)
+		if err != nil || timeout <= 0 {
+			return nil, fmt.Errorf("%q has an invalid timeout %q", d.Name, d.Timeout)
+		}
+	}
+	var syntheticEventTests JUnitsForEvents
+	if len(d.Invariants) > 0 {
+		var ok bool
+		syntheticEventTests, ok = invariants[d.Invariants]
+		if !ok {
+			var known []string
+			for name := range invariants {
+				known = append(known, name)
+			}
+			sort.Strings(known)
+			return nil, fmt.Errorf("%q has unknown invariants %q, must be one of: %s", d.Name, d.Invariants, strings.Join(known, ", "))
+		}
+	}
+
+	tags := d.Tags
+	return &TestSuite{
+		Name:        d.Name,
+		Description: d.Description,
+		Matches: func(name string) bool {
+			if strings.Contains(name, "[Disabled") {
+				return false
+			}
+			for _, tag := range tags {
+				if !strings.Contains(name, tag) {
+					return false
+				}
+			}
+			for _, re := range exclude {
+				if re.MatchString(name) {
+					return false
+				}
+			}
+			if len(include) == 0 {
+				return true
+			}
+			for _, re := range include {
+				if re.MatchString(name) {
+					return true
+				}
+			}
+			return false
+		},
+		Parallelism:          d.Parallelism,
+		Count:                d.Count,
+		TestTimeout:          timeout,
+		MaximumAllowedFlakes: d.MaxFlakes,
+		SyntheticEventTests:  syntheticEventTests,
+	}, nil
+}
+
+func compileSuiteRegexes(patterns []string) ([]*regexp.Regexp, error) {
+	regexes := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+		regexes = append(regexes, re)
+	}
+	return regexes, nil
+}
@@ -0,0 +1,88 @@
+package ginkgo
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeSuiteFile(t *testing.T, contents string) string {
+	path := filepath.Join(t.TempDir(), "suites.yaml")
+	if err := ioutil.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadSuiteFile(t *testing.T) {
+	invariants := map[string]JUnitsForEvents{"stable": JUnitsForAllEvents{}}
+	path := writeSuiteFile(t, `
+suites:
+- name: team/edge
+  description: Edge tests.
+  include:
+  - '\[sig-network-edge\]'
+  - '\[Feature:Router\]'
+  exclude:
+  - 'very long name'
+  tags:
+  - '[Suite:openshift/conformance/parallel]'
+  parallelism: 10
+  count: 2
+  timeout: 20m
+  maxFlakes: 3
+  invariants: stable
+- name: team/all
+`)
+	suites, err := LoadSuiteFile(path, invariants)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(suites) != 2 {
+		t.Fatalf("unexpected suites: %#v", suites)
+	}
+	edge := suites[0]
+	if edge.Name != "team/edge" || edge.Description != "Edge tests." || edge.Parallelism != 10 || edge.Count != 2 ||
+		edge.TestTimeout != 20*time.Minute || edge.MaximumAllowedFlakes != 3 || edge.SyntheticEventTests == nil {
+		t.Fatalf("unexpected suite: %#v", edge)
+	}
+	for name, expected := range map[string]bool{
+		"[sig-network-edge] routes work [Suite:openshift/conformance/parallel]":                   true,
+		"[sig-network][Feature:Router] routers work [Suite:openshift/conformance/parallel]":       true,
+		"[sig-network-edge] routes work [Suite:openshift/conformance/serial]":                     false,
+		"[sig-network-edge] a very long name [Suite:openshift/conformance/parallel]":              false,
+		"[sig-network-edge] routes work [Disabled:Broken] [Suite:openshift/conformance/parallel]": false,
+		"[sig-apps] deployments work [Suite:openshift/conformance/parallel]":                      false,
+	} {
+		if edge.Matches(name) != expected {
+			t.Errorf("expected match of %q to be %t", name, expected)
+		}
+	}
+	if all := suites[1]; !all.Matches("[sig-apps] anything") || all.SyntheticEventTests != nil {
+		t.Fatalf("unexpected suite: %#v", all)
+	}
+}
+
+func TestLoadSuiteFileInvalid(t *testing.T) {
+	invariants := map[string]JUnitsForEvents{"stable": JUnitsForAllEvents{}, "system": JUnitsForAllEvents{}}
+	for contents, expected := range map[string]string{
+		"suites: []":                                       "does not declare any suites",
+		"suites:\n- description: unnamed":                  "must have a name",
+		"suites:\n- name: a\n- description: unnamed":       "suite 2 in ",
+		"suites:\n- name: a\n- name: a":                    "declared more than once",
+		"suites:\n- name: a\n  include: ['[']":             "invalid include regex",
+		"suites:\n- name: a\n  exclude: ['(']":             "invalid exclude regex",
+		"suites:\n- name: a\n  tags: ['Serial']":           "not in brackets",
+		"suites:\n- name: a\n  parallelism: -1":            "negative",
+		"suites:\n- name: a\n  timeout: soon":              "invalid timeout",
+		"suites:\n- name: a\n  invariants: upgrade":        "must be one of: stable, system",
+		"suites:\n- name: a\n  include: ['x']\n  bogus: 1": "unknown field",
+	} {
+		_, err := LoadSuiteFile(writeSuiteFile(t, contents), invariants)
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected error containing %q for %q, got %v", expected, contents, err)
+		}
+	}
+}
@@ -32,6 +32,16 @@ type testCase struct {
 	skipped  bool
 	flake    bool
 
+	// interrupted is set when the test was stopped because the suite was cancelled
+	interrupted bool
+
+	// quarantine is set when the test is known to flake and may not fail the suite
+	quarantine *QuarantinedTest
+	// impact is the resource usage of the process that ran the test and its effect on the cluster
+	impact *TestImpact
+	// disruptions are the cluster disruptions that overlapped the test if it failed
+	disruptions []ConcurrentDisruption
+
 	previous *testCase
 }
 
@@ -52,6 +62,7 @@ func (t *testCase) Retry() *testCase {
 		spec:          t.spec,
 		location:      t.location,
 		testExclusion: t.testExclusion,
+		quarantine:    t.quarantine,
 
 		previous: t,
 	}
@@ -70,6 +81,10 @@ type TestSuite struct {
 	Parallelism int
 	// The number of flakes that may occur before this test is marked as a failure.
 	MaximumAllowedFlakes int
+	// RetryPolicy controls which failed tests are retried to detect flakes. If unset, each
+	// failure is retried once until MaximumAllowedFlakes retries have been used. The suite
+	// passes if every failure passed when retried.
+	RetryPolicy *RetryPolicy
 
 	// SyntheticEventTests is a set of suite level synthetics applied
 	SyntheticEventTests JUnitsForEvents
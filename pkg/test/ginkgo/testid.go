@@ -0,0 +1,50 @@
+package ginkgo
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+)
+
+// testIDIgnoredTags matches the tags added to test names by the annotator, which change as
+// tests move between suites or are enabled and disabled on platforms, and so are not part
+// of the identity of a test.
+var testIDIgnoredTags = regexp.MustCompile(`\s*\[(?:Suite|Disabled|Skipped):[^\]]*\]`)
+
+// testRenames maps the normalized name of a renamed test to the normalized name it had
+// before, so that its ID, and the history keyed by it, follows the test across the rename.
+// When a test description changes, add an entry here from the new name to the old one
+// with the annotator tags removed. Renames may be chained.
+var testRenames = map[string]string{}
+
+// normalizeTestName removes the annotator tags and redundant whitespace from a test name.
+func normalizeTestName(name string) string {
+	return strings.Join(strings.Fields(testIDIgnoredTags.ReplaceAllString(name, "")), " ")
+}
+
+// originalTestName follows renames of the normalized test name back to the first name the
+// test had.
+func originalTestName(name string, renames map[string]string) string {
+	seen := map[string]bool{name: true}
+	for {
+		previous, ok := renames[name]
+		if !ok || seen[previous] {
+			return name
+		}
+		seen[previous] = true
+		name = previous
+	}
+}
+
+// TestID returns a stable identifier for the named test that does not change when the
+// suites or platforms it runs on change, or when it is renamed with an entry in the rename
+// map.
+func TestID(name string) string {
+	return testIDFor(name, testRenames)
+}
+
+func testIDFor(name string, renames map[string]string) string {
+	sum := sha256.Sum256([]byte(originalTestName(normalizeTestName(name), renames)))
+	return hex.EncodeToString(sum[:8])
+}
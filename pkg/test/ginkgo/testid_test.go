@@ -0,0 +1,37 @@
+package ginkgo
+
+import "testing"
+
+func TestTestID(t *testing.T) {
+	name := "[sig-network] Services should serve a basic endpoint from pods [Conformance]"
+	id := testIDFor(name, nil)
+	if len(id) != 16 {
+		t.Fatalf("unexpected ID %q", id)
+	}
+	for _, annotated := range []string{
+		name + " [Suite:openshift/conformance/parallel/minimal] [Suite:k8s]",
+		name + " [Skipped:ibmroks] [Suite:openshift/conformance/parallel]",
+		"[sig-network] Services  should serve a basic endpoint from pods [Conformance] [Disabled:Broken]",
+	} {
+		if got := testIDFor(annotated, nil); got != id {
+			t.Errorf("expected %q to have ID %s, got %s", annotated, id, got)
+		}
+	}
+	if got := testIDFor("[sig-network] Services should serve a basic endpoint from pods [Conformance] [Serial]", nil); got == id {
+		t.Errorf("expected tags other than the annotations to change the ID")
+	}
+
+	renames := map[string]string{
+		"[sig-network] Services should serve endpoints from pods [Conformance]":    "[sig-network] Services should serve a basic endpoint from pods [Conformance]",
+		"[sig-network] Services should serve endpoints from pods [Conformance] v2": "[sig-network] Services should serve endpoints from pods [Conformance]",
+		// cycles are ignored
+		"a": "b",
+		"b": "a",
+	}
+	if got := testIDFor("[sig-network] Services should serve endpoints from pods [Conformance] v2 [Suite:k8s]", renames); got != id {
+		t.Errorf("expected a renamed test to keep its ID %s, got %s", id, got)
+	}
+	if testIDFor("a", renames) == testIDFor("c", renames) {
+		t.Errorf("unexpected ID collision")
+	}
+}
@@ -0,0 +1,156 @@
+package ginkgo
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TestTimings records how long each test has historically taken to run, keyed by
+// test name. It is used to schedule the longest tests first so that a slow test
+// picked up late does not extend the end of the run.
+type TestTimings struct {
+	// Tests maps a test name to its expected duration in seconds.
+	Tests map[string]float64 `json:"tests"`
+}
+
+// LoadTestTimings reads timings from path. A JSON timings file is read directly,
+// while a JUnit XML report (or a directory containing junit_e2e_*.xml reports) has
+// the durations of its passing and failing tests harvested.
+func LoadTestTimings(path string) (*TestTimings, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		files, err := filepath.Glob(filepath.Join(path, "junit_e2e_*.xml"))
+		if err != nil {
+			return nil, err
+		}
+		timings := &TestTimings{Tests: make(map[string]float64)}
+		for _, file := range files {
+			if err := timings.addJUnit(file); err != nil {
+				return nil, err
+			}
+		}
+		return timings, nil
+	}
+
+	if strings.HasSuffix(path, ".xml") {
+		timings := &TestTimings{Tests: make(map[string]float64)}
+		if err := timings.addJUnit(path); err != nil {
+			return nil, err
+		}
+		return timings, nil
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	timings := &TestTimings{}
+	if err := json.Unmarshal(data, timings); err != nil {
+		return nil, fmt.Errorf("unable to read test timings from %s: %v", path, err)
+	}
+	if timings.Tests == nil {
+		timings.Tests = make(map[string]float64)
+	}
+	return timings, nil
+}
+
+func (t *TestTimings) addJUnit(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	suite := &JUnitTestSuite{}
+	if err := xml.Unmarshal(data, suite); err != nil {
+		return fmt.Errorf("unable to read test timings from %s: %v", path, err)
+	}
+	for _, test := range suite.TestCases {
+		if test.SkipMessage != nil || test.Duration == 0 {
+			continue
+		}
+		if test.Duration > t.Tests[test.Name] {
+			t.Tests[test.Name] = test.Duration
+		}
+	}
+	return nil
+}
+
+// Write saves the timings as JSON to path.
+func (t *TestTimings) Write(path string) error {
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+// Update records the observed duration of each test that passed or failed. Skipped
+// tests are ignored since they do not reflect how long the test takes to run.
+func (t *TestTimings) Update(tests []*testCase) {
+	if t.Tests == nil {
+		t.Tests = make(map[string]float64)
+	}
+	for _, test := range tests {
+		if !test.success && !test.failed {
+			continue
+		}
+		if test.duration == 0 {
+			continue
+		}
+		t.Tests[test.name] = test.duration.Seconds()
+	}
+}
+
+// Duration returns the expected duration of the named test and whether it is known.
+func (t *TestTimings) Duration(name string) (time.Duration, bool) {
+	if t == nil {
+		return 0, false
+	}
+	seconds, ok := t.Tests[name]
+	if !ok {
+		return 0, false
+	}
+	return time.Duration(seconds * float64(time.Second)), true
+}
+
+// sortLongestFirst orders tests by descending expected duration. The sort is stable
+// so tests with equal estimates keep their relative order.
+func (t *TestTimings) sortLongestFirst(tests []*testCase) {
+	if t == nil || len(t.Tests) == 0 {
+		return
+	}
+	estimate := t.estimator(tests)
+	sort.SliceStable(tests, func(i, j int) bool { return estimate(tests[i]) > estimate(tests[j]) })
+}
+
+// estimator returns a function that estimates the duration of a test. Tests with no
+// known duration are assumed to take the median known duration of the provided tests,
+// so that they neither crowd out nor trail the tests we have data for.
+func (t *TestTimings) estimator(tests []*testCase) func(*testCase) time.Duration {
+	var known []time.Duration
+	for _, test := range tests {
+		if d, ok := t.Duration(test.name); ok {
+			known = append(known, d)
+		}
+	}
+	median := time.Second
+	if len(known) > 0 {
+		sort.Slice(known, func(i, j int) bool { return known[i] < known[j] })
+		median = known[len(known)/2]
+	}
+	return func(test *testCase) time.Duration {
+		if d, ok := t.Duration(test.name); ok {
+			return d
+		}
+		return median
+	}
+}
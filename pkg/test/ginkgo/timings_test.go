@@ -0,0 +1,34 @@
+package ginkgo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTimingsSortLongestFirst(t *testing.T) {
+	timings := &TestTimings{Tests: map[string]float64{
+		"short":  1,
+		"medium": 30,
+		"long":   600,
+	}}
+	tests := []*testCase{
+		{name: "short"},
+		{name: "unknown-1"},
+		{name: "long"},
+		{name: "medium"},
+		{name: "unknown-2"},
+	}
+	timings.sortLongestFirst(tests)
+	// unknown tests are estimated at the median known duration and keep their order
+	expected := []string{"long", "unknown-1", "medium", "unknown-2", "short"}
+	if names := testNames(tests); !reflect.DeepEqual(expected, names) {
+		t.Errorf("unexpected order: %v", names)
+	}
+
+	var none *TestTimings
+	tests = []*testCase{{name: "short"}, {name: "long"}}
+	none.sortLongestFirst(tests)
+	if names := testNames(tests); !reflect.DeepEqual([]string{"short", "long"}, names) {
+		t.Errorf("order should not change without timings: %v", names)
+	}
+}
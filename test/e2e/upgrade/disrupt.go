@@ -0,0 +1,499 @@
+package upgrade
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/kubernetes/test/e2e/framework"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+// disruptionInjector disrupts part of the cluster on purpose.
+type disruptionInjector interface {
+	// Inject disrupts the cluster once and returns when the disruption has ended, recording
+	// the start and end of the disruption of each object it targets.
+	Inject(ctx context.Context, kubeClient kubernetes.Interface, recorder *disruptionRecorder) error
+}
+
+// disruptionInjectorType describes an injector that may be named in a disrupt option.
+type disruptionInjectorType struct {
+	// positional is the argument set by a value given without a key, if any
+	positional string
+	// arguments lists the arguments the injector accepts, other than every
+	arguments []string
+	build     func(args disruptionArgs) (disruptionInjector, error)
+}
+
+// disruptionInjectors are the injectors that may be selected with disrupt=NAME[:ARGS].
+var disruptionInjectors = map[string]disruptionInjectorType{
+	"reboot": {
+		arguments: []string{"role", "mode"},
+		build: func(args disruptionArgs) (disruptionInjector, error) {
+			injector := &rebootInjector{role: args.get("role", "master")}
+			switch mode := args.get("mode", "graceful"); mode {
+			case "graceful":
+			case "force":
+				injector.hard = true
+			default:
+				return nil, fmt.Errorf("mode must be 'graceful' or 'force', not %q", mode)
+			}
+			return injector, nil
+		},
+	},
+	"kill-pod": {
+		positional: "ns",
+		arguments:  []string{"ns", "selector"},
+		build: func(args disruptionArgs) (disruptionInjector, error) {
+			injector := &killPodInjector{namespace: args.get("ns", ""), selector: args.get("selector", "")}
+			if len(injector.namespace) == 0 {
+				return nil, fmt.Errorf("ns is required")
+			}
+			return injector, nil
+		},
+	},
+	"cordon-drain": {
+		positional: "role",
+		arguments:  []string{"role", "for"},
+		build: func(args disruptionArgs) (disruptionInjector, error) {
+			duration, err := args.duration("for", 2*time.Minute)
+			if err != nil {
+				return nil, err
+			}
+			return &cordonDrainInjector{role: args.get("role", "worker"), duration: duration}, nil
+		},
+	},
+	"delete-leader-lease": {
+		positional: "ns",
+		arguments:  []string{"ns", "name"},
+		build: func(args disruptionArgs) (disruptionInjector, error) {
+			injector := &deleteLeaderLeaseInjector{namespace: args.get("ns", ""), name: args.get("name", "")}
+			if len(injector.namespace) == 0 {
+				return nil, fmt.Errorf("ns is required")
+			}
+			return injector, nil
+		},
+	},
+}
+
+// scheduledDisruption injects a disruption repeatedly while the upgrade runs.
+type scheduledDisruption struct {
+	spec     string
+	name     string
+	every    time.Duration
+	jitter   float64 // the factor of every added at random to each wait
+	injector disruptionInjector
+}
+
+// disruptionArgs are the KEY=VALUE arguments of a disrupt option.
+type disruptionArgs map[string]string
+
+func (a disruptionArgs) get(key, defaultValue string) string {
+	if value, ok := a[key]; ok {
+		return value
+	}
+	return defaultValue
+}
+
+func (a disruptionArgs) duration(key string, defaultValue time.Duration) (time.Duration, error) {
+	value, ok := a[key]
+	if !ok {
+		return defaultValue, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("%s must be a positive duration, not %q", key, value)
+	}
+	return d, nil
+}
+
+// parseDisruption parses a disruption of the form NAME[:ARG,...] where each ARG is KEY=VALUE,
+// or a value for the positional argument of the injector. Every injector accepts every=DURATION
+// to wait between that and twice that long after each injection before the next.
+func parseDisruption(spec string) (*scheduledDisruption, error) {
+	parts := strings.SplitN(spec, ":", 2)
+	name := parts[0]
+	injectorType, ok := disruptionInjectors[name]
+	if !ok {
+		var names []string
+		for name := range disruptionInjectors {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("disrupt must be one of %s, not %q", strings.Join(names, ", "), name)
+	}
+	args := disruptionArgs{}
+	if len(parts) == 2 && len(parts[1]) > 0 {
+		for _, arg := range strings.Split(parts[1], ",") {
+			keyValue := strings.SplitN(arg, "=", 2)
+			if len(keyValue) == 1 {
+				if len(injectorType.positional) == 0 || len(args[injectorType.positional]) > 0 {
+					return nil, fmt.Errorf("disrupt %s: expected KEY=VALUE instead of %q", name, arg)
+				}
+				args[injectorType.positional] = arg
+				continue
+			}
+			key := keyValue[0]
+			if key != "every" && !stringsContain(injectorType.arguments, key) {
+				return nil, fmt.Errorf("disrupt %s: unrecognized argument %q, must be one of: every, %s", name, key, strings.Join(injectorType.arguments, ", "))
+			}
+			args[key] = keyValue[1]
+		}
+	}
+	every, err := args.duration("every", 5*time.Minute)
+	if err != nil {
+		return nil, fmt.Errorf("disrupt %s: %v", name, err)
+	}
+	injector, err := injectorType.build(args)
+	if err != nil {
+		return nil, fmt.Errorf("disrupt %s: %v", name, err)
+	}
+	return &scheduledDisruption{spec: spec, name: name, every: every, jitter: 1, injector: injector}, nil
+}
+
+func stringsContain(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// run injects the disruption repeatedly until the context is done.
+func (d *scheduledDisruption) run(ctx context.Context, kubeClient kubernetes.Interface) {
+	framework.Logf("Periodically injecting disruption %s", d.spec)
+	recorder := &disruptionRecorder{client: kubeClient, injector: d.name}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Duration(rand.Int31n(90)) * time.Second):
+		}
+		if err := d.injector.Inject(ctx, kubeClient, recorder); err != nil {
+			framework.Logf("Failed to inject disruption %s: %v", d.spec, err)
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait.Jitter(d.every, d.jitter)):
+		}
+	}
+}
+
+// disruptionRecorder records the start and end of a disruption as cluster events, which the
+// monitor turns into intervals covering each injected disruption.
+type disruptionRecorder struct {
+	client   kubernetes.Interface
+	injector string
+}
+
+func (r *disruptionRecorder) Started(target corev1.ObjectReference, format string, args ...interface{}) {
+	r.record(target, monitorapi.InjectedDisruptionStartedReason, fmt.Sprintf(format, args...))
+}
+
+func (r *disruptionRecorder) Ended(target corev1.ObjectReference, format string, args ...interface{}) {
+	r.record(target, monitorapi.InjectedDisruptionEndedReason, fmt.Sprintf(format, args...))
+}
+
+func (r *disruptionRecorder) record(target corev1.ObjectReference, reason, note string) {
+	note = fmt.Sprintf("%s/%s %s", monitorapi.AnnotationInjector, r.injector, note)
+	framework.Logf("DISRUPTION: %s %s/%s: %s", reason, strings.ToLower(target.Kind), target.Name, note)
+
+	// events about cluster scoped objects must be created in the default namespace
+	namespace := target.Namespace
+	if len(namespace) == 0 {
+		namespace = metav1.NamespaceDefault
+	}
+	currentTime := metav1.MicroTime{Time: time.Now()}
+	ctx, cancelFn := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelFn()
+	_, err := r.client.EventsV1().Events(namespace).Create(ctx, &eventsv1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: fmt.Sprintf("%s.%x", target.Name, currentTime.UnixNano()),
+		},
+		Regarding:           target,
+		Action:              "Disrupt",
+		Reason:              reason,
+		Note:                note,
+		Type:                corev1.EventTypeWarning,
+		EventTime:           currentTime,
+		ReportingController: "openshift-tests.openshift.io/upgrade",
+		ReportingInstance:   r.injector,
+	}, metav1.CreateOptions{})
+	if err != nil {
+		framework.Logf("Unable to record disruption event: %v", err)
+	}
+}
+
+func nodeReference(name string) corev1.ObjectReference {
+	return corev1.ObjectReference{Kind: "Node", Name: name, APIVersion: "v1"}
+}
+
+// randomNode returns the name of a random node with the given role.
+func randomNode(ctx context.Context, kubeClient kubernetes.Interface, role string) (string, error) {
+	nodes, err := kubeClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{LabelSelector: "node-role.kubernetes.io/" + role})
+	if err != nil {
+		return "", err
+	}
+	if len(nodes.Items) == 0 {
+		return "", fmt.Errorf("no nodes have the role %s", role)
+	}
+	return nodes.Items[rand.Intn(len(nodes.Items))].Name, nil
+}
+
+func isNodeReady(node *corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// rebootInjector reboots a random node with a role.
+type rebootInjector struct {
+	role string
+	hard bool
+}
+
+func (i *rebootInjector) Inject(ctx context.Context, kubeClient kubernetes.Interface, recorder *disruptionRecorder) error {
+	name, err := randomNode(ctx, kubeClient, i.role)
+	if err != nil {
+		return err
+	}
+	mode := "graceful"
+	if i.hard {
+		mode = "force"
+	}
+	recorder.Started(nodeReference(name), "triggered %s reboot", mode)
+	if err := triggerReboot(kubeClient, name, 0, i.hard); err != nil {
+		recorder.Ended(nodeReference(name), "failed to trigger reboot: %v", err)
+		return err
+	}
+
+	// the node is disrupted until it has gone down and come back
+	var wentDown bool
+	err = wait.PollImmediateUntil(10*time.Second, func() (bool, error) {
+		node, err := kubeClient.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, nil
+		}
+		if !isNodeReady(node) {
+			wentDown = true
+			return false, nil
+		}
+		return wentDown, nil
+	}, withTimeout(ctx, 20*time.Minute))
+	if err != nil {
+		recorder.Ended(nodeReference(name), "node did not reboot and become ready: %v", err)
+		return nil
+	}
+	recorder.Ended(nodeReference(name), "node rebooted and is ready")
+	return nil
+}
+
+// killPodInjector deletes a random running pod in a namespace without a grace period.
+type killPodInjector struct {
+	namespace string
+	selector  string
+}
+
+func (i *killPodInjector) Inject(ctx context.Context, kubeClient kubernetes.Interface, recorder *disruptionRecorder) error {
+	pods, err := kubeClient.CoreV1().Pods(i.namespace).List(ctx, metav1.ListOptions{LabelSelector: i.selector})
+	if err != nil {
+		return err
+	}
+	var running []corev1.Pod
+	for _, pod := range pods.Items {
+		if pod.Status.Phase == corev1.PodRunning && pod.DeletionTimestamp == nil {
+			running = append(running, pod)
+		}
+	}
+	if len(running) == 0 {
+		return fmt.Errorf("no running pods in namespace %s match %q", i.namespace, i.selector)
+	}
+	pod := running[rand.Intn(len(running))]
+	target := corev1.ObjectReference{Kind: "Pod", Namespace: pod.Namespace, Name: pod.Name, UID: pod.UID, APIVersion: "v1"}
+
+	recorder.Started(target, "deleting pod without a grace period")
+	zero := int64(0)
+	if err := kubeClient.CoreV1().Pods(pod.Namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{GracePeriodSeconds: &zero}); err != nil {
+		recorder.Ended(target, "failed to delete pod: %v", err)
+		return err
+	}
+
+	// the namespace is disrupted until the pods that replace the deleted pod are ready
+	err = wait.PollImmediateUntil(10*time.Second, func() (bool, error) {
+		pods, err := kubeClient.CoreV1().Pods(i.namespace).List(ctx, metav1.ListOptions{LabelSelector: i.selector})
+		if err != nil {
+			return false, nil
+		}
+		for _, p := range pods.Items {
+			if p.UID == pod.UID {
+				return false, nil
+			}
+			switch p.Status.Phase {
+			case corev1.PodSucceeded, corev1.PodFailed:
+				continue
+			case corev1.PodRunning:
+			default:
+				return false, nil
+			}
+			for _, condition := range p.Status.Conditions {
+				if condition.Type == corev1.PodReady && condition.Status != corev1.ConditionTrue {
+					return false, nil
+				}
+			}
+		}
+		return true, nil
+	}, withTimeout(ctx, 10*time.Minute))
+	if err != nil {
+		recorder.Ended(target, "pods in namespace %s did not become ready: %v", i.namespace, err)
+		return nil
+	}
+	recorder.Ended(target, "pod was deleted and pods in namespace %s are ready", i.namespace)
+	return nil
+}
+
+// cordonDrainInjector cordons a random node with a role, evicts its pods, and uncordons it
+// after a while.
+type cordonDrainInjector struct {
+	role     string
+	duration time.Duration
+}
+
+func (i *cordonDrainInjector) Inject(ctx context.Context, kubeClient kubernetes.Interface, recorder *disruptionRecorder) error {
+	name, err := randomNode(ctx, kubeClient, i.role)
+	if err != nil {
+		return err
+	}
+	recorder.Started(nodeReference(name), "cordoning and draining node for %s", i.duration)
+	if err := setUnschedulable(ctx, kubeClient, name, true); err != nil {
+		recorder.Ended(nodeReference(name), "failed to cordon node: %v", err)
+		return err
+	}
+	defer func() {
+		// uncordon even if the upgrade finished while the node was drained
+		if err := setUnschedulable(context.Background(), kubeClient, name, false); err != nil {
+			recorder.Ended(nodeReference(name), "failed to uncordon node: %v", err)
+			return
+		}
+		recorder.Ended(nodeReference(name), "uncordoned node")
+	}()
+
+	pods, err := kubeClient.CoreV1().Pods("").List(ctx, metav1.ListOptions{FieldSelector: "spec.nodeName=" + name})
+	if err != nil {
+		return err
+	}
+	for _, pod := range pods.Items {
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		if _, ok := pod.Annotations[corev1.MirrorPodAnnotationKey]; ok {
+			continue
+		}
+		if owner := metav1.GetControllerOf(&pod); owner != nil && owner.Kind == "DaemonSet" {
+			continue
+		}
+		// evictions blocked by a disruption budget are expected, and leave the pod running
+		if err := kubeClient.CoreV1().Pods(pod.Namespace).Evict(ctx, &policyv1beta1.Eviction{
+			ObjectMeta: metav1.ObjectMeta{Namespace: pod.Namespace, Name: pod.Name},
+		}); err != nil && !errors.IsNotFound(err) && !errors.IsTooManyRequests(err) {
+			framework.Logf("Unable to evict pod %s/%s from node %s: %v", pod.Namespace, pod.Name, name, err)
+		}
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(i.duration):
+	}
+	return nil
+}
+
+func setUnschedulable(ctx context.Context, kubeClient kubernetes.Interface, name string, unschedulable bool) error {
+	patch := []byte(fmt.Sprintf(`{"spec":{"unschedulable":%t}}`, unschedulable))
+	_, err := kubeClient.CoreV1().Nodes().Patch(ctx, name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+// deleteLeaderLeaseInjector deletes the leader election leases in a namespace, forcing the
+// components that hold them to elect a new leader.
+type deleteLeaderLeaseInjector struct {
+	namespace string
+	name      string
+}
+
+func (i *deleteLeaderLeaseInjector) Inject(ctx context.Context, kubeClient kubernetes.Interface, recorder *disruptionRecorder) error {
+	leases, err := kubeClient.CoordinationV1().Leases(i.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	var deleted []corev1.ObjectReference
+	for _, lease := range leases.Items {
+		if len(i.name) > 0 && lease.Name != i.name {
+			continue
+		}
+		target := corev1.ObjectReference{Kind: "Lease", Namespace: lease.Namespace, Name: lease.Name, APIVersion: "coordination.k8s.io/v1"}
+		var holder string
+		if lease.Spec.HolderIdentity != nil {
+			holder = *lease.Spec.HolderIdentity
+		}
+		recorder.Started(target, "deleting leader lease held by %s", holder)
+		if err := kubeClient.CoordinationV1().Leases(lease.Namespace).Delete(ctx, lease.Name, metav1.DeleteOptions{}); err != nil {
+			recorder.Ended(target, "failed to delete lease: %v", err)
+			continue
+		}
+		deleted = append(deleted, target)
+	}
+	if len(deleted) == 0 {
+		if len(i.name) > 0 {
+			return fmt.Errorf("no lease %s in namespace %s", i.name, i.namespace)
+		}
+		return fmt.Errorf("no leases in namespace %s", i.namespace)
+	}
+
+	// each lease is disrupted until a new leader acquires it
+	for _, target := range deleted {
+		err := wait.PollImmediateUntil(5*time.Second, func() (bool, error) {
+			lease, err := kubeClient.CoordinationV1().Leases(target.Namespace).Get(ctx, target.Name, metav1.GetOptions{})
+			if err != nil {
+				return false, nil
+			}
+			return lease.Spec.HolderIdentity != nil && len(*lease.Spec.HolderIdentity) > 0, nil
+		}, withTimeout(ctx, 5*time.Minute))
+		if err != nil {
+			recorder.Ended(target, "no leader acquired the lease: %v", err)
+			continue
+		}
+		recorder.Ended(target, "a leader acquired the lease")
+	}
+	return nil
+}
+
+// withTimeout returns a channel that is closed when the context is done or the timeout
+// passes, whichever is first.
+func withTimeout(ctx context.Context, timeout time.Duration) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		select {
+		case <-ctx.Done():
+		case <-time.After(timeout):
+		}
+	}()
+	return done
+}
@@ -5,10 +5,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"math/rand"
 	"strings"
 	"text/tabwriter"
-	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -175,35 +173,10 @@ func (m *versionMonitor) Describe(f *framework.Framework) {
 	}
 }
 
-func (m *versionMonitor) Disrupt(ctx context.Context, kubeClient kubernetes.Interface, rebootPolicy string) {
-	rebootHard := false
-	switch rebootPolicy {
-	case "graceful":
-		framework.Logf("Periodically reboot master nodes with clean shutdown")
-	case "force":
-		framework.Logf("Periodically reboot master nodes without allowing for clean shutdown")
-		rebootHard = true
-	case "":
-		return
-	}
-	for {
-		time.Sleep(time.Duration(rand.Int31n(90)) * time.Second)
-		if ctx.Err() != nil {
-			return
-		}
-		nodes, err := kubeClient.CoreV1().Nodes().List(context.Background(), metav1.ListOptions{LabelSelector: "node-role.kubernetes.io/master"})
-		if err != nil || len(nodes.Items) == 0 {
-			framework.Logf("Unable to find nodes to reboot: %v", err)
-			continue
-		}
-		rand.Shuffle(len(nodes.Items), func(i, j int) { nodes.Items[i], nodes.Items[j] = nodes.Items[j], nodes.Items[i] })
-		name := nodes.Items[0].Name
-		framework.Logf("DISRUPTION: Triggering reboot of %s", name)
-		if err := triggerReboot(kubeClient, name, 0, rebootHard); err != nil {
-			framework.Logf("Failed to reboot %s: %v", name, err)
-			continue
-		}
-		time.Sleep(wait.Jitter(5*time.Minute, 2))
+// Disrupt injects each disruption repeatedly in the background until the context is done.
+func (m *versionMonitor) Disrupt(ctx context.Context, kubeClient kubernetes.Interface, disruptions []*scheduledDisruption) {
+	for _, disruption := range disruptions {
+		go disruption.run(ctx, kubeClient)
 	}
 }
 
@@ -81,6 +81,7 @@ var (
 	upgradeTests               = []upgrades.Test{}
 	upgradeAbortAt             int
 	upgradeDisruptRebootPolicy string
+	upgradeDisruptions         []*scheduledDisruption
 )
 
 // upgradeAbortAtRandom is a special value indicating the abort should happen at a random percentage
@@ -110,11 +111,43 @@ func SetUpgradeDisruptReboot(policy string) error {
 	}
 }
 
+// AddUpgradeDisruption injects a disruption repeatedly during the upgrade. The disruption is
+// given as NAME[:ARG,...], where NAME is one of:
+//
+// * reboot[:role=ROLE,mode=graceful|force] - reboot a random node with the role (master by default)
+// * kill-pod:ns=NAMESPACE[,selector=SELECTOR] - delete a random running pod in the namespace without a grace period
+// * cordon-drain[:role=ROLE,for=DURATION] - cordon and drain a random node with the role (worker by default) for a while (2m by default)
+// * delete-leader-lease:NAMESPACE[,name=NAME] - delete the leader election leases in the namespace
+//
+// and every=DURATION sets the time to wait after each injection (5m by default).
+func AddUpgradeDisruption(spec string) error {
+	disruption, err := parseDisruption(spec)
+	if err != nil {
+		return err
+	}
+	upgradeDisruptions = append(upgradeDisruptions, disruption)
+	return nil
+}
+
+// disruptionsDuringUpgrade returns the disruptions to inject during an upgrade.
+func disruptionsDuringUpgrade() []*scheduledDisruption {
+	disruptions := upgradeDisruptions
+	if len(upgradeDisruptRebootPolicy) > 0 {
+		reboot, err := parseDisruption("reboot:role=master,mode=" + upgradeDisruptRebootPolicy)
+		if err != nil {
+			panic(err)
+		}
+		// disrupt-reboot has always waited between 5 and 15 minutes after each reboot
+		reboot.jitter = 2
+		disruptions = append([]*scheduledDisruption{reboot}, disruptions...)
+	}
+	return disruptions
+}
+
 // SetUpgradeAbortAt defines abort behavior during an upgrade. Allowed values are:
 //
 // * empty string - do not abort
 // * integer between 0-100 - once this percentage of operators have updated, rollback to the previous version
-//
 func SetUpgradeAbortAt(policy string) error {
 	if len(policy) == 0 {
 		upgradeAbortAt = 0
@@ -159,7 +192,8 @@ var _ = g.Describe("[sig-arch][Feature:ClusterUpgrade]", func() {
 			upgradeTests,
 			func() {
 				for i := 1; i < len(upgCtx.Versions); i++ {
-					framework.ExpectNoError(clusterUpgrade(f, client, dynamicClient, config, upgCtx.Versions[i]), fmt.Sprintf("during upgrade to %s", upgCtx.Versions[i].NodeImage))
+					hop := upgradeHop{index: i, count: len(upgCtx.Versions) - 1}
+					framework.ExpectNoError(clusterUpgrade(f, client, dynamicClient, config, upgCtx.Versions[i], hop), fmt.Sprintf("during upgrade to %s", upgCtx.Versions[i].NodeImage))
 				}
 			},
 		)
@@ -260,7 +294,20 @@ func getUpgradeContext(c configv1client.Interface, upgradeImage string) (*upgrad
 
 var errControlledAbort = fmt.Errorf("beginning abort")
 
-func clusterUpgrade(f *framework.Framework, c configv1client.Interface, dc dynamic.Interface, config *rest.Config, version upgrades.VersionContext) error {
+// upgradeHop identifies one of the sequential upgrades of the test.
+type upgradeHop struct {
+	index, count int
+}
+
+// testName distinguishes the tests each upgrade records when the test upgrades more than once.
+func (h upgradeHop) testName(name string) string {
+	if h.count < 2 {
+		return name
+	}
+	return fmt.Sprintf("%s (hop %d of %d)", name, h.index, h.count)
+}
+
+func clusterUpgrade(f *framework.Framework, c configv1client.Interface, dc dynamic.Interface, config *rest.Config, version upgrades.VersionContext, hop upgradeHop) error {
 	fmt.Fprintf(os.Stderr, "\n\n\n")
 	defer func() { fmt.Fprintf(os.Stderr, "\n\n\n") }()
 
@@ -303,6 +350,9 @@ func clusterUpgrade(f *framework.Framework, c configv1client.Interface, dc dynam
 		}
 	}
 
+	if hop.count > 1 {
+		framework.Logf("Starting upgrade %d of %d", hop.index, hop.count)
+	}
 	framework.Logf("Starting upgrade to version=%s image=%s attempt=%s", version.Version.String(), version.NodeImage, uid)
 	recordClusterEvent(kubeClient, uid, "Upgrade", "UpgradeStarted", fmt.Sprintf("version/%s image/%s", version.Version.String(), version.NodeImage), false)
 
@@ -336,7 +386,7 @@ func clusterUpgrade(f *framework.Framework, c configv1client.Interface, dc dynam
 	// trigger the update and record verification as an independent step
 	if err := disruption.RecordJUnit(
 		f,
-		"[sig-cluster-lifecycle] Cluster version operator acknowledges upgrade",
+		hop.testName("[sig-cluster-lifecycle] Cluster version operator acknowledges upgrade"),
 		func() error {
 			cv, err := c.ConfigV1().ClusterVersions().Get(context.Background(), "version", metav1.GetOptions{})
 			if err != nil {
@@ -380,15 +430,16 @@ func clusterUpgrade(f *framework.Framework, c configv1client.Interface, dc dynam
 		recordClusterEvent(kubeClient, uid, "Upgrade", "UpgradeFailed", fmt.Sprintf("failed to acknowledge version: %v", err), true)
 		return err
 	}
+	recordClusterEvent(kubeClient, uid, "Upgrade", "UpgradeAcknowledged", fmt.Sprintf("version/%s image/%s", version.Version.String(), version.NodeImage), false)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
-	go monitor.Disrupt(ctx, kubeClient, upgradeDisruptRebootPolicy)
+	monitor.Disrupt(ctx, kubeClient, disruptionsDuringUpgrade())
 
 	// observe the upgrade, taking action as necessary
 	if err := disruption.RecordJUnit(
 		f,
-		"[sig-cluster-lifecycle] Cluster completes upgrade",
+		hop.testName("[sig-cluster-lifecycle] Cluster completes upgrade"),
 		func() error {
 			framework.Logf("Cluster version operator acknowledged upgrade request")
 			aborted := false
@@ -446,7 +497,7 @@ func clusterUpgrade(f *framework.Framework, c configv1client.Interface, dc dynam
 			// record whether the cluster was fast or slow upgrading.  Don't fail the test, we still want signal on the actual tests themselves.
 			upgradeEnded := time.Now()
 			upgradeDuration := upgradeEnded.Sub(upgradeStarted)
-			testCaseName := fmt.Sprintf("[sig-cluster-lifecycle] cluster upgrade should complete in %0.2f minutes", durationToSoftFailure.Minutes())
+			testCaseName := hop.testName(fmt.Sprintf("[sig-cluster-lifecycle] cluster upgrade should complete in %0.2f minutes", durationToSoftFailure.Minutes()))
 			failure := ""
 			if upgradeDuration > durationToSoftFailure {
 				failure = fmt.Sprintf("%s to %s took too long: %0.2f minutes", action, versionString(desired), upgradeDuration.Minutes())
@@ -463,7 +514,7 @@ func clusterUpgrade(f *framework.Framework, c configv1client.Interface, dc dynam
 	var errMasterUpdating error
 	if err := disruption.RecordJUnit(
 		f,
-		"[sig-mco] Machine config pools complete upgrade",
+		hop.testName("[sig-mco] Machine config pools complete upgrade"),
 		func() error {
 			framework.Logf("Waiting on pools to be upgraded")
 			if err := wait.PollImmediate(10*time.Second, 30*time.Minute, func() (bool, error) {
@@ -499,6 +550,7 @@ func clusterUpgrade(f *framework.Framework, c configv1client.Interface, dc dynam
 		recordClusterEvent(kubeClient, uid, "Upgrade", "UpgradeFailed", fmt.Sprintf("failed to upgrade nodes: %v", err), true)
 		return err
 	}
+	recordClusterEvent(kubeClient, uid, "Upgrade", "UpgradePoolsComplete", fmt.Sprintf("version/%s image/%s", updated.Status.Desired.Version, updated.Status.Desired.Image), false)
 
 	if errMasterUpdating != nil {
 		recordClusterEvent(kubeClient, uid, "Upgrade", "UpgradeFailed", fmt.Sprintf("master was updating after cluster version reached level: %v", errMasterUpdating), true)
@@ -507,7 +559,7 @@ func clusterUpgrade(f *framework.Framework, c configv1client.Interface, dc dynam
 
 	if err := disruption.RecordJUnit(
 		f,
-		"[sig-cluster-lifecycle] ClusterOperators are available and not degraded after upgrade",
+		hop.testName("[sig-cluster-lifecycle] ClusterOperators are available and not degraded after upgrade"),
 		func() error {
 			if err := operator.WaitForOperatorsToSettle(context.TODO(), c); err != nil {
 				return err
@@ -53286,6 +53286,9 @@ var _e2echartE2eChartTemplateHtml = []byte(`<html lang="en">
         if (eventInterval.locator.includes(" route/")) {
             return true
         }
+        if (eventInterval.locator.startsWith("disruption/")) {
+            return true
+        }
         return false
 
     }
@@ -53310,6 +53313,14 @@ var _e2echartE2eChartTemplateHtml = []byte(`<html lang="en">
         return false
     }
 
+    function isPodState(eventInterval) {
+        // every pod is running for most of the run, so only show the other states
+        if (eventInterval.message.startsWith("reason/PodPhase ")) {
+            return !eventInterval.message.includes("phase/Running")
+        }
+        return eventInterval.message.startsWith("reason/ContainerNotReady") || eventInterval.message.startsWith("reason/ContainerBackOff")
+    }
+
     function isAlert(eventInterval) {
         if (eventInterval.locator.startsWith("alert/")) {
             return true
@@ -53339,6 +53350,17 @@ var _e2echartE2eChartTemplateHtml = []byte(`<html lang="en">
         return [item.locator, ` + "`" + ` (${roles},updates)` + "`" + `, "Update"];
     }
 
+    function podStateValue(item) {
+        let m = item.message.match(rePhase);
+        if (m && item.message.startsWith("reason/PodPhase ")) {
+            return [item.locator, "", "Pod" + m[2]];
+        }
+        if (item.message.startsWith("reason/ContainerBackOff")) {
+            return [item.locator, "", "ContainerBackOff"];
+        }
+        return [item.locator, "", "ContainerNotReady"];
+    }
+
     function alertSeverity(item) {
         // the other types can be pending, so check pending first
         let pendingIndex = item.message.indexOf("pending")
@@ -53417,94 +53439,103 @@ var _e2echartE2eChartTemplateHtml = []byte(`<html lang="en">
 
     var loc = window.location.href;
 
-    var timelineGroups = []
-    timelineGroups.push({group: "operator-unavailable", data: []})
-    createTimelineData("OperatorUnavailable", timelineGroups[timelineGroups.length - 1].data, eventIntervals, isOperatorAvailable)
-
-    timelineGroups.push({group: "operator-degraded", data: []})
-    createTimelineData("OperatorDegraded", timelineGroups[timelineGroups.length - 1].data, eventIntervals, isOperatorDegraded)
-
-    timelineGroups.push({group: "operator-progressing", data: []})
-    createTimelineData("OperatorProgressing", timelineGroups[timelineGroups.length - 1].data, eventIntervals, isOperatorProgressing)
-
-    timelineGroups.push({group: "alerts", data: []})
-    createTimelineData(alertSeverity, timelineGroups[timelineGroups.length - 1].data, eventIntervals, isAlert)
-    // leaving this for posterity so future me (or someone else) can try it, but I think ordering by name makes the
-    // patterns shown by timing hide and timing appears more relevant to my eyes.
-    // sort alerts alphabetically for display purposes, but keep the json itself ordered by time.
-    // timelineGroups[timelineGroups.length - 1].data.sort(function (e1 ,e2){
-    //     if (e1.label.includes("alert") && e2.label.includes("alert")) {
-    //         return e1.label < e2.label ? -1 : e1.label > e2.label;
-    //     }
-    //     return 0
-    // })
-
-    timelineGroups.push({group: "node-state", data: []})
-    createTimelineData(nodeStateValue, timelineGroups[timelineGroups.length - 1].data, eventIntervals, isNodeState)
-    timelineGroups[timelineGroups.length - 1].data.sort(function (e1 ,e2){
-        if (e1.label.includes("master") && e2.label.includes("worker")) {
-            return -1
-        }
-        return 0
-    })
-
-    timelineGroups.push({group: "apiserver-availability", data: []})
-    createTimelineData("Failed", timelineGroups[timelineGroups.length - 1].data, eventIntervals, isAPIServerConnectivity)
-
-    timelineGroups.push({group: "endpoint-availability", data: []})
-    createTimelineData("Failed", timelineGroups[timelineGroups.length - 1].data, eventIntervals, isEndpointConnectivity)
-
-    timelineGroups.push({group: "e2e-test-failed", data: []})
-    createTimelineData("Failed", timelineGroups[timelineGroups.length - 1].data, eventIntervals, isE2EFailed)
-
-    timelineGroups.push({group: "e2e-test-flaked", data: []})
-    createTimelineData("Flaked", timelineGroups[timelineGroups.length - 1].data, eventIntervals, isE2EFlaked)
-
-    timelineGroups.push({group: "e2e-test-passed", data: []})
-    createTimelineData("Passed", timelineGroups[timelineGroups.length - 1].data, eventIntervals, isE2EPassed)
-
-    var segmentFunc = function (segment) {
-        // for (var i in data) {
-        //     if (data[i].group == segment.group) {
-        //         var groupdata = data[i].data
-        //         for (var j in groupdata) {
-        //             if (groupdata[j].label == segment.label) {
-        //                 labeldata = groupdata[j].data
-        //                 for (var k in labeldata) {
-        //                     var startDate = new Date(labeldata[k].timeRange[0])
-        //                     var endDate = new Date(labeldata[k].timeRange[1])
-        //                     if (startDate.getTime() == segment.timeRange[0].getTime() &&
-        //                         endDate.getTime() == segment.timeRange[1].getTime()) {
-        //                         $('#myModalContent').text(labeldata[k].extended)
-        //                         $('#myModal').modal()
-        //                     }
-        //                 }
-        //             }
-        //         }
+    function renderChart(eventIntervals) {
+        var timelineGroups = []
+        timelineGroups.push({group: "operator-unavailable", data: []})
+        createTimelineData("OperatorUnavailable", timelineGroups[timelineGroups.length - 1].data, eventIntervals, isOperatorAvailable)
+
+        timelineGroups.push({group: "operator-degraded", data: []})
+        createTimelineData("OperatorDegraded", timelineGroups[timelineGroups.length - 1].data, eventIntervals, isOperatorDegraded)
+
+        timelineGroups.push({group: "operator-progressing", data: []})
+        createTimelineData("OperatorProgressing", timelineGroups[timelineGroups.length - 1].data, eventIntervals, isOperatorProgressing)
+
+        timelineGroups.push({group: "alerts", data: []})
+        createTimelineData(alertSeverity, timelineGroups[timelineGroups.length - 1].data, eventIntervals, isAlert)
+        // leaving this for posterity so future me (or someone else) can try it, but I think ordering by name makes the
+        // patterns shown by timing hide and timing appears more relevant to my eyes.
+        // sort alerts alphabetically for display purposes, but keep the json itself ordered by time.
+        // timelineGroups[timelineGroups.length - 1].data.sort(function (e1 ,e2){
+        //     if (e1.label.includes("alert") && e2.label.includes("alert")) {
+        //         return e1.label < e2.label ? -1 : e1.label > e2.label;
         //     }
-        // }
+        //     return 0
+        // })
+
+        timelineGroups.push({group: "node-state", data: []})
+        createTimelineData(nodeStateValue, timelineGroups[timelineGroups.length - 1].data, eventIntervals, isNodeState)
+        timelineGroups[timelineGroups.length - 1].data.sort(function (e1 ,e2){
+            if (e1.label.includes("master") && e2.label.includes("worker")) {
+                return -1
+            }
+            return 0
+        })
+
+        timelineGroups.push({group: "pod-state", data: []})
+        createTimelineData(podStateValue, timelineGroups[timelineGroups.length - 1].data, eventIntervals, isPodState)
+
+        timelineGroups.push({group: "apiserver-availability", data: []})
+        createTimelineData("Failed", timelineGroups[timelineGroups.length - 1].data, eventIntervals, isAPIServerConnectivity)
+
+        timelineGroups.push({group: "endpoint-availability", data: []})
+        createTimelineData("Failed", timelineGroups[timelineGroups.length - 1].data, eventIntervals, isEndpointConnectivity)
+
+        timelineGroups.push({group: "e2e-test-failed", data: []})
+        createTimelineData("Failed", timelineGroups[timelineGroups.length - 1].data, eventIntervals, isE2EFailed)
+
+        timelineGroups.push({group: "e2e-test-flaked", data: []})
+        createTimelineData("Flaked", timelineGroups[timelineGroups.length - 1].data, eventIntervals, isE2EFlaked)
+
+        timelineGroups.push({group: "e2e-test-passed", data: []})
+        createTimelineData("Passed", timelineGroups[timelineGroups.length - 1].data, eventIntervals, isE2EPassed)
+
+        var segmentFunc = function (segment) {
+            // for (var i in data) {
+            //     if (data[i].group == segment.group) {
+            //         var groupdata = data[i].data
+            //         for (var j in groupdata) {
+            //             if (groupdata[j].label == segment.label) {
+            //                 labeldata = groupdata[j].data
+            //                 for (var k in labeldata) {
+            //                     var startDate = new Date(labeldata[k].timeRange[0])
+            //                     var endDate = new Date(labeldata[k].timeRange[1])
+            //                     if (startDate.getTime() == segment.timeRange[0].getTime() &&
+            //                         endDate.getTime() == segment.timeRange[1].getTime()) {
+            //                         $('#myModalContent').text(labeldata[k].extended)
+            //                         $('#myModal').modal()
+            //                     }
+            //                 }
+            //             }
+            //         }
+            //     }
+            // }
+        }
+
+        const el = document.querySelector('#chart');
+        const myChart = TimelinesChart();
+        var ordinalScale = d3.scaleOrdinal()
+            .domain([
+                'AlertInfo', 'AlertPending', 'AlertWarning', 'AlertCritical', // alerts
+                'OperatorUnavailable', 'OperatorDegraded', 'OperatorProgressing', // operators
+                'Update', 'Drain', 'Reboot', 'OperatingSystemUpdate', 'NodeNotReady', // nodes
+                'PodPending', 'PodTerminating', 'ContainerNotReady', 'ContainerBackOff', // pods
+                'Passed', 'Skipped', 'Flaked', 'Failed',  // tests
+                'Degraded', 'Upgradeable', 'False', 'Unknown'])
+            .range([
+                '#fada5e','#fada5e','#ffa500','#d0312d',  // alerts
+                '#d0312d', '#ffa500', '#fada5e', // operators
+                '#1e7bd9', '#4294e6', '#6aaef2', '#96cbff', '#fada5e', // nodes
+                '#96cbff', '#6aaef2', '#ffa500', '#d0312d', // pods
+                '#3cb043', '#ceba76', '#ffa500', '#d0312d', // tests
+                '#b65049', '#32b8b6', '#ffffff', '#bbbbbb']);
+        myChart.data(timelineGroups).zQualitative(true).enableAnimations(false).leftMargin(240).rightMargin(550).maxLineHeight(20).maxHeight(10000).zColorScale(ordinalScale).onSegmentClick(segmentFunc)
+        (el);
+
+        // force a minimum width for smaller devices (which otherwise get an unusable display)
+        setTimeout(() => { if (myChart.width() < 1300) { myChart.width(1300) }}, 1)
     }
 
-    const el = document.querySelector('#chart');
-    const myChart = TimelinesChart();
-    var ordinalScale = d3.scaleOrdinal()
-        .domain([
-            'AlertInfo', 'AlertPending', 'AlertWarning', 'AlertCritical', // alerts
-            'OperatorUnavailable', 'OperatorDegraded', 'OperatorProgressing', // operators
-            'Update', 'Drain', 'Reboot', 'OperatingSystemUpdate', 'NodeNotReady', // nodes
-            'Passed', 'Skipped', 'Flaked', 'Failed',  // tests
-            'Degraded', 'Upgradeable', 'False', 'Unknown'])
-        .range([
-            '#fada5e','#fada5e','#ffa500','#d0312d',  // alerts
-            '#d0312d', '#ffa500', '#fada5e', // operators
-            '#1e7bd9', '#4294e6', '#6aaef2', '#96cbff', '#fada5e', // nodes
-            '#3cb043', '#ceba76', '#ffa500', '#d0312d', // tests
-            '#b65049', '#32b8b6', '#ffffff', '#bbbbbb']);
-    myChart.data(timelineGroups).zQualitative(true).enableAnimations(false).leftMargin(240).rightMargin(550).maxLineHeight(20).maxHeight(10000).zColorScale(ordinalScale).onSegmentClick(segmentFunc)
-    (el);
-
-    // force a minimum width for smaller devices (which otherwise get an unusable display)
-    setTimeout(() => { if (myChart.width() < 1300) { myChart.width(1300) }}, 1)
+    renderChart(eventIntervals)
 </script>
 </body>
 </html>